@@ -0,0 +1,131 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package azqr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/cmendible/azqr/internal/scanners"
+	pkgscanners "github.com/cmendible/azqr/pkg/scanners"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(countCmd)
+	countCmd.Flags().StringP("subscription-id", "s", "", "Azure Subscription Id. All subscriptions are counted if not set")
+	countCmd.Flags().StringP("output", "o", "table", "Output format: table or json")
+}
+
+// serviceResourceCount - Number of resources of a resource type scanned by a service
+type serviceResourceCount struct {
+	Service   string `json:"service"`
+	Type      string `json:"type"`
+	Resources int    `json:"resources"`
+}
+
+var countCmd = &cobra.Command{
+	Use:   "count",
+	Short: "Print the number of resources of each supported type",
+	Long:  "Use Azure Resource Graph to print the number of resources of each type supported by azqr, to see how big a scan will be",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		subscriptionID, _ := cmd.Flags().GetString("subscription-id")
+		output, _ := cmd.Flags().GetString("output")
+		if output != "table" && output != "json" {
+			log.Fatalf("Unsupported output %s, use table or json", output)
+		}
+
+		cred, err := azidentity.NewDefaultAzureCredential(nil)
+		if err != nil {
+			log.Fatal(err)
+		}
+		ctx := context.Background()
+
+		subscriptions := []string{}
+		if subscriptionID != "" {
+			subscriptions = append(subscriptions, subscriptionID)
+		} else {
+			subs, err := listSubscriptions(ctx, cred, nil)
+			if err != nil {
+				log.Fatal(err)
+			}
+			for _, s := range subs {
+				subscriptions = append(subscriptions, *s.SubscriptionID)
+			}
+		}
+
+		graph := scanners.ResourceGraph{}
+		err = graph.Init(&scanners.ScannerConfig{
+			Ctx:  ctx,
+			Cred: cred,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		counts, err := getResourceCounts(func(types []string) (map[string]int, error) {
+			return graph.CountResourcesByType(subscriptions, types)
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		switch output {
+		case "json":
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(counts); err != nil {
+				log.Fatal(err)
+			}
+		case "table":
+			total := 0
+			fmt.Fprintln(cmd.OutOrStdout(), "Service | Type | Resources")
+			fmt.Fprintln(cmd.OutOrStdout(), "---|---|---")
+			for _, c := range counts {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s | %s | %d\n", c.Service, c.Type, c.Resources)
+				total += c.Resources
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Total | | %d\n", total)
+		}
+	},
+}
+
+// getResourceCounts - Returns the number of resources of each type of every service in the scanner registry.
+// count returns the number of resources of the given types, keyed by lower case type as Resource Graph does.
+func getResourceCounts(count func(types []string) (map[string]int, error)) ([]serviceResourceCount, error) {
+	services := pkgscanners.Services()
+	serviceTypes := make([][]string, 0, len(services))
+	types := []string{}
+	for _, service := range services {
+		s, err := pkgscanners.NewScanner(service)
+		if err != nil {
+			return nil, err
+		}
+		t := s.GetResourceTypes()
+		serviceTypes = append(serviceTypes, t)
+		types = append(types, t...)
+	}
+
+	counts, err := count(types)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []serviceResourceCount{}
+	for i, service := range services {
+		for _, t := range serviceTypes[i] {
+			result = append(result, serviceResourceCount{
+				Service:   service,
+				Type:      t,
+				Resources: counts[strings.ToLower(t)],
+			})
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,55 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package azqr
+
+import (
+	"errors"
+	"testing"
+
+	pkgscanners "github.com/cmendible/azqr/pkg/scanners"
+)
+
+func TestGetResourceCounts(t *testing.T) {
+	requested := []string{}
+	counts, err := getResourceCounts(func(types []string) (map[string]int, error) {
+		requested = types
+		return map[string]int{
+			"microsoft.containerservice/managedclusters": 2,
+			"microsoft.storage/storageaccounts":          5,
+		}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(counts) != len(requested) {
+		t.Errorf("getResourceCounts() returned %d types, want %d", len(counts), len(requested))
+	}
+
+	want := map[string]int{
+		"aks": 2,
+		"st":  5,
+	}
+	got := map[string]int{}
+	for _, c := range counts {
+		got[c.Service] += c.Resources
+	}
+	for service, resources := range got {
+		if resources != want[service] {
+			t.Errorf("%s resources = %d, want %d", service, resources, want[service])
+		}
+	}
+	if len(got) != len(pkgscanners.Services()) {
+		t.Errorf("getResourceCounts() returned %d services, want %d", len(got), len(pkgscanners.Services()))
+	}
+}
+
+func TestGetResourceCounts_Error(t *testing.T) {
+	_, err := getResourceCounts(func(types []string) (map[string]int, error) {
+		return nil, errors.New("throttled")
+	})
+	if err == nil {
+		t.Error("getResourceCounts() should return the count error")
+	}
+}
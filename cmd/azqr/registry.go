@@ -0,0 +1,38 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package azqr
+
+import (
+	"strings"
+
+	"github.com/cmendible/azqr/internal/scanners"
+	pkgscanners "github.com/cmendible/azqr/pkg/scanners"
+)
+
+// getScanners - Returns new scanners for the given service codes, or for every supported service if none are given
+func getScanners(services []string) ([]scanners.IAzureScanner, error) {
+	selected := map[string]bool{}
+	for _, s := range services {
+		selected[strings.ToLower(strings.TrimSpace(s))] = true
+	}
+
+	result := []scanners.IAzureScanner{}
+	for _, service := range pkgscanners.Services() {
+		if len(selected) == 0 || selected[service] {
+			s, err := pkgscanners.NewScanner(service)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, s)
+			delete(selected, service)
+		}
+	}
+
+	for s := range selected {
+		if _, err := pkgscanners.NewScanner(s); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,35 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package azqr
+
+import (
+	"log"
+
+	"github.com/cmendible/azqr/internal/renderers"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.AddCommand(reportSchemaCmd)
+}
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Information about the azqr report formats",
+	Long:  "Information about the azqr report formats",
+	Args:  cobra.NoArgs,
+}
+
+var reportSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of the json report",
+	Long:  "Print the JSON Schema of the report written with --output-format json",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if _, err := cmd.OutOrStdout().Write(renderers.ReportSchema); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
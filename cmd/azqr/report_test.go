@@ -0,0 +1,29 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package azqr
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestReportSchemaCmd(t *testing.T) {
+	out := &bytes.Buffer{}
+	rootCmd.SetOut(out)
+	rootCmd.SetArgs([]string{"report", "schema"})
+	defer rootCmd.SetArgs(nil)
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	schema := map[string]interface{}{}
+	if err := json.Unmarshal(out.Bytes(), &schema); err != nil {
+		t.Fatalf("report schema output is not valid JSON: %s", err)
+	}
+	if schema["$schema"] == nil {
+		t.Errorf("report schema output %v should declare $schema", schema)
+	}
+}
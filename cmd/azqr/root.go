@@ -7,8 +7,11 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// Build metadata, injected with -ldflags "-X 'github.com/cmendible/azqr/cmd/azqr.version=...'"
 var (
 	version = "dev"
+	commit  = "none"
+	date    = "unknown"
 )
 
 var rootCmd = &cobra.Command{
@@ -0,0 +1,71 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package azqr
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	pkgscanners "github.com/cmendible/azqr/pkg/scanners"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rulesCmd.AddCommand(rulesSummaryCmd)
+	rulesSummaryCmd.Flags().StringP("output", "o", "table", "Output format: table or json")
+}
+
+// serviceRulesSummary - Number of rules of a service, and how many of them there are for each severity
+type serviceRulesSummary struct {
+	Service    string         `json:"service"`
+	Rules      int            `json:"rules"`
+	Severities map[string]int `json:"severities"`
+}
+
+var rulesSummaryCmd = &cobra.Command{
+	Use:   "summary",
+	Short: "Print the number of rules of each service",
+	Long:  "Print the number of rules of each supported service and their severity breakdown",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		summary := getRulesSummary()
+
+		switch output {
+		case "json":
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(summary); err != nil {
+				log.Fatal(err)
+			}
+		case "table":
+			fmt.Fprintln(cmd.OutOrStdout(), "Service | Rules | High | Medium | Low")
+			fmt.Fprintln(cmd.OutOrStdout(), "---|---|---|---|---")
+			for _, s := range summary {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s | %d | %d | %d | %d\n", s.Service, s.Rules, s.Severities["High"], s.Severities["Medium"], s.Severities["Low"])
+			}
+		default:
+			log.Fatalf("Unsupported output %s, use table or json", output)
+		}
+	},
+}
+
+// getRulesSummary - Returns the rules summary of every service in the scanner registry
+func getRulesSummary() []serviceRulesSummary {
+	summary := []serviceRulesSummary{}
+	for _, service := range pkgscanners.Services() {
+		s := serviceRulesSummary{
+			Service:    service,
+			Severities: map[string]int{},
+		}
+		scanner, _ := pkgscanners.NewScanner(service)
+		for _, rule := range scanner.GetRules() {
+			s.Rules++
+			s.Severities[rule.Severity]++
+		}
+		summary = append(summary, s)
+	}
+	return summary
+}
@@ -0,0 +1,58 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package azqr
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/cmendible/azqr/internal/scanners/aks"
+	"github.com/cmendible/azqr/internal/scanners/plan"
+	pkgscanners "github.com/cmendible/azqr/pkg/scanners"
+)
+
+func TestRulesSummaryCmd(t *testing.T) {
+	out := &bytes.Buffer{}
+	rootCmd.SetOut(out)
+	rootCmd.SetArgs([]string{"rules", "summary", "--output", "json"})
+	defer rootCmd.SetArgs(nil)
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	summary := []serviceRulesSummary{}
+	if err := json.Unmarshal(out.Bytes(), &summary); err != nil {
+		t.Fatal(err)
+	}
+	if len(summary) != len(pkgscanners.Services()) {
+		t.Errorf("rules summary has %d services, want %d", len(summary), len(pkgscanners.Services()))
+	}
+
+	want := map[string]int{
+		"aks":  len((&aks.AKSScanner{}).GetRules()),
+		"plan": len((&plan.AppServiceScanner{}).GetRules()),
+	}
+	for _, s := range summary {
+		rules, ok := want[s.Service]
+		if !ok {
+			continue
+		}
+		if s.Rules != rules {
+			t.Errorf("%s rules = %d, want %d", s.Service, s.Rules, rules)
+		}
+		bySeverity := 0
+		for _, c := range s.Severities {
+			bySeverity += c
+		}
+		if bySeverity != rules {
+			t.Errorf("%s rules by severity = %d, want %d", s.Service, bySeverity, rules)
+		}
+		delete(want, s.Service)
+	}
+	if len(want) > 0 {
+		t.Errorf("rules summary is missing %v", want)
+	}
+}
@@ -5,34 +5,19 @@ package azqr
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"os"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cmendible/azqr/internal/scanners"
-	"github.com/cmendible/azqr/internal/scanners/afd"
-	"github.com/cmendible/azqr/internal/scanners/afw"
-	"github.com/cmendible/azqr/internal/scanners/agw"
-	"github.com/cmendible/azqr/internal/scanners/aks"
-	"github.com/cmendible/azqr/internal/scanners/apim"
-	"github.com/cmendible/azqr/internal/scanners/appcs"
-	"github.com/cmendible/azqr/internal/scanners/cae"
-	"github.com/cmendible/azqr/internal/scanners/ci"
-	"github.com/cmendible/azqr/internal/scanners/cosmos"
-	"github.com/cmendible/azqr/internal/scanners/cr"
-	"github.com/cmendible/azqr/internal/scanners/evgd"
 	"github.com/cmendible/azqr/internal/scanners/evh"
-	"github.com/cmendible/azqr/internal/scanners/kv"
-	"github.com/cmendible/azqr/internal/scanners/mysql"
-	"github.com/cmendible/azqr/internal/scanners/plan"
-	"github.com/cmendible/azqr/internal/scanners/psql"
-	"github.com/cmendible/azqr/internal/scanners/redis"
-	"github.com/cmendible/azqr/internal/scanners/sb"
-	"github.com/cmendible/azqr/internal/scanners/sigr"
-	"github.com/cmendible/azqr/internal/scanners/sql"
-	"github.com/cmendible/azqr/internal/scanners/st"
-	"github.com/cmendible/azqr/internal/scanners/wps"
+	pkgscanners "github.com/cmendible/azqr/pkg/scanners"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
@@ -41,18 +26,51 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/subscription/armsubscription"
 	"github.com/cmendible/azqr/internal/renderers"
+	"github.com/cmendible/azqr/internal/tui"
 	"github.com/spf13/cobra"
-	"golang.org/x/sync/semaphore"
 )
 
 func init() {
 	scanCmd.PersistentFlags().StringP("subscription-id", "s", "", "Azure Subscription Id")
 	scanCmd.PersistentFlags().StringP("resource-group", "g", "", "Azure Resource Group (Use with --subscription-id)")
+	scanCmd.PersistentFlags().String("tag", "", "Only scan resources with the given tag, of any supported type (e.g. env=prod or env)")
 	scanCmd.PersistentFlags().BoolP("defender", "d", true, "Scan Defender Status")
 	scanCmd.PersistentFlags().BoolP("advisor", "a", true, "Scan Azure Advisor Recommendations")
+	scanCmd.PersistentFlags().Bool("with-advisor", false, "Enrich each scanned resource with the categories of its Azure Advisor Recommendations")
 	scanCmd.PersistentFlags().StringP("output-prefix", "o", "azqr_report", "Output file prefix")
 	scanCmd.PersistentFlags().BoolP("mask", "m", true, "Mask the subscription id in the report")
+	scanCmd.PersistentFlags().Bool("with-metrics", false, "Read the CPU and memory usage of the last 7 days from Azure Monitor to find over-sized App Service Plans")
+	scanCmd.PersistentFlags().Bool("anonymize", false, "Replace subscription ids, resource groups, resource names and ids with stable hashes in the report")
 	scanCmd.PersistentFlags().BoolP("parallel-processes", "p", true, "Use parallel processes to run scans")
+	scanCmd.PersistentFlags().Int("concurrency", 0, "Maximum number of scanners running at the same time across Resource Groups (with --parallel-processes). 0 means GOMAXPROCS*4")
+	scanCmd.PersistentFlags().StringSlice("allowed-locations", []string{}, "Comma separated list of allowed locations (e.g. westeurope,northeurope). Resources in other locations are flagged")
+	scanCmd.PersistentFlags().String("caf-prefixes", "", "YAML file mapping resource abbreviations to the name prefixes accepted by the CAF naming rules (e.g. aks: [aks, k8s])")
+	scanCmd.PersistentFlags().Int("max-resource-age", 0, "Flag resources created more than this number of days ago, using their system data or a createdOn tag, to help find stale resources (0 disables the check)")
+	scanCmd.PersistentFlags().StringArray("tag-policy", []string{}, "Tag value policy as key=regex (e.g. costcenter=^CC\\d{4}$). Resources whose tag values don't match are flagged. Can be repeated")
+	scanCmd.PersistentFlags().Int("min-retention-days", scanners.DefaultMinRetentionDays, "Minimum log retention, in days, for diagnostic settings sending logs to a storage account")
+	scanCmd.PersistentFlags().Int("consumer-groups-threshold", evh.DefaultConsumerGroupsThreshold, "Number of consumer groups per event hub considered too high")
+	scanCmd.PersistentFlags().Bool("production-severity", false, "Lower the severity of rules meant for production resources (e.g. aks-005) when the resource isn't tagged as production (e.g. env=prod). Production resources are called out in the rule result either way")
+	scanCmd.PersistentFlags().Int("limit", 0, "Maximum number of resources evaluated per scanner and Resource Group, useful for quick smoke tests. 0 means no limit")
+	scanCmd.PersistentFlags().Bool("estimate-calls", false, "Print the approximate number of ARM calls the scan would make, based on Resource Graph counts, without scanning")
+	scanCmd.PersistentFlags().Bool("stream", false, "Stream the findings to a JSON lines file as they are produced, instead of building the Excel report in memory")
+	scanCmd.PersistentFlags().Bool("log-analytics", false, "Also write the findings as flat records ready for Log Analytics custom table ingestion")
+	scanCmd.PersistentFlags().String("fail-on", "", "Exit with a non zero code if rules of this severity or higher are broken (high, medium or low): 4 for High, 3 for Medium and 2 for Low")
+	scanCmd.PersistentFlags().StringToInt("max-findings", map[string]int{}, "Exit with code 5 if the broken rules of a category exceed its threshold (e.g. Security=0,Governance=5)")
+	scanCmd.PersistentFlags().Bool("remediation", false, "Also write the remediation backlog, ordered by severity and number of resources affected, as JSON")
+	scanCmd.PersistentFlags().StringSlice("output-format", []string{}, "Comma separated list of additional report formats to write (e.g. json,csv,md)")
+	scanCmd.PersistentFlags().StringSlice("output", []string{}, "Comma separated list of additional report formats to write")
+	_ = scanCmd.PersistentFlags().MarkDeprecated("output", "use --output-format instead")
+	scanCmd.PersistentFlags().String("output-bundle", "", "Also write the selected report formats into a single ZIP file (e.g. report.zip)")
+	scanCmd.PersistentFlags().Bool("show-passed", false, "Show passed rules in the report, not only the broken ones")
+	scanCmd.PersistentFlags().Bool("no-color", false, "Disable colors in the console output")
+	scanCmd.PersistentFlags().Bool("cache-rules", false, "Reuse the results of the rules that call Azure APIs when a resource is evaluated again, e.g. by a retried scanner")
+	scanCmd.PersistentFlags().Bool("tui", false, "Browse the findings in an interactive terminal UI when the scan completes")
+	scanCmd.PersistentFlags().Bool("fail-fast", false, "Abort the scan on the first scanner error. By default failed scanners are skipped and their errors reported at the end of the scan")
+	scanCmd.PersistentFlags().Duration("timeout", 0, "Maximum duration of the whole scan (e.g. 30m). 0 means no timeout")
+	scanCmd.PersistentFlags().Duration("scanner-timeout", 0, "Maximum duration of each scanner in a resource group, after which the scanner is abandoned and its results discarded. Defaults to half of --timeout, so a hung scanner is skipped before the whole scan times out")
+	scanCmd.PersistentFlags().String("checkpoint", "", "Record completed scans in this checkpoint file, so an interrupted scan can be resumed with --resume. The file is removed when the scan completes")
+	scanCmd.PersistentFlags().String("resume", "", "Resume an interrupted scan from a checkpoint file, recording the remaining scans in the same file")
+	scanCmd.Flags().StringSlice("services", []string{}, "Comma separated list of services to scan (e.g. aks,evh). Services without resources are skipped")
 	rootCmd.AddCommand(scanCmd)
 }
 
@@ -62,31 +80,10 @@ var scanCmd = &cobra.Command{
 	Long:  "Scan Azure Resources",
 	Args:  cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		serviceScanners := []scanners.IAzureScanner{
-			&aks.AKSScanner{},
-			&apim.APIManagementScanner{},
-			&agw.ApplicationGatewayScanner{},
-			&cae.ContainerAppsScanner{},
-			&ci.ContainerInstanceScanner{},
-			&cosmos.CosmosDBScanner{},
-			&cr.ContainerRegistryScanner{},
-			&evh.EventHubScanner{},
-			&evgd.EventGridScanner{},
-			&kv.KeyVaultScanner{},
-			&appcs.AppConfigurationScanner{},
-			&plan.AppServiceScanner{},
-			&redis.RedisScanner{},
-			&sb.ServiceBusScanner{},
-			&sigr.SignalRScanner{},
-			&wps.WebPubSubScanner{},
-			&st.StorageScanner{},
-			&psql.PostgreScanner{},
-			&psql.PostgreFlexibleScanner{},
-			&sql.SQLScanner{},
-			&afd.FrontDoorScanner{},
-			&afw.FirewallScanner{},
-			&mysql.MySQLScanner{},
-			&mysql.MySQLFlexibleScanner{},
+		services, _ := cmd.Flags().GetStringSlice("services")
+		serviceScanners, err := getScanners(services)
+		if err != nil {
+			log.Fatal(err)
 		}
 
 		scan(cmd, serviceScanners)
@@ -96,16 +93,101 @@ var scanCmd = &cobra.Command{
 func scan(cmd *cobra.Command, serviceScanners []scanners.IAzureScanner) {
 	subscriptionID, _ := cmd.Flags().GetString("subscription-id")
 	resourceGroupName, _ := cmd.Flags().GetString("resource-group")
+	tag, _ := cmd.Flags().GetString("tag")
 	outputFilePrefix, _ := cmd.Flags().GetString("output-prefix")
 	defender, _ := cmd.Flags().GetBool("defender")
 	advisor, _ := cmd.Flags().GetBool("advisor")
+	withAdvisor, _ := cmd.Flags().GetBool("with-advisor")
 	mask, _ := cmd.Flags().GetBool("mask")
-	concurrency, _ := cmd.Flags().GetBool("parallel-processes")
+	anonymize, _ := cmd.Flags().GetBool("anonymize")
+	withMetrics, _ := cmd.Flags().GetBool("with-metrics")
+	noColor, _ := cmd.Flags().GetBool("no-color")
+	showPassed, _ := cmd.Flags().GetBool("show-passed")
+	estimateCalls, _ := cmd.Flags().GetBool("estimate-calls")
+	streamResults, _ := cmd.Flags().GetBool("stream")
+	logAnalytics, _ := cmd.Flags().GetBool("log-analytics")
+	remediation, _ := cmd.Flags().GetBool("remediation")
+	outputs, _ := cmd.Flags().GetStringSlice("output-format")
+	deprecatedOutputs, _ := cmd.Flags().GetStringSlice("output")
+	outputs = append(outputs, deprecatedOutputs...)
+	outputBundle, _ := cmd.Flags().GetString("output-bundle")
+	allowedLocations, _ := cmd.Flags().GetStringSlice("allowed-locations")
+	cafPrefixesFile, _ := cmd.Flags().GetString("caf-prefixes")
+	tagPolicy, _ := cmd.Flags().GetStringArray("tag-policy")
+	maxResourceAge, _ := cmd.Flags().GetInt("max-resource-age")
+	minRetentionDays, _ := cmd.Flags().GetInt("min-retention-days")
+	consumerGroupsThreshold, _ := cmd.Flags().GetInt("consumer-groups-threshold")
+	productionSeverity, _ := cmd.Flags().GetBool("production-severity")
+	limit, _ := cmd.Flags().GetInt("limit")
+	parallel, _ := cmd.Flags().GetBool("parallel-processes")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	failFast, _ := cmd.Flags().GetBool("fail-fast")
+	openTUI, _ := cmd.Flags().GetBool("tui")
+	cacheRules, _ := cmd.Flags().GetBool("cache-rules")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	scannerTimeout, _ := cmd.Flags().GetDuration("scanner-timeout")
+	checkpointFile, _ := cmd.Flags().GetString("checkpoint")
+	resume, _ := cmd.Flags().GetString("resume")
+	failOn, _ := cmd.Flags().GetString("fail-on")
+	maxFindings, _ := cmd.Flags().GetStringToInt("max-findings")
+	services, _ := cmd.Flags().GetStringSlice("services")
+
+	if err := validateFailOn(failOn); err != nil {
+		log.Fatal(err)
+	}
+	if err := validateMaxFindings(maxFindings); err != nil {
+		log.Fatal(err)
+	}
+	if concurrency < 0 {
+		log.Fatal("--concurrency can't be negative")
+	}
+	processes := 1
+	if parallel {
+		processes = concurrency
+		if processes == 0 {
+			processes = runtime.GOMAXPROCS(0) * 4
+		}
+	}
+	if streamResults {
+		if err := validateStream(outputs, logAnalytics, remediation, outputBundle, openTUI); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	tagPolicies, err := scanners.ParseTagPolicies(tagPolicy)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cafPrefixes := map[string][]string{}
+	if cafPrefixesFile != "" {
+		cafPrefixes, err = scanners.LoadCAFPrefixes(cafPrefixesFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	outputs = append([]string{"excel", "console"}, outputs...)
+	if logAnalytics {
+		outputs = append(outputs, "loganalytics")
+	}
+	if remediation {
+		outputs = append(outputs, "remediation")
+	}
+	for _, o := range outputs {
+		if _, exists := renderers.Get(o); !exists {
+			log.Fatalf("Unknown output format %s. Available formats: %s", o, strings.Join(renderers.Names(), ", "))
+		}
+	}
 
 	if subscriptionID == "" && resourceGroupName != "" {
 		log.Fatal("Resource Group name can only be used with a Subscription Id")
 	}
 
+	if scannerTimeout == 0 {
+		scannerTimeout = timeout / 2
+	}
+
 	current_time := time.Now()
 	outputFileStamp := fmt.Sprintf("%d_%02d_%02d_T%02d%02d%02d",
 		current_time.Year(), current_time.Month(), current_time.Day(),
@@ -142,17 +224,63 @@ func scan(cmd *cobra.Command, serviceScanners []scanners.IAzureScanner) {
 		}
 	}
 
+	if estimateCalls {
+		if err := printCallEstimate(ctx, cred, clientOptions, subscriptions, resourceGroupName, serviceScanners); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	var checkpoint *scanners.Checkpoint
+	if resume != "" {
+		if checkpointFile != "" && checkpointFile != resume {
+			log.Fatal("--checkpoint can't be used with --resume, the remaining scans are recorded in the resumed checkpoint")
+		}
+		log.Printf("Resuming scan from checkpoint %s", resume)
+		checkpoint, err = scanners.LoadCheckpoint(resume)
+		if err != nil {
+			log.Fatal(err)
+		}
+		checkpointFile = resume
+	} else if checkpointFile != "" {
+		checkpoint = scanners.NewCheckpoint(checkpointFile)
+	}
+
+	var stream *renderers.JSONLinesWriter
+	if streamResults {
+		streamFile := fmt.Sprintf("%s.jsonl", outputFile)
+		log.Printf("Streaming findings to: %s", streamFile)
+		f, err := os.Create(streamFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		stream = renderers.NewJSONLinesWriter(f, mask, showPassed)
+	}
+
 	var ruleResults []scanners.AzureServiceResult
+	var ruleCache *scanners.RuleCache
+	if cacheRules {
+		ruleCache = scanners.NewRuleCache()
+	}
+	summary := severitySummary{}
 	var defenderResults []scanners.DefenderResult
 	var advisorResults []scanners.AdvisorResult
+	var scanErrors []scanners.ScanError
 
-	ctx, cancel := context.WithCancel(ctx)
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
 	defer cancel()
 
 	defenderScanner := scanners.DefenderScanner{}
 	peScanner := scanners.PrivateEndpointScanner{}
 	advisorScanner := scanners.AdvisorScanner{}
 
+	timedOut := false
 	for _, s := range subscriptions {
 		resourceGroups := []string{}
 		if resourceGroupName != "" {
@@ -176,10 +304,12 @@ func scan(cmd *cobra.Command, serviceScanners []scanners.IAzureScanner) {
 		}
 
 		config := &scanners.ScannerConfig{
-			Ctx:            ctx,
-			SubscriptionID: s,
-			Cred:           cred,
-			ClientOptions:  clientOptions,
+			Ctx:                     ctx,
+			SubscriptionID:          s,
+			Cred:                    cred,
+			ClientOptions:           clientOptions,
+			MinRetentionDays:        minRetentionDays,
+			ConsumerGroupsThreshold: consumerGroupsThreshold,
 		}
 
 		err = peScanner.Init(config)
@@ -193,70 +323,328 @@ func scan(cmd *cobra.Command, serviceScanners []scanners.IAzureScanner) {
 
 		scanContext := scanners.ScanContext{
 			PrivateEndpoints: peResults,
+			CAFPrefixes:      cafPrefixes,
 		}
 
-		for _, a := range serviceScanners {
-			err := a.Init(config)
+		subscriptionScanners := serviceScanners
+		if len(services) > 0 {
+			subscriptionScanners, err = filterScannersWithResources(config, serviceScanners)
 			if err != nil {
 				log.Fatal(err)
 			}
 		}
 
-		rc := ReviewContext{
-			Ctx:   ctx,
-			ResCh: make(chan []scanners.AzureServiceResult),
-			ErrCh: make(chan error),
+		runOptions := pkgscanners.RunOptions{
+			Cred:                    cred,
+			ClientOptions:           clientOptions,
+			MinRetentionDays:        minRetentionDays,
+			ConsumerGroupsThreshold: consumerGroupsThreshold,
+			Limit:                   limit,
+			WithMetrics:             withMetrics,
+			ProductionSeverity:      productionSeverity,
+			RuleCache:               ruleCache,
 		}
-		for _, r := range resourceGroups {
-			log.Printf("Scanning Resource Group %s", r)
-			go scanRunner(&rc, r, &scanContext, &serviceScanners, concurrency)
-			res, err := waitForReviews(&rc, len(serviceScanners))
-			// As soon as any error happen, we cancel every still running analysis
+
+		// Scanners are initialized once for the subscription, then scan its Resource Groups concurrently.
+		// Scanners that timed out in a Resource Group may still be running, they are cancelled once the subscription is scanned.
+		subscriptionCtx, cancelSubscription := context.WithCancel(ctx)
+		var initErrors []scanners.ScanError
+		subscriptionScanners, initErrors, err = initScanners(subscriptionCtx, s, subscriptionScanners, &runOptions, failFast)
+		if err != nil {
+			cancelSubscription()
+			log.Fatal(err)
+		}
+		scanErrors = append(scanErrors, initErrors...)
+
+		var tagSelection *scanners.TagSelection
+		if tag != "" {
+			tagSelection, err = selectTaggedResources(config, subscriptionScanners, tag)
 			if err != nil {
-				cancel()
 				log.Fatal(err)
 			}
-			ruleResults = append(ruleResults, *res...)
+			resourceGroups = filterResourceGroups(resourceGroups, tagSelection.ResourceGroups())
 		}
 
-		if defender {
-			err = defenderScanner.Init(config)
+		// Recommendations are listed before scanning, so their categories can be attached to the findings as they are produced
+		var advisorCategories scanners.AdvisorCategories
+		if advisor || withAdvisor {
+			err = advisorScanner.Init(config)
 			if err != nil {
 				log.Fatal(err)
 			}
 
-			res, err := defenderScanner.ListConfiguration()
+			rec, err := advisorScanner.ListRecommendations()
 			if err != nil {
 				log.Fatal(err)
 			}
-			defenderResults = append(defenderResults, res...)
+			if advisor {
+				advisorResults = append(advisorResults, rec...)
+			}
+			if withAdvisor {
+				advisorCategories = scanners.NewAdvisorCategories(rec)
+			}
 		}
 
-		if advisor {
-			err = advisorScanner.Init(config)
+		rc := ReviewContext{
+			Ctx:      ctx,
+			ResCh:    make(chan []scanners.AzureServiceResult),
+			ErrCh:    make(chan error),
+			FailFast: failFast,
+		}
+		jobs := []scanJob{}
+		for _, r := range resourceGroups {
+			rgScanners := subscriptionScanners
+			if tagSelection != nil {
+				rgScanners = tagSelection.GetScanners(r)
+			}
+			jobs = append(jobs, newScanJobs(r, rgScanners)...)
+		}
+		var loadErrors []scanners.ScanError
+		jobs, loadErrors, err = loadScanContext(s, jobs, &scanContext, checkpoint, failFast)
+		if err != nil {
+			cancelSubscription()
+			log.Fatal(err)
+		}
+		scanErrors = append(scanErrors, loadErrors...)
+		log.Printf("Scanning %d Resource Groups, running up to %d scanners at a time", len(resourceGroups), processes)
+		go scanRunner(&rc, s, jobs, &scanContext, processes, scannerTimeout, checkpoint)
+		err = waitForReviews(&rc, len(jobs), func(res []scanners.AzureServiceResult) {
+			if tagSelection != nil {
+				res = tagSelection.FilterResults(res)
+			}
+			addPolicyRules(res, allowedLocations, tagPolicies, maxResourceAge)
+			advisorCategories.Add(res)
+			summary.add(res)
+			if stream != nil {
+				// Findings are written and discarded, so memory doesn't grow with the size of the estate
+				if anonymize {
+					res = renderers.AnonymizeReportData(renderers.ReportData{MainData: res}).MainData
+				}
+				if err := stream.Write(res); err != nil {
+					log.Fatal(err)
+				}
+			} else {
+				ruleResults = append(ruleResults, res...)
+			}
+		})
+		cancelSubscription()
+		scanErrors = append(scanErrors, rc.scanErrors()...)
+		scanErrors = append(scanErrors, scanContext.RuleErrors()...)
+		// When the scan times out, the reports are written with the results received so far
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() != nil {
+			log.Printf("WARNING: scan timed out after %s. Results are partial.", timeout)
+			scanErrors = append(scanErrors, scanners.ScanError{
+				Scanner:        "scan",
+				SubscriptionID: s,
+				Error:          fmt.Sprintf("timed out after %s, the remaining scanners were not run", timeout),
+			})
+			timedOut = true
+			break
+		}
+		// As soon as any error happen, we cancel every still running analysis
+		if err != nil {
+			cancel()
+			log.Fatal(err)
+		}
+
+		if defender {
+			err = defenderScanner.Init(config)
 			if err != nil {
 				log.Fatal(err)
 			}
 
-			rec, err := advisorScanner.ListRecommendations()
+			res, err := defenderScanner.ListConfiguration()
 			if err != nil {
 				log.Fatal(err)
 			}
-			advisorResults = append(advisorResults, rec...)
+			defenderResults = append(defenderResults, res...)
 		}
 	}
 
+	// Scanners complete in any order, sort the results so reports are deterministic
+	sortResults(ruleResults)
+	sortErrors(scanErrors)
+
 	reportData := renderers.ReportData{
 		OutputFileName: outputFile,
+		Version:        version,
+		Commit:         commit,
+		BuildDate:      date,
 		Mask:           mask,
+		NoColor:        noColor,
+		ShowPassed:     showPassed,
 		MainData:       ruleResults,
 		DefenderData:   defenderResults,
 		AdvisorData:    advisorResults,
+		Errors:         scanErrors,
+	}
+
+	if anonymize {
+		reportData = renderers.AnonymizeReportData(reportData)
+	}
+
+	if stream != nil {
+		log.Printf("Streamed %d findings", stream.Count())
+	} else {
+		for _, o := range outputs {
+			r, _ := renderers.Get(o)
+			if err := r.Render(reportData); err != nil {
+				log.Fatal(err)
+			}
+		}
+		if outputBundle != "" {
+			if err := renderers.CreateBundle(reportData, outputs, outputBundle); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+
+	if checkpoint != nil {
+		// Keep the checkpoint of a timed out scan so it can be resumed
+		if timedOut {
+			err = checkpoint.Close()
+			log.Printf("Resume the scan with --resume %s", checkpointFile)
+		} else {
+			err = checkpoint.Delete()
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if ruleCache != nil {
+		log.Printf("Rule cache hits: %d", ruleCache.Hits())
+	}
+
+	if len(scanErrors) > 0 {
+		log.Printf("Scan completed with %d scanner errors. Results are partial.", len(scanErrors))
+	} else {
+		log.Println("Scan completed.")
+	}
+
+	if openTUI {
+		if err := tui.Run(reportData.MainData, showPassed); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	summary.write(os.Stderr)
+	if code := summary.exitCode(failOn); code != 0 {
+		os.Exit(code)
+	}
+	if exceeded := summary.exceededCategories(maxFindings); len(exceeded) > 0 {
+		fmt.Fprintf(os.Stderr, "azqr max findings exceeded: %s\n", strings.Join(exceeded, ", "))
+		os.Exit(maxFindingsExitCode)
+	}
+}
+
+// validateStream - Returns an error if options needing every finding in memory are used with --stream
+func validateStream(outputs []string, logAnalytics, remediation bool, outputBundle string, openTUI bool) error {
+	incompatible := []string{}
+	if len(outputs) > 0 {
+		incompatible = append(incompatible, "--output-format")
+	}
+	if logAnalytics {
+		incompatible = append(incompatible, "--log-analytics")
+	}
+	if remediation {
+		incompatible = append(incompatible, "--remediation")
+	}
+	if outputBundle != "" {
+		incompatible = append(incompatible, "--output-bundle")
+	}
+	if openTUI {
+		incompatible = append(incompatible, "--tui")
+	}
+	if len(incompatible) > 0 {
+		return fmt.Errorf("%s can't be used with --stream, the findings are not kept in memory", strings.Join(incompatible, ", "))
+	}
+	return nil
+}
+
+// addPolicyRules - Adds the rules of the allowed locations, tag and resource age policies to the results
+func addPolicyRules(results []scanners.AzureServiceResult, allowedLocations []string, tagPolicies []scanners.TagPolicy, maxResourceAge int) {
+	scanners.AddAllowedLocationsRule(results, allowedLocations)
+	scanners.AddTagPolicyRule(results, tagPolicies)
+	scanners.AddResourceAgeRule(results, maxResourceAge)
+}
+
+// printCallEstimate - Uses Resource Graph to print the approximate number of ARM calls of the scan
+func printCallEstimate(ctx context.Context, cred azcore.TokenCredential, options *arm.ClientOptions, subscriptions []string, resourceGroupName string, serviceScanners []scanners.IAzureScanner) error {
+	graph := scanners.ResourceGraph{}
+	err := graph.Init(&scanners.ScannerConfig{
+		Ctx:           ctx,
+		Cred:          cred,
+		ClientOptions: options,
+	})
+	if err != nil {
+		return err
+	}
+
+	estimate, err := graph.EstimateCalls(subscriptions, resourceGroupName, serviceScanners)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Estimated ARM calls: %d (subscriptions: %d, resource groups: %d, services: %d, resources: %d)\n",
+		estimate.Calls, estimate.Subscriptions, estimate.ResourceGroups, len(serviceScanners), estimate.Resources)
+	return nil
+}
+
+// filterScannersWithResources - Uses Resource Graph to skip the scanners without resources in the subscription
+func filterScannersWithResources(config *scanners.ScannerConfig, serviceScanners []scanners.IAzureScanner) ([]scanners.IAzureScanner, error) {
+	graph := scanners.ResourceGraph{}
+	err := graph.Init(config)
+	if err != nil {
+		return nil, err
+	}
+
+	types := []string{}
+	for _, s := range serviceScanners {
+		types = append(types, s.GetResourceTypes()...)
+	}
+
+	counts, err := graph.CountResourcesByType([]string{config.SubscriptionID}, types)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := scanners.FilterScannersWithResources(serviceScanners, counts)
+	log.Printf("Found resources for %d of %d services in subscription %s", len(filtered), len(serviceScanners), config.SubscriptionID)
+	return filtered, nil
+}
+
+// selectTaggedResources - Uses Resource Graph to find the resources with the tag (key=value or key) and dispatch them to their scanners
+func selectTaggedResources(config *scanners.ScannerConfig, serviceScanners []scanners.IAzureScanner, tag string) (*scanners.TagSelection, error) {
+	graph := scanners.ResourceGraph{}
+	err := graph.Init(config)
+	if err != nil {
+		return nil, err
 	}
 
-	renderers.CreateExcelReport(reportData)
+	name, value, _ := strings.Cut(tag, "=")
+	resources, err := graph.ListResourcesByTag([]string{config.SubscriptionID}, name, value)
+	if err != nil {
+		return nil, err
+	}
 
-	log.Println("Scan completed.")
+	selection := scanners.NewTagSelection(serviceScanners, resources)
+	log.Printf("Found %d resources with tag %s in %d Resource Groups of subscription %s", len(resources), tag, len(selection.ResourceGroups()), config.SubscriptionID)
+	return selection, nil
+}
+
+// filterResourceGroups - Returns the resource groups present in both lists, ignoring case
+func filterResourceGroups(resourceGroups []string, selected []string) []string {
+	filtered := []string{}
+	for _, r := range resourceGroups {
+		for _, s := range selected {
+			if strings.EqualFold(r, s) {
+				filtered = append(filtered, r)
+				break
+			}
+		}
+	}
+	return filtered
 }
 
 // ReviewContext A running resource group analysis support context
@@ -267,43 +655,235 @@ type ReviewContext struct {
 	ResCh chan []scanners.AzureServiceResult
 	// Communication interface for errors
 	ErrCh chan error
+	// Abort the review on the first scanner error, otherwise the error is recorded in Errors and the review continues
+	FailFast bool
+	// Errors of the scanners that failed when FailFast is false
+	Errors []scanners.ScanError
+	mu     sync.Mutex
 }
 
-// Run a scan on a particular resource group "r" with the appropriates scanners using "concurrency" goroutines
-func scanRunner(rc *ReviewContext, r string, scanContext *scanners.ScanContext, svcAnalysers *[]scanners.IAzureScanner, concurrency bool) {
-	processes := 1
-	if concurrency {
-		processes = len(*svcAnalysers)
-	}
-	sem := semaphore.NewWeighted(int64(processes))
-	for i := range *svcAnalysers {
-		if err := sem.Acquire(rc.Ctx, 1); err != nil {
-			rc.ErrCh <- err
-			return
-		}
-		// When starting a goroutine from a loop, we cannot directly use
-		// the iteration variable, as only the last element of the loop will
-		// be processed
-		analyserPtr := &(*svcAnalysers)[i]
-		go func(a *scanners.IAzureScanner, r string) {
-			defer sem.Release(1)
-			// In case the analysis was cancelled, we don't need to execute the review
-			if context.Canceled == rc.Ctx.Err() {
-				return
+// addError - Records the error of a scanner
+func (rc *ReviewContext) addError(e scanners.ScanError) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.Errors = append(rc.Errors, e)
+}
+
+// scanErrors - Returns a copy of the recorded errors, safe to use while scanners are still running
+func (rc *ReviewContext) scanErrors() []scanners.ScanError {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return append([]scanners.ScanError(nil), rc.Errors...)
+}
+
+// scanJob - A scanner to run on a resource group
+type scanJob struct {
+	resourceGroup string
+	scanner       scanners.IAzureScanner
+}
+
+// newScanJobs - Returns a job for each scanner of the resource group
+func newScanJobs(resourceGroup string, svcScanners []scanners.IAzureScanner) []scanJob {
+	jobs := make([]scanJob, 0, len(svcScanners))
+	for _, a := range svcScanners {
+		jobs = append(jobs, scanJob{resourceGroup: resourceGroup, scanner: a})
+	}
+	return jobs
+}
+
+// initScanners - Initializes the scanners for the subscription and returns the ones ready to scan its Resource Groups.
+// Unless failFast is set, scanners failing to initialize are skipped and their errors returned.
+func initScanners(ctx context.Context, subscriptionID string, svcScanners []scanners.IAzureScanner, opts *pkgscanners.RunOptions, failFast bool) ([]scanners.IAzureScanner, []scanners.ScanError, error) {
+	initialized := make([]scanners.IAzureScanner, 0, len(svcScanners))
+	errs := []scanners.ScanError{}
+	for _, a := range svcScanners {
+		if err := pkgscanners.Init(ctx, a, subscriptionID, *opts); err != nil {
+			if failFast {
+				return nil, nil, err
 			}
-			res, err := retry(3, 10*time.Millisecond, a, r, scanContext)
-			if err != nil {
-				rc.ErrCh <- err
+			name := scanners.GetScannerName(a)
+			log.Printf("WARNING: %s failed to initialize for subscription %s: %s", name, subscriptionID, err)
+			errs = append(errs, scanners.ScanError{Scanner: name, SubscriptionID: subscriptionID, Error: err.Error()})
+			continue
+		}
+		initialized = append(initialized, a)
+	}
+	return initialized, errs, nil
+}
+
+// loadScanContext - Loads, once for the subscription, the data the rules of the scanners implementing IScanContextLoader
+// read from the ScanContext, for the Resource Groups each scanner still has to scan. Unless failFast is set, the jobs of
+// scanners failing to load are dropped and their errors returned, as for scanners failing to initialize.
+func loadScanContext(subscriptionID string, jobs []scanJob, scanContext *scanners.ScanContext, checkpoint *scanners.Checkpoint, failFast bool) ([]scanJob, []scanners.ScanError, error) {
+	loaders := []scanners.IScanContextLoader{}
+	resourceGroups := map[scanners.IScanContextLoader][]string{}
+	for _, j := range jobs {
+		loader, ok := j.scanner.(scanners.IScanContextLoader)
+		if !ok {
+			continue
+		}
+		if checkpoint != nil {
+			if checkpoint.IsCompleted(subscriptionID, j.resourceGroup, scanners.GetScannerName(j.scanner)) {
+				continue
 			}
-			rc.ResCh <- res
-		}(analyserPtr, r)
+		}
+		if _, exists := resourceGroups[loader]; !exists {
+			loaders = append(loaders, loader)
+		}
+		resourceGroups[loader] = append(resourceGroups[loader], j.resourceGroup)
+	}
+
+	errs := []scanners.ScanError{}
+	failed := map[scanners.IAzureScanner]bool{}
+	for _, loader := range loaders {
+		if err := loader.LoadScanContext(resourceGroups[loader], scanContext); err != nil {
+			if failFast {
+				return nil, nil, err
+			}
+			a := loader.(scanners.IAzureScanner)
+			name := scanners.GetScannerName(a)
+			log.Printf("WARNING: %s failed to load its data for subscription %s: %s", name, subscriptionID, err)
+			errs = append(errs, scanners.ScanError{Scanner: name, SubscriptionID: subscriptionID, Error: err.Error()})
+			failed[a] = true
+		}
+	}
+	if len(failed) == 0 {
+		return jobs, errs, nil
+	}
+
+	loaded := make([]scanJob, 0, len(jobs))
+	for _, j := range jobs {
+		if !failed[j.scanner] {
+			loaded = append(loaded, j)
+		}
+	}
+	return loaded, errs, nil
+}
+
+// Run the scan jobs of a subscription with "processes" workers reading them from a queue, and return once the workers are done.
+// Scanners are initialized once per subscription by initScanners and guard their own state, so a scanner scans several
+// resource groups at the same time. Throttled (429) requests are retried by the Azure SDK honoring Retry-After.
+// When a checkpoint is given, scanners it already completed are skipped and their stored results are returned instead,
+// and every job is recorded in the checkpoint as soon as it is done.
+// Scanners running longer than "timeout" are recorded as failed and abandoned, and the scan continues without their results.
+// Their Azure calls use the context of the subscription the scanners were initialized with, not one per job, so they are
+// only cancelled once the subscription is scanned.
+// Unless rc.FailFast is set, failed scanners are recorded in rc.Errors and the scan continues without their results.
+// Once rc.Ctx is done, no more jobs are run and pending results are dropped, so the workers don't block
+// when waitForReviews stopped reading.
+func scanRunner(rc *ReviewContext, subscriptionID string, jobs []scanJob, scanContext *scanners.ScanContext, processes int, timeout time.Duration, checkpoint *scanners.Checkpoint) {
+	queue := make(chan scanJob)
+	wg := sync.WaitGroup{}
+	for i := 0; i < processes; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range queue {
+				// In case the analysis was cancelled or timed out, we don't need to execute the review
+				if rc.Ctx.Err() != nil {
+					return
+				}
+				res, err := runScanJob(rc, subscriptionID, j, scanContext, timeout, checkpoint)
+				if err != nil {
+					select {
+					case rc.ErrCh <- err:
+					case <-rc.Ctx.Done():
+						return
+					}
+					continue
+				}
+				select {
+				case rc.ResCh <- res:
+				case <-rc.Ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, j := range jobs {
+		select {
+		case queue <- j:
+		case <-rc.Ctx.Done():
+			break feed
+		}
+	}
+	close(queue)
+	wg.Wait()
+}
+
+// runScanJob - Runs the scanner of the job, returning an error only if the scan must be aborted
+func runScanJob(rc *ReviewContext, subscriptionID string, j scanJob, scanContext *scanners.ScanContext, timeout time.Duration, checkpoint *scanners.Checkpoint) ([]scanners.AzureServiceResult, error) {
+	name := scanners.GetScannerName(j.scanner)
+	if checkpoint != nil {
+		if res, ok := checkpoint.Completed(subscriptionID, j.resourceGroup, name); ok {
+			return res, nil
+		}
+	}
+	res, err := scanWithTimeout(rc.Ctx, timeout, j.scanner, j.resourceGroup, scanContext)
+	// The whole scan was cancelled or timed out, there is no one left to report the error to
+	if err != nil && rc.Ctx.Err() != nil {
+		return nil, rc.Ctx.Err()
+	}
+	if err != nil {
+		if rc.FailFast {
+			return nil, err
+		}
+		log.Printf("WARNING: %s failed scanning Resource Group %s: %s", name, j.resourceGroup, err)
+		rc.addError(scanners.ScanError{Scanner: name, SubscriptionID: subscriptionID, ResourceGroup: j.resourceGroup, Error: err.Error()})
+		return []scanners.AzureServiceResult{}, nil
 	}
+	if checkpoint != nil {
+		if err := checkpoint.Complete(subscriptionID, j.resourceGroup, name, res); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// scanResult - Results or error of a scanner run by scanWithTimeout
+type scanResult struct {
+	res []scanners.AzureServiceResult
+	err error
 }
 
-func retry(attempts int, sleep time.Duration, a *scanners.IAzureScanner, r string, scanContext *scanners.ScanContext) ([]scanners.AzureServiceResult, error) {
+// scanWithTimeout - Runs the scanner, returning an error as soon as "timeout" expires. The Azure calls of the scanner
+// use the context it was initialized with, so a timed out scanner may keep running until the subscription is scanned,
+// and its results are discarded. A timeout of 0 means no timeout.
+func scanWithTimeout(ctx context.Context, timeout time.Duration, a scanners.IAzureScanner, resourceGroupName string, scanContext *scanners.ScanContext) ([]scanners.AzureServiceResult, error) {
+	if timeout <= 0 {
+		return retry(ctx, 3, 10*time.Millisecond, a, resourceGroupName, scanContext)
+	}
+
+	scanCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	// Buffered, so a scanner finishing after the timeout doesn't block forever
+	done := make(chan scanResult, 1)
+	go func() {
+		res, err := retry(scanCtx, 3, 10*time.Millisecond, a, resourceGroupName, scanContext)
+		done <- scanResult{res: res, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		if r.err != nil && ctx.Err() == nil && errors.Is(scanCtx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("timed out after %s", timeout)
+		}
+		return r.res, r.err
+	case <-scanCtx.Done():
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("timed out after %s", timeout)
+	}
+}
+
+func retry(ctx context.Context, attempts int, sleep time.Duration, a scanners.IAzureScanner, resourceGroupName string, scanContext *scanners.ScanContext) ([]scanners.AzureServiceResult, error) {
 	var err error
 	for i := 0; ; i++ {
-		res, err := (*a).Scan(r, scanContext)
+		var res []scanners.AzureServiceResult
+		res, err = a.Scan(resourceGroupName, scanContext)
 		if err == nil {
 			return res, nil
 		}
@@ -319,31 +899,67 @@ func retry(attempts int, sleep time.Duration, a *scanners.IAzureScanner, r strin
 			break
 		}
 		
-		time.Sleep(sleep)
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(sleep):
+		}
 		sleep *= 2
 	}
 	return nil, err
 }
 
-// Wait for at least "nb" goroutines to hands their result and return them
-func waitForReviews(rc *ReviewContext, nb int) (*[]scanners.AzureServiceResult, error) {
-	received := 0
-	reviews := make([]scanners.AzureServiceResult, 0)
-	for {
+// Wait for "nb" goroutines to hand their results, passing them to "handle" as they arrive
+func waitForReviews(rc *ReviewContext, nb int, handle func(res []scanners.AzureServiceResult)) error {
+	for received := 0; received < nb; received++ {
 		select {
 		// In case a timeout is set
 		case <-rc.Ctx.Done():
-			return nil, rc.Ctx.Err()
+			return rc.Ctx.Err()
 		case err := <-rc.ErrCh:
-			return nil, err
+			return err
 		case res := <-rc.ResCh:
-			received++
-			reviews = append(reviews, res...)
-			if received >= nb {
-				return &reviews, nil
-			}
+			handle(res)
 		}
 	}
+	return nil
+}
+
+// sortResults - Sorts the results by subscription, resource group, type and resource id
+func sortResults(results []scanners.AzureServiceResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.SubscriptionID != b.SubscriptionID {
+			return a.SubscriptionID < b.SubscriptionID
+		}
+		if a.ResourceGroup != b.ResourceGroup {
+			return a.ResourceGroup < b.ResourceGroup
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.ResourceID < b.ResourceID
+	})
+}
+
+// sortErrors - Sorts the scanner and rule errors by subscription, resource group, scanner, resource and rule
+func sortErrors(errors []scanners.ScanError) {
+	sort.SliceStable(errors, func(i, j int) bool {
+		a, b := errors[i], errors[j]
+		if a.SubscriptionID != b.SubscriptionID {
+			return a.SubscriptionID < b.SubscriptionID
+		}
+		if a.ResourceGroup != b.ResourceGroup {
+			return a.ResourceGroup < b.ResourceGroup
+		}
+		if a.Scanner != b.Scanner {
+			return a.Scanner < b.Scanner
+		}
+		if a.ResourceID != b.ResourceID {
+			return a.ResourceID < b.ResourceID
+		}
+		return a.Rule < b.Rule
+	})
 }
 
 func checkExistenceResourceGroup(ctx context.Context, subscriptionID string, resourceGroupName string, cred azcore.TokenCredential, options *arm.ClientOptions) (bool, error) {
@@ -0,0 +1,650 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package azqr
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cmendible/azqr/internal/scanners"
+	pkgscanners "github.com/cmendible/azqr/pkg/scanners"
+)
+
+type fakeScanner struct {
+	name  string
+	calls int
+}
+
+func (f *fakeScanner) Init(config *scanners.ScannerConfig) error {
+	return nil
+}
+
+func (f *fakeScanner) GetRules() map[string]scanners.AzureRule {
+	return map[string]scanners.AzureRule{}
+}
+
+func (f *fakeScanner) GetResourceTypes() []string {
+	return []string{"Fake/resources"}
+}
+
+func (f *fakeScanner) Scan(resourceGroupName string, scanContext *scanners.ScanContext) ([]scanners.AzureServiceResult, error) {
+	f.calls++
+	return []scanners.AzureServiceResult{
+		{
+			ResourceGroup: resourceGroupName,
+			ServiceName:   f.name,
+		},
+	}, nil
+}
+
+// blockingScanner - Blocks until its context is done, like a scanner waiting on a hung Azure API
+type blockingScanner struct {
+	fakeScanner
+	ctx      context.Context
+	returned chan struct{}
+}
+
+func (b *blockingScanner) Init(config *scanners.ScannerConfig) error {
+	b.ctx = config.Ctx
+	return nil
+}
+
+func (b *blockingScanner) Scan(resourceGroupName string, scanContext *scanners.ScanContext) ([]scanners.AzureServiceResult, error) {
+	defer close(b.returned)
+	<-b.ctx.Done()
+	return nil, b.ctx.Err()
+}
+
+type erroringScanner struct {
+	fakeScanner
+}
+
+func (e *erroringScanner) Scan(resourceGroupName string, scanContext *scanners.ScanContext) ([]scanners.AzureServiceResult, error) {
+	e.calls++
+	return nil, errors.New("scanner failed")
+}
+
+// testScanContext - Returns a scan context with the private endpoints already listed, as the scan command does
+func testScanContext() *scanners.ScanContext {
+	return &scanners.ScanContext{PrivateEndpoints: map[string]bool{}}
+}
+
+// loadCheckpoint - Closes the checkpoint and loads it back from path
+func loadCheckpoint(t *testing.T, checkpoint *scanners.Checkpoint, path string) *scanners.Checkpoint {
+	t.Helper()
+	if err := checkpoint.Close(); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := scanners.LoadCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loaded
+}
+
+// collectReviews - Waits for the results of "nb" jobs and returns them
+func collectReviews(rc *ReviewContext, nb int) (*[]scanners.AzureServiceResult, error) {
+	reviews := []scanners.AzureServiceResult{}
+	err := waitForReviews(rc, nb, func(res []scanners.AzureServiceResult) {
+		reviews = append(reviews, res...)
+	})
+	return &reviews, err
+}
+
+func TestScanRunner_ScannerError(t *testing.T) {
+	tests := []struct {
+		name     string
+		failFast bool
+	}{
+		{
+			name:     "fail fast aborts on the first error",
+			failFast: true,
+		},
+		{
+			name:     "errors are collected and the scan continues",
+			failFast: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "checkpoint.json")
+			checkpoint := scanners.NewCheckpoint(path)
+
+			failing := &erroringScanner{fakeScanner: fakeScanner{name: "failing"}}
+			healthy := &fakeScanner{name: "healthy"}
+
+			svcScanners := []scanners.IAzureScanner{failing, healthy}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			rc := ReviewContext{
+				Ctx:      ctx,
+				ResCh:    make(chan []scanners.AzureServiceResult),
+				ErrCh:    make(chan error),
+				FailFast: tt.failFast,
+			}
+			done := make(chan struct{})
+			go func() {
+				scanRunner(&rc, "sub", newScanJobs("rg", svcScanners), testScanContext(), 1, 0, checkpoint)
+				close(done)
+			}()
+			res, err := collectReviews(&rc, len(svcScanners))
+
+			if tt.failFast {
+				if err == nil {
+					t.Fatal("waitForReviews() should return the scanner error")
+				}
+				if len(rc.Errors) != 0 {
+					t.Errorf("ReviewContext.Errors = %v, want no collected errors", rc.Errors)
+				}
+				// The scan command cancels the scan, the workers must not stay blocked on results no one reads
+				cancel()
+				select {
+				case <-done:
+				case <-time.After(time.Second):
+					t.Error("scanRunner() should return once the scan is cancelled")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(*res) != 1 || (*res)[0].ServiceName != "healthy" {
+				t.Errorf("scanRunner() = %v, want only the results of the healthy scanner", *res)
+			}
+			if len(rc.Errors) != 1 || rc.Errors[0].Scanner != scanners.GetScannerName(failing) || rc.Errors[0].ResourceGroup != "rg" {
+				t.Errorf("ReviewContext.Errors = %v, want the error of the failing scanner", rc.Errors)
+			}
+			loaded := loadCheckpoint(t, checkpoint, path)
+			if _, ok := loaded.Completed("sub", "rg", scanners.GetScannerName(failing)); ok {
+				t.Error("failed scanner should not be recorded in the checkpoint")
+			}
+			if _, ok := loaded.Completed("sub", "rg", scanners.GetScannerName(healthy)); !ok {
+				t.Error("healthy scanner should be recorded in the checkpoint")
+			}
+		})
+	}
+}
+
+func TestScanRunner_ScannerTimeout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	checkpoint := scanners.NewCheckpoint(path)
+
+	healthy := &fakeScanner{name: "healthy"}
+	hung := &blockingScanner{fakeScanner: fakeScanner{name: "hung"}, returned: make(chan struct{})}
+
+	// Scanners are initialized once for the subscription, its context is cancelled once the subscription is scanned
+	subscriptionCtx, cancelSubscription := context.WithCancel(context.Background())
+	defer cancelSubscription()
+	svcScanners, _, err := initScanners(subscriptionCtx, "sub", []scanners.IAzureScanner{healthy, hung}, &pkgscanners.RunOptions{}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc := ReviewContext{
+		Ctx:   context.Background(),
+		ResCh: make(chan []scanners.AzureServiceResult),
+		ErrCh: make(chan error),
+	}
+	go scanRunner(&rc, "sub", newScanJobs("rg", svcScanners), testScanContext(), len(svcScanners), 50*time.Millisecond, checkpoint)
+	res, err := collectReviews(&rc, len(svcScanners))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*res) != 1 || (*res)[0].ServiceName != "healthy" {
+		t.Errorf("scanRunner() = %v, want only the results of the healthy scanner", *res)
+	}
+	cancelSubscription()
+	select {
+	case <-hung.returned:
+	case <-time.After(time.Second):
+		t.Error("timed out scanner should be cancelled with the subscription, not left running")
+	}
+	if len(rc.Errors) != 1 || rc.Errors[0].Scanner != scanners.GetScannerName(hung) || !strings.Contains(rc.Errors[0].Error, "timed out") {
+		t.Errorf("ReviewContext.Errors = %v, want the timeout of the hung scanner", rc.Errors)
+	}
+	if _, ok := loadCheckpoint(t, checkpoint, path).Completed("sub", "rg", scanners.GetScannerName(hung)); ok {
+		t.Error("timed out scanner should not be recorded in the checkpoint")
+	}
+}
+
+// stuckScanner - Ignores the cancellation of its context, like a scanner stuck in a call made without it
+type stuckScanner struct {
+	fakeScanner
+	release chan struct{}
+}
+
+func (s *stuckScanner) Scan(resourceGroupName string, scanContext *scanners.ScanContext) ([]scanners.AzureServiceResult, error) {
+	<-s.release
+	return []scanners.AzureServiceResult{}, nil
+}
+
+func TestScanWithTimeout_StuckScanner(t *testing.T) {
+	stuck := &stuckScanner{fakeScanner: fakeScanner{name: "stuck"}, release: make(chan struct{})}
+	defer close(stuck.release)
+
+	start := time.Now()
+	_, err := scanWithTimeout(context.Background(), 50*time.Millisecond, stuck, "rg", testScanContext())
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("scanWithTimeout() error = %v, want the timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("scanWithTimeout() returned after %s, want it to return when the timeout expires", elapsed)
+	}
+}
+
+func TestScanRunner_ScanTimeout(t *testing.T) {
+	healthy := &fakeScanner{name: "healthy"}
+	hung := &blockingScanner{fakeScanner: fakeScanner{name: "hung"}, returned: make(chan struct{})}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	svcScanners, _, err := initScanners(ctx, "sub", []scanners.IAzureScanner{healthy, hung}, &pkgscanners.RunOptions{}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc := ReviewContext{
+		Ctx:   ctx,
+		ResCh: make(chan []scanners.AzureServiceResult),
+		ErrCh: make(chan error),
+	}
+	go scanRunner(&rc, "sub", newScanJobs("rg", svcScanners), testScanContext(), len(svcScanners), 0, nil)
+	res, err := collectReviews(&rc, len(svcScanners))
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("waitForReviews() error = %v, want the scan timeout", err)
+	}
+	if len(*res) != 1 || (*res)[0].ServiceName != "healthy" {
+		t.Errorf("waitForReviews() = %v, want the results received before the timeout", *res)
+	}
+	<-hung.returned
+	if errs := rc.scanErrors(); len(errs) != 0 {
+		t.Errorf("ReviewContext.Errors = %v, want no errors recorded for scanners cancelled by the scan timeout", errs)
+	}
+}
+
+func TestScanRunner_ResumeSkipsCompletedScanners(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	completed := &fakeScanner{name: "completed"}
+	pending := &fakeScanner{name: "pending"}
+
+	previous := scanners.NewCheckpoint(path)
+	err := previous.Complete("sub", "rg", scanners.GetScannerName(completed), []scanners.AzureServiceResult{
+		{
+			ResourceGroup: "rg",
+			ServiceName:   "from-checkpoint",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkpoint := loadCheckpoint(t, previous, path)
+
+	svcScanners := []scanners.IAzureScanner{completed}
+	rc := ReviewContext{
+		Ctx:   context.Background(),
+		ResCh: make(chan []scanners.AzureServiceResult),
+		ErrCh: make(chan error),
+	}
+	go scanRunner(&rc, "sub", newScanJobs("rg", svcScanners), testScanContext(), 1, 0, checkpoint)
+	res, err := collectReviews(&rc, len(svcScanners))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if completed.calls != 0 {
+		t.Errorf("completed scanner was called %d times, want 0", completed.calls)
+	}
+	if len(*res) != 1 || (*res)[0].ServiceName != "from-checkpoint" {
+		t.Errorf("scanRunner() = %v, want results restored from checkpoint", *res)
+	}
+
+	svcScanners = []scanners.IAzureScanner{pending}
+	go scanRunner(&rc, "sub", newScanJobs("rg2", svcScanners), testScanContext(), 1, 0, checkpoint)
+	_, err = collectReviews(&rc, len(svcScanners))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pending.calls != 1 {
+		t.Errorf("pending scanner was called %d times, want 1", pending.calls)
+	}
+	if _, ok := loadCheckpoint(t, checkpoint, path).Completed("sub", "rg2", scanners.GetScannerName(pending)); !ok {
+		t.Error("pending scanner should be recorded in the checkpoint")
+	}
+}
+
+// concurrentScanner - Counts the scans running at the same time, across the scanners sharing the counters
+type concurrentScanner struct {
+	fakeScanner
+	scans      int32
+	running    *int32
+	maxRunning *int32
+}
+
+func (c *concurrentScanner) Scan(resourceGroupName string, scanContext *scanners.ScanContext) ([]scanners.AzureServiceResult, error) {
+	atomic.AddInt32(&c.scans, 1)
+	running := atomic.AddInt32(c.running, 1)
+	defer atomic.AddInt32(c.running, -1)
+	for {
+		max := atomic.LoadInt32(c.maxRunning)
+		if running <= max || atomic.CompareAndSwapInt32(c.maxRunning, max, running) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+	return []scanners.AzureServiceResult{{ResourceGroup: resourceGroupName, ServiceName: c.name}}, nil
+}
+
+type gatedScanner struct {
+	fakeScanner
+	started *sync.WaitGroup
+}
+
+func (g *gatedScanner) Scan(resourceGroupName string, scanContext *scanners.ScanContext) ([]scanners.AzureServiceResult, error) {
+	// Every scan waits for the others, so the scan only completes if they run at the same time
+	g.started.Done()
+	g.started.Wait()
+	return []scanners.AzureServiceResult{{ResourceGroup: resourceGroupName, ServiceName: g.name}}, nil
+}
+
+func TestScanRunner_ResourceGroupsInParallel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	checkpoint := scanners.NewCheckpoint(path)
+
+	resourceGroups := []string{"rg1", "rg2", "rg3", "rg4"}
+	started := &sync.WaitGroup{}
+	started.Add(len(resourceGroups))
+	// The same scanner, initialized once for the subscription, scans every resource group at the same time
+	shared := &gatedScanner{fakeScanner: fakeScanner{name: "shared"}, started: started}
+
+	jobs := []scanJob{}
+	for _, r := range resourceGroups {
+		jobs = append(jobs, newScanJobs(r, []scanners.IAzureScanner{shared})...)
+	}
+
+	rc := ReviewContext{
+		Ctx:   context.Background(),
+		ResCh: make(chan []scanners.AzureServiceResult),
+		ErrCh: make(chan error),
+	}
+	go scanRunner(&rc, "sub", jobs, testScanContext(), len(resourceGroups), 0, checkpoint)
+	res, err := collectReviews(&rc, len(jobs))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*res) != len(jobs) {
+		t.Errorf("scanRunner() returned %d results, want %d", len(*res), len(jobs))
+	}
+	loaded := loadCheckpoint(t, checkpoint, path)
+	for _, r := range resourceGroups {
+		if _, ok := loaded.Completed("sub", r, scanners.GetScannerName(shared)); !ok {
+			t.Errorf("shared scanner should be recorded in the checkpoint for %s", r)
+		}
+	}
+}
+
+func TestScanRunner_BoundedWorkers(t *testing.T) {
+	var running, maxRunning int32
+	shared := &concurrentScanner{fakeScanner: fakeScanner{name: "shared"}, running: &running, maxRunning: &maxRunning}
+	other := &concurrentScanner{fakeScanner: fakeScanner{name: "other"}, running: &running, maxRunning: &maxRunning}
+
+	jobs := []scanJob{}
+	for _, r := range []string{"rg1", "rg2", "rg3", "rg4", "rg5", "rg6"} {
+		jobs = append(jobs, newScanJobs(r, []scanners.IAzureScanner{shared, other})...)
+	}
+
+	rc := ReviewContext{
+		Ctx:   context.Background(),
+		ResCh: make(chan []scanners.AzureServiceResult),
+		ErrCh: make(chan error),
+	}
+	go scanRunner(&rc, "sub", jobs, testScanContext(), 2, 0, nil)
+	res, err := collectReviews(&rc, len(jobs))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*res) != len(jobs) {
+		t.Errorf("scanRunner() returned %d results, want %d", len(*res), len(jobs))
+	}
+	if shared.scans != 6 || other.scans != 6 {
+		t.Errorf("scanners were called %d and %d times, want 6", shared.scans, other.scans)
+	}
+	if maxRunning > 2 {
+		t.Errorf("up to %d scans ran at the same time, want at most 2 workers", maxRunning)
+	}
+}
+
+func TestInitScanners(t *testing.T) {
+	initErr := errors.New("init failed")
+	failing := &initErrorScanner{fakeScanner: fakeScanner{name: "failing"}, err: initErr}
+	healthy := &fakeScanner{name: "healthy"}
+
+	initialized, errs, err := initScanners(context.Background(), "sub", []scanners.IAzureScanner{failing, healthy}, &pkgscanners.RunOptions{}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(initialized) != 1 || initialized[0] != healthy {
+		t.Errorf("initScanners() = %v, want only the healthy scanner", initialized)
+	}
+	if len(errs) != 1 || errs[0].Scanner != scanners.GetScannerName(failing) || errs[0].SubscriptionID != "sub" {
+		t.Errorf("initScanners() errors = %v, want the error of the failing scanner", errs)
+	}
+
+	if _, _, err := initScanners(context.Background(), "sub", []scanners.IAzureScanner{failing, healthy}, &pkgscanners.RunOptions{}, true); !errors.Is(err, initErr) {
+		t.Errorf("initScanners() with fail fast error = %v, want %v", err, initErr)
+	}
+}
+
+// loaderScanner - Records the Resource Groups it loads its data for
+type loaderScanner struct {
+	fakeScanner
+	resourceGroups []string
+	err            error
+}
+
+func (l *loaderScanner) LoadScanContext(resourceGroups []string, scanContext *scanners.ScanContext) error {
+	l.resourceGroups = append(l.resourceGroups, resourceGroups...)
+	return l.err
+}
+
+// failingLoaderScanner - Fails to load its data
+type failingLoaderScanner struct {
+	loaderScanner
+}
+
+func TestLoadScanContext(t *testing.T) {
+	loader := &loaderScanner{fakeScanner: fakeScanner{name: "loader"}}
+	failing := &failingLoaderScanner{loaderScanner: loaderScanner{fakeScanner: fakeScanner{name: "failing"}, err: errors.New("load failed")}}
+	plain := &fakeScanner{name: "plain"}
+
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	previous := scanners.NewCheckpoint(path)
+	if err := previous.Complete("sub", "rg2", scanners.GetScannerName(loader), nil); err != nil {
+		t.Fatal(err)
+	}
+	checkpoint := loadCheckpoint(t, previous, path)
+	defer checkpoint.Close()
+
+	jobs := append(newScanJobs("rg1", []scanners.IAzureScanner{loader, failing, plain}), newScanJobs("rg2", []scanners.IAzureScanner{loader, failing, plain})...)
+	got, errs, err := loadScanContext("sub", jobs, testScanContext(), checkpoint, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"rg1"}; !reflect.DeepEqual(loader.resourceGroups, want) {
+		t.Errorf("LoadScanContext() called for %v, want %v once, skipping completed Resource Groups", loader.resourceGroups, want)
+	}
+	if want := []string{"rg1", "rg2"}; !reflect.DeepEqual(failing.resourceGroups, want) {
+		t.Errorf("LoadScanContext() called for %v, want %v once", failing.resourceGroups, want)
+	}
+	for _, j := range got {
+		if j.scanner == failing {
+			t.Errorf("loadScanContext() should drop the jobs of scanners failing to load, got %v", j)
+		}
+	}
+	if len(got) != 4 {
+		t.Errorf("loadScanContext() returned %d jobs, want 4", len(got))
+	}
+	if len(errs) != 1 || errs[0].Scanner != scanners.GetScannerName(failing) || errs[0].SubscriptionID != "sub" {
+		t.Errorf("loadScanContext() errors = %v, want the error of the failing scanner", errs)
+	}
+	if _, ok := checkpoint.Completed("sub", "rg2", scanners.GetScannerName(loader)); !ok {
+		t.Errorf("loadScanContext() should keep the completed units of the checkpoint to be replayed")
+	}
+
+	if _, _, err := loadScanContext("sub", jobs, testScanContext(), nil, true); err == nil {
+		t.Errorf("loadScanContext() with fail fast should return the error of the failing scanner")
+	}
+}
+
+type initErrorScanner struct {
+	fakeScanner
+	err error
+}
+
+func (i *initErrorScanner) Init(config *scanners.ScannerConfig) error {
+	return i.err
+}
+
+type ruledScanner struct {
+	fakeScanner
+}
+
+func (r *ruledScanner) Scan(resourceGroupName string, scanContext *scanners.ScanContext) ([]scanners.AzureServiceResult, error) {
+	r.calls++
+	createdAt := time.Now().AddDate(-1, 0, 0)
+	return []scanners.AzureServiceResult{
+		{
+			SubscriptionID: "sub",
+			ResourceGroup:  resourceGroupName,
+			ServiceName:    r.name,
+			Location:       "eastus",
+			Tags:           map[string]*string{},
+			CreatedAt:      &createdAt,
+			Rules: map[string]scanners.AzureRuleResult{
+				"rule": {Id: "rule"},
+			},
+		},
+	}, nil
+}
+
+func TestScanRunner_PolicyRulesWithCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	checkpoint := scanners.NewCheckpoint(path)
+
+	tagPolicies, err := scanners.ParseTagPolicies([]string{"costcenter=^CC\\d{4}$"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resourceGroups := []string{"rg1", "rg2", "rg3", "rg4"}
+	jobs := []scanJob{}
+	for _, r := range resourceGroups {
+		jobs = append(jobs, newScanJobs(r, []scanners.IAzureScanner{
+			&ruledScanner{fakeScanner: fakeScanner{name: "ruled"}},
+		})...)
+	}
+
+	rc := ReviewContext{
+		Ctx:   context.Background(),
+		ResCh: make(chan []scanners.AzureServiceResult),
+		ErrCh: make(chan error),
+	}
+	go scanRunner(&rc, "sub", jobs, testScanContext(), 8, 0, checkpoint)
+	// The policy rules are added while the checkpoint of other jobs is written, run with -race
+	err = waitForReviews(&rc, len(jobs), func(res []scanners.AzureServiceResult) {
+		addPolicyRules(res, []string{"westeurope"}, tagPolicies, 30)
+		for _, r := range res {
+			if len(r.Rules) != 4 {
+				t.Errorf("addPolicyRules() rules = %v, want the scanner rule and the 3 policy rules", r.Rules)
+			}
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := loadCheckpoint(t, checkpoint, path)
+	for _, r := range resourceGroups {
+		res, ok := loaded.Completed("sub", r, scanners.GetScannerName(jobs[0].scanner))
+		if !ok {
+			t.Fatalf("scanner should be recorded in the checkpoint for %s", r)
+		}
+		if len(res) != 1 || len(res[0].Rules) != 1 {
+			t.Errorf("checkpoint results = %v, want the results as returned by the scanner", res)
+		}
+	}
+}
+
+func TestValidateStream(t *testing.T) {
+	tests := []struct {
+		name         string
+		outputs      []string
+		logAnalytics bool
+		remediation  bool
+		outputBundle string
+		openTUI      bool
+		want         string
+	}{
+		{
+			name: "no report options",
+		},
+		{
+			name:    "additional outputs",
+			outputs: []string{"json"},
+			want:    "--output-format can't be used with --stream, the findings are not kept in memory",
+		},
+		{
+			name:         "every report option",
+			outputs:      []string{"csv"},
+			logAnalytics: true,
+			remediation:  true,
+			outputBundle: "report.zip",
+			openTUI:      true,
+			want:         "--output-format, --log-analytics, --remediation, --output-bundle, --tui can't be used with --stream, the findings are not kept in memory",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateStream(tt.outputs, tt.logAnalytics, tt.remediation, tt.outputBundle, tt.openTUI)
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if got != tt.want {
+				t.Errorf("validateStream() error = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortResults(t *testing.T) {
+	results := []scanners.AzureServiceResult{
+		{SubscriptionID: "sub2", ResourceGroup: "rg1", Type: "a", ResourceID: "1"},
+		{SubscriptionID: "sub1", ResourceGroup: "rg2", Type: "a", ResourceID: "2"},
+		{SubscriptionID: "sub1", ResourceGroup: "rg1", Type: "b", ResourceID: "3"},
+		{SubscriptionID: "sub1", ResourceGroup: "rg1", Type: "a", ResourceID: "5"},
+		{SubscriptionID: "sub1", ResourceGroup: "rg1", Type: "a", ResourceID: "4"},
+	}
+	sortResults(results)
+
+	got := []string{}
+	for _, r := range results {
+		got = append(got, r.ResourceID)
+	}
+	want := []string{"4", "5", "3", "2", "1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sortResults() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package azqr
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+// severityExitCodes - Exit code used by --fail-on for the highest broken severity.
+// Codes start at 2 so they don't collide with the exit code 1 of a failed scan.
+var severityExitCodes = map[string]int{
+	"low":    2,
+	"medium": 3,
+	"high":   4,
+}
+
+// maxFindingsExitCode - Exit code used by --max-findings when a category exceeds its threshold
+const maxFindingsExitCode = 5
+
+// severityRanks - Order of the severities, used to compare them with the --fail-on threshold
+var severityRanks = map[string]int{
+	"low":    1,
+	"medium": 2,
+	"high":   3,
+}
+
+// severitySummary - Number of broken rules per severity and per category
+type severitySummary struct {
+	High       int
+	Medium     int
+	Low        int
+	Categories map[string]int
+}
+
+// add - Counts the broken rules of the results
+func (s *severitySummary) add(results []scanners.AzureServiceResult) {
+	for _, r := range results {
+		for _, rule := range r.Rules {
+			if !rule.IsBroken {
+				continue
+			}
+			if s.Categories == nil {
+				s.Categories = map[string]int{}
+			}
+			s.Categories[rule.Category]++
+			switch strings.ToLower(rule.Severity) {
+			case "high":
+				s.High++
+			case "medium":
+				s.Medium++
+			case "low":
+				s.Low++
+			}
+		}
+	}
+}
+
+// write - Writes the machine parseable summary line
+func (s *severitySummary) write(w io.Writer) {
+	fmt.Fprintf(w, "azqr summary: high=%d medium=%d low=%d\n", s.High, s.Medium, s.Low)
+}
+
+// exitCode - Returns the exit code for the highest broken severity at or above failOn, or 0
+func (s *severitySummary) exitCode(failOn string) int {
+	threshold, ok := severityRanks[strings.ToLower(failOn)]
+	if !ok {
+		return 0
+	}
+
+	counts := map[string]int{"high": s.High, "medium": s.Medium, "low": s.Low}
+	for _, severity := range []string{"high", "medium", "low"} {
+		if severityRanks[severity] < threshold {
+			break
+		}
+		if counts[severity] > 0 {
+			return severityExitCodes[severity]
+		}
+	}
+	return 0
+}
+
+// validateFailOn - Returns an error if failOn is not empty nor a known severity
+func validateFailOn(failOn string) error {
+	if failOn == "" {
+		return nil
+	}
+	if _, ok := severityRanks[strings.ToLower(failOn)]; !ok {
+		return fmt.Errorf("invalid --fail-on value %q, use high, medium or low", failOn)
+	}
+	return nil
+}
+
+// exceededCategories - Returns the sorted categories whose broken rules exceed their --max-findings threshold,
+// as category=broken (max threshold). Categories are compared case insensitively.
+func (s *severitySummary) exceededCategories(maxFindings map[string]int) []string {
+	exceeded := []string{}
+	for category, max := range maxFindings {
+		broken := 0
+		for c, count := range s.Categories {
+			if strings.EqualFold(c, category) {
+				broken += count
+			}
+		}
+		if broken > max {
+			exceeded = append(exceeded, fmt.Sprintf("%s=%d (max %d)", category, broken, max))
+		}
+	}
+	sort.Strings(exceeded)
+	return exceeded
+}
+
+// validateMaxFindings - Returns an error if a --max-findings threshold is negative
+func validateMaxFindings(maxFindings map[string]int) error {
+	for category, max := range maxFindings {
+		if max < 0 {
+			return fmt.Errorf("invalid --max-findings value %s=%d, thresholds can't be negative", category, max)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package azqr
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+func TestSeveritySummary(t *testing.T) {
+	summary := severitySummary{}
+	summary.add([]scanners.AzureServiceResult{
+		{
+			Rules: map[string]scanners.AzureRuleResult{
+				"aks-001": {Severity: "High", IsBroken: true},
+				"aks-002": {Severity: "Medium", IsBroken: true},
+				"aks-003": {Severity: "Medium", IsBroken: true},
+				"aks-004": {Severity: "Low", IsBroken: false},
+			},
+		},
+	})
+
+	out := &bytes.Buffer{}
+	summary.write(out)
+	if want := "azqr summary: high=1 medium=2 low=0\n"; out.String() != want {
+		t.Errorf("write() = %q, want %q", out.String(), want)
+	}
+
+	tests := []struct {
+		failOn string
+		want   int
+	}{
+		{failOn: "", want: 0},
+		{failOn: "high", want: 4},
+		{failOn: "Medium", want: 4},
+		{failOn: "low", want: 4},
+	}
+	for _, tt := range tests {
+		if got := summary.exitCode(tt.failOn); got != tt.want {
+			t.Errorf("exitCode(%q) = %d, want %d", tt.failOn, got, tt.want)
+		}
+	}
+
+	mediumOnly := severitySummary{Medium: 1}
+	if got := mediumOnly.exitCode("high"); got != 0 {
+		t.Errorf("exitCode(high) = %d, want 0", got)
+	}
+	if got := mediumOnly.exitCode("low"); got != 3 {
+		t.Errorf("exitCode(low) = %d, want 3", got)
+	}
+
+	if err := validateFailOn("critical"); err == nil {
+		t.Errorf("validateFailOn(critical) should fail")
+	}
+}
+
+func TestSeveritySummary_ExceededCategories(t *testing.T) {
+	summary := severitySummary{}
+	summary.add([]scanners.AzureServiceResult{
+		{
+			Rules: map[string]scanners.AzureRuleResult{
+				"aks-001": {Category: "Security", Severity: "High", IsBroken: true},
+				"aks-002": {Category: "Governance", Severity: "Low", IsBroken: true},
+				"aks-003": {Category: "Governance", Severity: "Low", IsBroken: true},
+				"aks-004": {Category: "Security", Severity: "Medium", IsBroken: false},
+			},
+		},
+	})
+
+	tests := []struct {
+		name        string
+		maxFindings map[string]int
+		want        []string
+	}{
+		{
+			name:        "no thresholds",
+			maxFindings: map[string]int{},
+			want:        []string{},
+		},
+		{
+			name:        "thresholds not exceeded",
+			maxFindings: map[string]int{"Security": 1, "Governance": 2, "Networking": 0},
+			want:        []string{},
+		},
+		{
+			name:        "thresholds exceeded",
+			maxFindings: map[string]int{"security": 0, "Governance": 1},
+			want:        []string{"Governance=2 (max 1)", "security=1 (max 0)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := summary.exceededCategories(tt.maxFindings); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("exceededCategories() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if err := validateMaxFindings(map[string]int{"Security": -1}); err == nil {
+		t.Errorf("validateMaxFindings(Security=-1) should fail")
+	}
+}
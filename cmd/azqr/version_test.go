@@ -0,0 +1,32 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package azqr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestVersionCmd(t *testing.T) {
+	version, commit, date = "1.2.3", "abc1234", "2023-05-01T00:00:00Z"
+	defer func() {
+		version, commit, date = "dev", "none", "unknown"
+	}()
+
+	out := &bytes.Buffer{}
+	rootCmd.SetOut(out)
+	rootCmd.SetArgs([]string{"version"})
+	defer rootCmd.SetArgs(nil)
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"1.2.3", "abc1234", "2023-05-01T00:00:00Z"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("version output %q should contain %q", out.String(), want)
+		}
+	}
+}
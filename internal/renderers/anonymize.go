@@ -0,0 +1,151 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+var (
+	// errorCode - Matches the error code of an Azure SDK response error
+	errorCode = regexp.MustCompile(`ERROR CODE: (\S+)`)
+	// resultResourceID - Matches the resource ids embedded in a rule result
+	resultResourceID = regexp.MustCompile(`(?i)/subscriptions/[^\s,;()]+`)
+	// resultChildName - Matches the names of child resources (e.g. node pools or event hubs) listed as "name: value" in a rule result
+	resultChildName = regexp.MustCompile(`(^|, |; )([^\s,;:]+): `)
+)
+
+// AnonymizeReportData - Replaces subscription ids, resource groups, resource names and ids with stable hashes so reports can be shared.
+// The same name always gets the same hash, so findings for a resource can still be correlated.
+// Rule results are preserved with the resource ids, the names of the resource and the names of its child resources they embed hashed.
+// Tags and error messages can embed names that can't be told apart, so they are dropped: errors keep only their Azure error code.
+func AnonymizeReportData(data ReportData) ReportData {
+	mainData := make([]scanners.AzureServiceResult, 0, len(data.MainData))
+	for _, r := range data.MainData {
+		rules := make(map[string]scanners.AzureRuleResult, len(r.Rules))
+		for k, rule := range r.Rules {
+			rule.Result = anonymizeResult(rule.Result, r)
+			rules[k] = rule
+		}
+		r.Rules = rules
+		r.SubscriptionID = anonymizeSubscriptionID(r.SubscriptionID)
+		r.ResourceGroup = anonymize("rg", r.ResourceGroup)
+		r.ServiceName = anonymize("name", r.ServiceName)
+		r.ResourceID = anonymize("id", r.ResourceID)
+		r.Tags = nil
+		mainData = append(mainData, r)
+	}
+	data.MainData = mainData
+
+	defenderData := make([]scanners.DefenderResult, 0, len(data.DefenderData))
+	for _, r := range data.DefenderData {
+		r.SubscriptionID = anonymizeSubscriptionID(r.SubscriptionID)
+		defenderData = append(defenderData, r)
+	}
+	data.DefenderData = defenderData
+
+	advisorData := make([]scanners.AdvisorResult, 0, len(data.AdvisorData))
+	for _, r := range data.AdvisorData {
+		r.SubscriptionID = anonymizeSubscriptionID(r.SubscriptionID)
+		r.Name = anonymize("name", r.Name)
+		r.ResourceID = anonymize("id", r.ResourceID)
+		advisorData = append(advisorData, r)
+	}
+	data.AdvisorData = advisorData
+
+	errors := make([]scanners.ScanError, 0, len(data.Errors))
+	for _, e := range data.Errors {
+		e.SubscriptionID = anonymizeSubscriptionID(e.SubscriptionID)
+		e.ResourceGroup = anonymize("rg", e.ResourceGroup)
+		e.ResourceID = anonymize("id", e.ResourceID)
+		e.Error = anonymizeError(e.Error)
+		errors = append(errors, e)
+	}
+	data.Errors = errors
+
+	return data
+}
+
+func anonymize(prefix, value string) string {
+	if value == "" {
+		return ""
+	}
+	return prefix + "-" + hash(value)[:12]
+}
+
+// anonymizeSubscriptionID - Hashes the subscription id keeping the format of a GUID, so it can still be masked
+func anonymizeSubscriptionID(subscriptionID string) string {
+	if subscriptionID == "" {
+		return ""
+	}
+	h := hash(subscriptionID)
+	return fmt.Sprintf("%s-%s-%s-%s-%s", h[0:8], h[8:12], h[12:16], h[16:20], h[20:32])
+}
+
+// anonymizeResult - Hashes the identifiers embedded in the result of a rule evaluated for the resource,
+// so values such as SKUs, SLAs or modes are kept
+func anonymizeResult(result string, r scanners.AzureServiceResult) string {
+	if result == "" {
+		return ""
+	}
+
+	result = resultResourceID.ReplaceAllStringFunc(result, func(id string) string {
+		return anonymize("id", id)
+	})
+	result = resultChildName.ReplaceAllStringFunc(result, func(m string) string {
+		sub := resultChildName.FindStringSubmatch(m)
+		return sub[1] + anonymize("name", sub[2]) + ": "
+	})
+	result = replaceName(result, r.SubscriptionID, anonymizeSubscriptionID(r.SubscriptionID))
+	result = replaceName(result, r.ResourceGroup, anonymize("rg", r.ResourceGroup))
+	return replaceName(result, r.ServiceName, anonymize("name", r.ServiceName))
+}
+
+// replaceName - Replaces the occurrences of name in s that are not part of a longer name
+func replaceName(s, name, replacement string) string {
+	if name == "" {
+		return s
+	}
+
+	b := strings.Builder{}
+	for {
+		i := strings.Index(strings.ToLower(s), strings.ToLower(name))
+		if i < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		end := i + len(name)
+		if (i > 0 && isNameChar(s[i-1])) || (end < len(s) && isNameChar(s[end])) {
+			b.WriteString(s[:end])
+		} else {
+			b.WriteString(s[:i])
+			b.WriteString(replacement)
+		}
+		s = s[end:]
+	}
+}
+
+// isNameChar - Returns true for the characters allowed in Azure resource names
+func isNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.'
+}
+
+// anonymizeError - Keeps only the Azure error code, the message can name the resources that failed
+func anonymizeError(err string) string {
+	if m := errorCode.FindStringSubmatch(err); m != nil {
+		return m[1]
+	}
+	return "error details removed by --anonymize"
+}
+
+func hash(value string) string {
+	h := sha256.Sum256([]byte(strings.ToLower(value)))
+	return hex.EncodeToString(h[:])
+}
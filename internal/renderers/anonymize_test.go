@@ -0,0 +1,150 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+func TestAnonymizeReportData(t *testing.T) {
+	subscriptionID := "a1b2c3d4-e5f6-4a5b-9c8d-123456789abc"
+	team := "payments"
+	data := ReportData{
+		MainData: []scanners.AzureServiceResult{
+			{
+				SubscriptionID: subscriptionID,
+				ResourceGroup:  "rg-payments",
+				ResourceID:     "/subscriptions/a1b2c3d4-e5f6-4a5b-9c8d-123456789abc/resourceGroups/rg-payments/providers/Microsoft.ContainerService/managedClusters/aks-payments",
+				ServiceName:    "aks-payments",
+				Tags:           map[string]*string{"team": &team},
+				Rules: map[string]scanners.AzureRuleResult{
+					"aks-001": {Id: "aks-001", IsBroken: true, Result: "nodepool-payments: 1, system: 3"},
+					"aks-002": {Id: "aks-002", IsBroken: true, Result: "aks-payments uses /subscriptions/a1b2c3d4-e5f6-4a5b-9c8d-123456789abc/resourceGroups/rg-payments/providers/Microsoft.Network/virtualNetworks/vnet-payments"},
+					"SKU":     {Id: "aks-005", Result: "Standard"},
+				},
+			},
+			{
+				SubscriptionID: subscriptionID,
+				ResourceGroup:  "rg-payments",
+				ResourceID:     "/subscriptions/a1b2c3d4-e5f6-4a5b-9c8d-123456789abc/resourceGroups/rg-payments/providers/Microsoft.Storage/storageAccounts/stpayments",
+				ServiceName:    "stpayments",
+			},
+			{
+				SubscriptionID: subscriptionID,
+				ResourceGroup:  "rg-payments",
+				ResourceID:     "/subscriptions/a1b2c3d4-e5f6-4a5b-9c8d-123456789abc/resourceGroups/rg-payments/providers/Microsoft.ContainerService/managedClusters/aks-payments",
+				ServiceName:    "aks-payments",
+			},
+		},
+		DefenderData: []scanners.DefenderResult{
+			{SubscriptionID: subscriptionID, Name: "VirtualMachines", Tier: "Standard"},
+		},
+		AdvisorData: []scanners.AdvisorResult{
+			{
+				SubscriptionID: subscriptionID,
+				Name:           "aks-payments",
+				ResourceID:     "/subscriptions/a1b2c3d4-e5f6-4a5b-9c8d-123456789abc/resourceGroups/rg-payments/providers/Microsoft.ContainerService/managedClusters/aks-payments",
+			},
+		},
+		Errors: []scanners.ScanError{
+			{
+				Scanner:        "*aks.AKSScanner",
+				SubscriptionID: subscriptionID,
+				ResourceGroup:  "rg-payments",
+				Error:          "GET https://management.azure.com/subscriptions/a1b2c3d4-e5f6-4a5b-9c8d-123456789abc/resourceGroups/rg-payments\n--------------------------------------------------------------------------------\nRESPONSE 403: 403 Forbidden\nERROR CODE: AuthorizationFailed\n",
+			},
+			{
+				Scanner:       "*st.StorageScanner",
+				ResourceGroup: "rg-payments",
+				Error:         "stpayments not found",
+			},
+			{
+				Rule:           "aks-001",
+				SubscriptionID: subscriptionID,
+				ResourceGroup:  "rg-payments",
+				ResourceID:     "/subscriptions/a1b2c3d4-e5f6-4a5b-9c8d-123456789abc/resourceGroups/rg-payments/providers/Microsoft.ContainerService/managedClusters/aks-payments",
+				Error:          "checking diagnostic settings for aks-payments failed",
+			},
+		},
+	}
+
+	got := AnonymizeReportData(data)
+
+	report, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"aks-payments", "stpayments", "rg-payments", "nodepool-payments", team, subscriptionID} {
+		if strings.Contains(string(report), name) {
+			t.Errorf("AnonymizeReportData() leaks %s", name)
+		}
+	}
+
+	if got.MainData[0].ServiceName != got.MainData[2].ServiceName || got.MainData[0].ServiceName != got.AdvisorData[0].Name {
+		t.Errorf("AnonymizeReportData() should hash the same name consistently")
+	}
+	if got.MainData[0].ServiceName == got.MainData[1].ServiceName {
+		t.Errorf("AnonymizeReportData() should hash different names differently")
+	}
+	if got.MainData[0].ResourceID != got.AdvisorData[0].ResourceID {
+		t.Errorf("AnonymizeReportData() should hash the same resource id consistently")
+	}
+	if got.MainData[0].ResourceGroup != got.Errors[0].ResourceGroup {
+		t.Errorf("AnonymizeReportData() should hash the same resource group consistently")
+	}
+	if got.MainData[0].SubscriptionID != got.DefenderData[0].SubscriptionID {
+		t.Errorf("AnonymizeReportData() should hash the same subscription id consistently")
+	}
+	if masked := scanners.MaskSubscriptionID(got.MainData[0].SubscriptionID, true); len(masked) != len(subscriptionID) {
+		t.Errorf("AnonymizeReportData() subscription id can't be masked: %s", masked)
+	}
+	if !got.MainData[0].Rules["aks-001"].IsBroken {
+		t.Errorf("AnonymizeReportData() should preserve rule results")
+	}
+	if got.MainData[0].Rules["aks-001"].Result != anonymize("name", "nodepool-payments")+": 1, "+anonymize("name", "system")+": 3" {
+		t.Errorf("AnonymizeReportData() result = %s, want the node pool names hashed and their values kept", got.MainData[0].Rules["aks-001"].Result)
+	}
+	if got.Errors[2].ResourceID != got.MainData[0].ResourceID {
+		t.Errorf("AnonymizeReportData() should hash the resource id of rule errors as in the findings")
+	}
+	if got.Errors[0].Error != "AuthorizationFailed" {
+		t.Errorf("AnonymizeReportData() error = %s, want the error code", got.Errors[0].Error)
+	}
+	if data.MainData[0].ServiceName != "aks-payments" || data.MainData[0].Rules["aks-001"].Result != "nodepool-payments: 1, system: 3" {
+		t.Errorf("AnonymizeReportData() should not modify the original data")
+	}
+}
+
+func TestAnonymizeReportData_InformationalRules(t *testing.T) {
+	data := ReportData{
+		MainData: []scanners.AzureServiceResult{
+			{
+				SubscriptionID: "a1b2c3d4-e5f6-4a5b-9c8d-123456789abc",
+				ResourceGroup:  "rg-payments",
+				ServiceName:    "aks-payments",
+				Rules: map[string]scanners.AzureRuleResult{
+					"SKU":     {Id: "aks-005", Result: "Standard"},
+					"SLA":     {Id: "aks-004", Result: "99.95%"},
+					"aks-034": {Id: "aks-034", Result: "overlay"},
+					"aks-002": {Id: "aks-002"},
+				},
+			},
+		},
+	}
+
+	findings := toFindings(AnonymizeReportData(data).MainData, false, false)
+	got := map[string]string{}
+	for _, f := range findings {
+		got[f.RuleID] = f.Result
+	}
+	want := map[string]string{"aks-005": "Standard", "aks-004": "99.95%", "aks-034": "overlay"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findings of the anonymized report = %v, want %v", got, want)
+	}
+}
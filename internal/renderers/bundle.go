@@ -0,0 +1,104 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// CreateBundle - Renders the selected report formats and writes their files into a single ZIP file.
+// The console renderer doesn't write any file, so it's skipped.
+func CreateBundle(data ReportData, outputs []string, filename string) error {
+	log.Printf("Generating Report Bundle: %s", filename)
+	dir, err := os.MkdirTemp("", "azqr-bundle-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	bundleData := data
+	bundleData.OutputFileName = filepath.Join(dir, filepath.Base(data.OutputFileName))
+	for _, o := range outputs {
+		if o == "console" {
+			continue
+		}
+		r, exists := Get(o)
+		if !exists {
+			return fmt.Errorf("unknown output format %s", o)
+		}
+		if err := r.Render(bundleData); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := writeBundle(f, dir); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeBundle - Writes the files rendered in dir into a ZIP, ordered by name
+func writeBundle(w io.Writer, dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		zf, err := zw.Create(e.Name())
+		if err != nil {
+			return err
+		}
+		src, err := os.Open(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(zf, src)
+		src.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func writeFindingsJSON(w io.Writer, findings []JSONLinesFinding) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(findings)
+}
+
+// writeFindingsCSV - Writes one row per rule evaluated for a resource. Values are quoted by encoding/csv when needed.
+func writeFindingsCSV(w io.Writer, findings []JSONLinesFinding) error {
+	cw := csv.NewWriter(w)
+	heathers := []string{"RuleID", "Category", "Subcategory", "Severity", "Broken", "Result", "ResourceName", "ResourceGroup", "SubscriptionId", "Url"}
+	if err := cw.Write(heathers); err != nil {
+		return err
+	}
+	for _, f := range findings {
+		row := []string{f.RuleID, f.Category, f.Subcategory, f.Severity, strconv.FormatBool(f.Broken), f.Result, f.Name, f.ResourceGroup, f.SubscriptionID, f.Learn}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
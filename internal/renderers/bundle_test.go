@@ -0,0 +1,76 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"archive/zip"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+func TestCreateBundle(t *testing.T) {
+	dir := t.TempDir()
+	data := ReportData{
+		OutputFileName: filepath.Join(dir, "azqr_report_2023_01_01_T000000"),
+		MainData: []scanners.AzureServiceResult{
+			{
+				ServiceName: "aks-test",
+				Rules: map[string]scanners.AzureRuleResult{
+					"aks-001": {Id: "aks-001", Severity: "Medium", IsBroken: true},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		outputs []string
+		want    []string
+	}{
+		{
+			name:    "excel, json and csv",
+			outputs: []string{"excel", "console", "json", "csv"},
+			want: []string{
+				"azqr_report_2023_01_01_T000000.csv",
+				"azqr_report_2023_01_01_T000000.json",
+				"azqr_report_2023_01_01_T000000.xlsx",
+			},
+		},
+		{
+			name:    "only the selected formats",
+			outputs: []string{"md"},
+			want: []string{
+				"azqr_report_2023_01_01_T000000.md",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(dir, "report.zip")
+			if err := CreateBundle(data, tt.outputs, filename); err != nil {
+				t.Fatal(err)
+			}
+
+			r, err := zip.OpenReader(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+			got := []string{}
+			for _, f := range r.File {
+				got = append(got, f.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CreateBundle() entries = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if err := CreateBundle(data, []string{"unknown"}, filepath.Join(dir, "unknown.zip")); err == nil {
+		t.Error("CreateBundle() with an unknown format, want an error")
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+var severityColors = map[string]string{
+	"High":   "\033[31m",
+	"Medium": "\033[33m",
+	"Low":    "\033[34m",
+}
+
+const colorReset = "\033[0m"
+
+func init() {
+	Register("console", consoleRenderer{})
+}
+
+// consoleRenderer - Prints a summary of the broken rules to stdout
+type consoleRenderer struct{}
+
+// Render - Prints a summary of the broken rules to stdout.
+// Severities are colorized only when stdout is a terminal and neither --no-color nor NO_COLOR are set.
+func (consoleRenderer) Render(data ReportData) error {
+	color := !data.NoColor && os.Getenv("NO_COLOR") == "" && isTerminal(os.Stdout)
+	renderConsole(os.Stdout, data, color)
+	return nil
+}
+
+func renderConsole(out io.Writer, data ReportData, color bool) {
+	type recommendation struct {
+		id, severity, description string
+		count                     int
+	}
+
+	recommendations := map[string]*recommendation{}
+	severities := map[string]int{}
+	for _, d := range data.MainData {
+		for _, r := range d.Rules {
+			if !r.IsBroken {
+				continue
+			}
+			severities[r.Severity]++
+			rec, exists := recommendations[r.Id]
+			if !exists {
+				rec = &recommendation{id: r.Id, severity: r.Severity, description: r.Description}
+				recommendations[r.Id] = rec
+			}
+			rec.count++
+		}
+	}
+
+	ids := make([]string, 0, len(recommendations))
+	for id := range recommendations {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	fmt.Fprintln(out, "Summary:")
+	for _, s := range []string{"High", "Medium", "Low"} {
+		fmt.Fprintf(out, "  %s: %d\n", colorize(s, s, color), severities[s])
+	}
+	fmt.Fprintln(out)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Id\tSeverity\tResources\tDescription")
+	for _, id := range ids {
+		r := recommendations[id]
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", r.id, colorize(r.severity, r.severity, color), r.count, r.description)
+	}
+	_ = w.Flush()
+
+	if len(data.Errors) == 0 {
+		return
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Errors (results are partial):")
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Scanner/Rule\tScope\tError")
+	for _, e := range toScanErrors(data.Errors, data.Mask) {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", errorSource(e), errorScope(e), firstLine(e.Error))
+	}
+	_ = w.Flush()
+}
+
+// errorSource - Returns the scanner that failed or, for rule errors, the rule
+func errorSource(e scanners.ScanError) string {
+	if e.Rule != "" {
+		return e.Rule
+	}
+	return e.Scanner
+}
+
+// errorScope - Returns the resource of a rule error, or the subscription and resource group the scanner failed in
+func errorScope(e scanners.ScanError) string {
+	if e.ResourceID != "" {
+		return e.ResourceID
+	}
+	return fmt.Sprintf("%s/%s", e.SubscriptionID, e.ResourceGroup)
+}
+
+// firstLine - Returns the first non empty line of an error message, Azure SDK errors span many lines
+func firstLine(message string) string {
+	for _, line := range strings.Split(message, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+func colorize(text, severity string, color bool) string {
+	c, exists := severityColors[severity]
+	if !color || !exists {
+		return text
+	}
+	return c + text + colorReset
+}
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,84 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+func getConsoleReportData() ReportData {
+	return ReportData{
+		MainData: []scanners.AzureServiceResult{
+			{
+				ServiceName: "aks-test",
+				Rules: map[string]scanners.AzureRuleResult{
+					"aks-001": {Id: "aks-001", Severity: "Medium", Description: "diagnostics", IsBroken: true},
+					"aks-002": {Id: "aks-002", Severity: "High", Description: "zones", IsBroken: true},
+					"aks-006": {Id: "aks-006", Severity: "Low", Description: "caf", IsBroken: false},
+				},
+			},
+		},
+	}
+}
+
+func TestRenderConsole_NoColor(t *testing.T) {
+	var out bytes.Buffer
+	renderConsole(&out, getConsoleReportData(), false)
+
+	if strings.Contains(out.String(), "\033[") {
+		t.Errorf("renderConsole() with no color emitted ANSI escape codes: %q", out.String())
+	}
+	if !strings.Contains(out.String(), "aks-002") || strings.Contains(out.String(), "aks-006") {
+		t.Errorf("renderConsole() should only list broken rules, got: %q", out.String())
+	}
+}
+
+func TestRenderConsole_Color(t *testing.T) {
+	var out bytes.Buffer
+	renderConsole(&out, getConsoleReportData(), true)
+
+	if !strings.Contains(out.String(), severityColors["High"]+"High"+colorReset) {
+		t.Errorf("renderConsole() with color should colorize severities, got: %q", out.String())
+	}
+}
+
+func TestRenderConsole_Errors(t *testing.T) {
+	data := getConsoleReportData()
+	data.Mask = true
+	data.Errors = []scanners.ScanError{
+		{
+			Scanner:        "*kv.KeyVaultScanner",
+			SubscriptionID: "00000000-0000-0000-0000-000000000000",
+			ResourceGroup:  "rg",
+			Error:          "GET https://management.azure.com/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.KeyVault/vaults\n--------------------------------------------------------------------------------\nRESPONSE 403: 403 Forbidden\nERROR CODE: AuthorizationFailed",
+		},
+	}
+
+	var out bytes.Buffer
+	renderConsole(&out, data, false)
+
+	errorsSection := strings.SplitN(out.String(), "Errors (results are partial):", 2)
+	if len(errorsSection) != 2 {
+		t.Fatalf("renderConsole() should print an errors section, got: %q", out.String())
+	}
+	if !strings.Contains(errorsSection[1], "*kv.KeyVaultScanner") || !strings.Contains(errorsSection[1], "xxxxxxxx-xxxx-xxxx-xxxx-xxxxx0000000/rg") {
+		t.Errorf("renderConsole() errors section should list the scanner and the masked scope, got: %q", errorsSection[1])
+	}
+	if !strings.Contains(errorsSection[1], "GET https://management.azure.com/") || strings.Contains(errorsSection[1], "RESPONSE 403") {
+		t.Errorf("renderConsole() errors section should show the first line of the error, got: %q", errorsSection[1])
+	}
+}
+
+func TestRenderConsole_NoErrors(t *testing.T) {
+	var out bytes.Buffer
+	renderConsole(&out, getConsoleReportData(), false)
+
+	if strings.Contains(out.String(), "Errors") {
+		t.Errorf("renderConsole() should not print an errors section without errors, got: %q", out.String())
+	}
+}
@@ -13,27 +13,49 @@ import (
 	"github.com/xuri/excelize/v2"
 )
 
-func CreateExcelReport(data ReportData) {
-	if len(data.MainData) > 0 {
-		filename := fmt.Sprintf("%s.xlsx", data.OutputFileName)
-		log.Printf("Generating Report: %s", filename)
-		f := excelize.NewFile()
-		defer func() {
-			if err := f.Close(); err != nil {
-				log.Fatal(err)
-			}
-		}()
+func init() {
+	Register("excel", excelRenderer{})
+}
 
-		renderOverview(f, data)
-		renderRecommendations(f, data)
-		renderDefender(f, data)
-		renderServices(f, data)
-		renderAdvisor(f, data)
+// excelRenderer - Writes the Excel report
+type excelRenderer struct{}
 
-		if err := f.SaveAs(filename); err != nil {
-			log.Fatal(err)
-		}
+// Render - Writes the Excel report, if there are results, to <OutputFileName>.xlsx
+func (excelRenderer) Render(data ReportData) error {
+	if len(data.MainData) == 0 {
+		return nil
 	}
+
+	filename := fmt.Sprintf("%s.xlsx", data.OutputFileName)
+	log.Printf("Generating Report: %s", filename)
+	f := newExcelReport(data)
+	if err := f.SaveAs(filename); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// newExcelReport - Renders the report sheets in a new Excel file
+func newExcelReport(data ReportData) *excelize.File {
+	f := excelize.NewFile()
+
+	err := f.SetDocProps(&excelize.DocProperties{
+		Creator:     "azqr",
+		Version:     data.Version,
+		Description: fmt.Sprintf("Azure Quick Review %s (commit: %s, built: %s)", data.Version, data.Commit, data.BuildDate),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	renderOverview(f, data)
+	renderRecommendations(f, data)
+	renderRemediation(f, data)
+	renderDefender(f, data)
+	renderServices(f, data)
+	renderAdvisor(f, data)
+	return f
 }
 
 func autofit(f *excelize.File, sheetName string) error {
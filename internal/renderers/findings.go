@@ -0,0 +1,63 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+func init() {
+	Register("json", findingsRenderer{extension: "json", write: writeFindingsJSON, writeErrors: writeErrorsJSON})
+	Register("csv", findingsRenderer{extension: "csv", write: writeFindingsCSV, everyRule: true})
+}
+
+// findingsRenderer - Writes the findings, one per rule result, to <OutputFileName>.<extension>.
+// If writeErrors is set and some scanners failed, their errors are written to <OutputFileName>.errors.<extension>
+// With everyRule passed rules are always written, otherwise only with ShowPassed.
+type findingsRenderer struct {
+	extension   string
+	write       func(w io.Writer, findings []JSONLinesFinding) error
+	writeErrors func(w io.Writer, errors []scanners.ScanError) error
+	everyRule   bool
+}
+
+// Render - Writes the findings of the report
+func (r findingsRenderer) Render(data ReportData) error {
+	filename := fmt.Sprintf("%s.%s", data.OutputFileName, r.extension)
+	log.Printf("Generating Report: %s", filename)
+	err := writeFile(filename, func(w io.Writer) error {
+		return r.write(w, toFindings(data.MainData, data.Mask, data.ShowPassed || r.everyRule))
+	})
+	if err != nil || r.writeErrors == nil || len(data.Errors) == 0 {
+		return err
+	}
+
+	filename = fmt.Sprintf("%s.errors.%s", data.OutputFileName, r.extension)
+	log.Printf("Generating Errors Report: %s", filename)
+	return writeFile(filename, func(w io.Writer) error {
+		return r.writeErrors(w, toScanErrors(data.Errors, data.Mask))
+	})
+}
+
+// toScanErrors - Returns the scan errors with their subscription ids, also in the resource ids of rule errors, masked
+func toScanErrors(errors []scanners.ScanError, mask bool) []scanners.ScanError {
+	masked := make([]scanners.ScanError, 0, len(errors))
+	for _, e := range errors {
+		e.SubscriptionID = scanners.MaskSubscriptionID(e.SubscriptionID, mask)
+		e.ResourceID = scanners.MaskResourceID(e.ResourceID, mask)
+		masked = append(masked, e)
+	}
+	return masked
+}
+
+func writeErrorsJSON(w io.Writer, errors []scanners.ScanError) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(errors)
+}
@@ -0,0 +1,146 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+func init() {
+	Register("index", indexRenderer{})
+}
+
+// SubscriptionIndexEntry - Compliance summary of a subscription, linking to its report
+type SubscriptionIndexEntry struct {
+	SubscriptionID string `json:"subscriptionId"`
+	Resources      int    `json:"resources"`
+	Rules          int    `json:"rules"`
+	Broken         int    `json:"broken"`
+	Score          int    `json:"score"`
+	Report         string `json:"report"`
+	// id - Unmasked subscription id, results are grouped by it since masked ids of different subscriptions can be equal
+	id string
+}
+
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Azure Quick Review</title>
+</head>
+<body>
+<h1>Azure Quick Review</h1>
+<table>
+<tr><th>Subscription</th><th>Resources</th><th>Broken Rules</th><th>Compliance Score</th></tr>
+{{- range .}}
+<tr><td><a href="{{.Report}}">{{.SubscriptionID}}</a></td><td>{{.Resources}}</td><td>{{.Broken}}</td><td>{{.Score}}%</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+// indexRenderer - Writes a report per subscription and an index, as HTML and JSON, linking to them
+type indexRenderer struct{}
+
+// Render - Writes <OutputFileName>_<subscription>.json for each subscription, plus <OutputFileName>.index.html and <OutputFileName>.index.json
+func (indexRenderer) Render(data ReportData) error {
+	bySubscription := map[string][]scanners.AzureServiceResult{}
+	for _, d := range data.MainData {
+		bySubscription[d.SubscriptionID] = append(bySubscription[d.SubscriptionID], d)
+	}
+
+	entries := getSubscriptionIndex(data)
+	for _, e := range entries {
+		filename := filepath.Join(filepath.Dir(data.OutputFileName), e.Report)
+		findings := toFindings(bySubscription[e.id], data.Mask, data.ShowPassed)
+		if err := writeFile(filename, func(w io.Writer) error {
+			return writeFindingsJSON(w, findings)
+		}); err != nil {
+			return err
+		}
+	}
+
+	filename := fmt.Sprintf("%s.index.html", data.OutputFileName)
+	log.Printf("Generating Report Index: %s", filename)
+	if err := writeFile(filename, func(w io.Writer) error {
+		return indexTemplate.Execute(w, entries)
+	}); err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fmt.Sprintf("%s.index.json", data.OutputFileName), b, 0644)
+}
+
+// getSubscriptionIndex - Returns the compliance summary of each subscription, ordered by subscription id.
+// The score is the percentage of evaluated rules that are not broken.
+func getSubscriptionIndex(data ReportData) []SubscriptionIndexEntry {
+	entries := map[string]*SubscriptionIndexEntry{}
+	for _, d := range data.MainData {
+		e, exists := entries[d.SubscriptionID]
+		if !exists {
+			e = &SubscriptionIndexEntry{
+				SubscriptionID: scanners.MaskSubscriptionID(d.SubscriptionID, data.Mask),
+				id:             d.SubscriptionID,
+			}
+			entries[d.SubscriptionID] = e
+		}
+		e.Resources++
+		for _, r := range d.Rules {
+			e.Rules++
+			if r.IsBroken {
+				e.Broken++
+			}
+		}
+	}
+
+	index := []SubscriptionIndexEntry{}
+	for _, e := range entries {
+		e.Score = 100
+		if e.Rules > 0 {
+			e.Score = (e.Rules - e.Broken) * 100 / e.Rules
+		}
+		index = append(index, *e)
+	}
+	sort.Slice(index, func(i, j int) bool {
+		return index[i].id < index[j].id
+	})
+
+	// Reports are named after the displayed subscription id, numbered when masked ids are equal
+	reports := map[string]int{}
+	for i := range index {
+		name := fmt.Sprintf("%s_%s", filepath.Base(data.OutputFileName), index[i].SubscriptionID)
+		reports[name]++
+		if n := reports[name]; n > 1 {
+			name = fmt.Sprintf("%s_%d", name, n)
+		}
+		index[i].Report = name + ".json"
+	}
+	return index
+}
+
+func writeFile(filename string, write func(w io.Writer) error) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
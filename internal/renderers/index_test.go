@@ -0,0 +1,112 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+func getIndexReportData(dir string) ReportData {
+	return ReportData{
+		OutputFileName: filepath.Join(dir, "azqr_report"),
+		MainData: []scanners.AzureServiceResult{
+			{
+				SubscriptionID: "22222222-2222-2222-2222-222222222222",
+				Rules: map[string]scanners.AzureRuleResult{
+					"aks-001": {Id: "aks-001", IsBroken: true},
+					"aks-002": {Id: "aks-002"},
+				},
+			},
+			{
+				SubscriptionID: "11111111-1111-1111-1111-111111111111",
+				Rules: map[string]scanners.AzureRuleResult{
+					"st-001": {Id: "st-001"},
+				},
+			},
+		},
+	}
+}
+
+func TestGetSubscriptionIndex(t *testing.T) {
+	got := getSubscriptionIndex(getIndexReportData("out"))
+	want := []SubscriptionIndexEntry{
+		{
+			SubscriptionID: "11111111-1111-1111-1111-111111111111",
+			Resources:      1,
+			Rules:          1,
+			Broken:         0,
+			Score:          100,
+			Report:         "azqr_report_11111111-1111-1111-1111-111111111111.json",
+			id:             "11111111-1111-1111-1111-111111111111",
+		},
+		{
+			SubscriptionID: "22222222-2222-2222-2222-222222222222",
+			Resources:      1,
+			Rules:          2,
+			Broken:         1,
+			Score:          50,
+			Report:         "azqr_report_22222222-2222-2222-2222-222222222222.json",
+			id:             "22222222-2222-2222-2222-222222222222",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getSubscriptionIndex() = %v, want %v", got, want)
+	}
+}
+
+func TestIndexRenderer(t *testing.T) {
+	dir := t.TempDir()
+	data := getIndexReportData(dir)
+
+	r, _ := Get("index")
+	if err := r.Render(data); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	b, err := os.ReadFile(data.OutputFileName + ".index.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(b)
+	for _, s := range []string{"11111111-1111-1111-1111-111111111111", "22222222-2222-2222-2222-222222222222"} {
+		report := "azqr_report_" + s + ".json"
+		if !strings.Contains(html, `href="`+report+`"`) {
+			t.Errorf("Render() index doesn't link to %s", report)
+		}
+		if _, err := os.Stat(filepath.Join(dir, report)); err != nil {
+			t.Errorf("Render() didn't write %s: %v", report, err)
+		}
+	}
+}
+
+func TestGetSubscriptionIndex_Masked(t *testing.T) {
+	data := ReportData{
+		OutputFileName: "azqr_report",
+		Mask:           true,
+		MainData: []scanners.AzureServiceResult{
+			{SubscriptionID: "22222222-2222-2222-2222-222221234567"},
+			{SubscriptionID: "11111111-1111-1111-1111-111111234567"},
+			{SubscriptionID: "11111111-1111-1111-1111-111111234567"},
+		},
+	}
+
+	got := getSubscriptionIndex(data)
+	if len(got) != 2 {
+		t.Fatalf("getSubscriptionIndex() = %v, want an entry per subscription", got)
+	}
+	for i, resources := range []int{2, 1} {
+		if got[i].SubscriptionID != "xxxxxxxx-xxxx-xxxx-xxxx-xxxxx1234567" || got[i].Resources != resources {
+			t.Errorf("getSubscriptionIndex()[%d] = %v, want the masked id and %d resources", i, got[i], resources)
+		}
+	}
+	if got[0].Report == got[1].Report {
+		t.Errorf("getSubscriptionIndex() reports = %s, want a report per subscription", got[0].Report)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+// JSONLinesFinding - A single rule result for a resource, written as one JSON line
+type JSONLinesFinding struct {
+	SubscriptionID    string   `json:"subscriptionId"`
+	ResourceGroup     string   `json:"resourceGroup"`
+	ResourceID        string   `json:"resourceId"`
+	Name              string   `json:"name"`
+	Type              string   `json:"type"`
+	Location          string   `json:"location"`
+	RuleID            string   `json:"ruleId"`
+	Category          string   `json:"category"`
+	Subcategory       string   `json:"subcategory"`
+	Description       string   `json:"description"`
+	Severity          string   `json:"severity"`
+	Broken            bool     `json:"broken"`
+	Result            string   `json:"result"`
+	Learn             string   `json:"learn"`
+	AdvisorCategories []string `json:"advisorCategories,omitempty"`
+}
+
+// JSONLinesWriter - Streams findings as JSON lines as soon as they are produced, so results don't need to be kept in memory
+type JSONLinesWriter struct {
+	mu         sync.Mutex
+	enc        *json.Encoder
+	mask       bool
+	showPassed bool
+	count      int
+}
+
+// NewJSONLinesWriter - Creates a JSONLinesWriter. Passed rules are only written with showPassed, unless they carry a result.
+func NewJSONLinesWriter(w io.Writer, mask, showPassed bool) *JSONLinesWriter {
+	return &JSONLinesWriter{
+		enc:        json.NewEncoder(w),
+		mask:       mask,
+		showPassed: showPassed,
+	}
+}
+
+// Write - Writes one line per finding of the results
+func (j *JSONLinesWriter) Write(results []scanners.AzureServiceResult) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, finding := range toFindings(results, j.mask, j.showPassed) {
+		if err := j.enc.Encode(finding); err != nil {
+			return err
+		}
+		j.count++
+	}
+	return nil
+}
+
+// toFindings - Returns one finding per rule result. Passed rules are only included with showPassed, unless they carry a result.
+func toFindings(results []scanners.AzureServiceResult, mask, showPassed bool) []JSONLinesFinding {
+	findings := []JSONLinesFinding{}
+	for _, d := range results {
+		for _, r := range d.Rules {
+			if !r.IsBroken && !showPassed && r.Result == "" {
+				continue
+			}
+			findings = append(findings, JSONLinesFinding{
+				SubscriptionID:    scanners.MaskSubscriptionID(d.SubscriptionID, mask),
+				ResourceGroup:     d.ResourceGroup,
+				ResourceID:        scanners.MaskResourceID(d.ResourceID, mask),
+				Name:              d.ServiceName,
+				Type:              d.Type,
+				Location:          d.Location,
+				RuleID:            r.Id,
+				Category:          r.Category,
+				Subcategory:       r.Subcategory,
+				Description:       r.Description,
+				Severity:          r.Severity,
+				Broken:            r.IsBroken,
+				Result:            r.Result,
+				Learn:             r.Learn,
+				AdvisorCategories: d.AdvisorCategories,
+			})
+		}
+	}
+	return findings
+}
+
+// Count - Returns the number of findings written
+func (j *JSONLinesWriter) Count() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.count
+}
@@ -0,0 +1,66 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+func TestJSONLinesWriter(t *testing.T) {
+	out := &bytes.Buffer{}
+	w := NewJSONLinesWriter(out, false, false)
+
+	batches := [][]scanners.AzureServiceResult{
+		{
+			{
+				ServiceName: "aks-test",
+				Rules: map[string]scanners.AzureRuleResult{
+					"aks-001": {Id: "aks-001", IsBroken: true},
+					"SKU":     {Id: "aks-005", Result: "Standard"},
+					"aks-002": {Id: "aks-002"},
+				},
+			},
+		},
+		{
+			{
+				ServiceName:       "st-test",
+				AdvisorCategories: []string{"Cost"},
+				Rules: map[string]scanners.AzureRuleResult{
+					"st-001": {Id: "st-001", IsBroken: true},
+				},
+			},
+		},
+	}
+
+	for _, b := range batches {
+		if err := w.Write(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lines := 0
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		lines++
+		finding := JSONLinesFinding{}
+		if err := json.Unmarshal(scanner.Bytes(), &finding); err != nil {
+			t.Errorf("line %d is not valid JSON: %s", lines, err)
+		}
+		if finding.RuleID == "aks-002" {
+			t.Errorf("passed rule aks-002 should not be written")
+		}
+		if finding.RuleID == "st-001" && (len(finding.AdvisorCategories) != 1 || finding.AdvisorCategories[0] != "Cost") {
+			t.Errorf("finding st-001 advisor categories = %v, want [Cost]", finding.AdvisorCategories)
+		}
+	}
+
+	if lines != 3 || w.Count() != 3 {
+		t.Errorf("JSONLinesWriter wrote %d lines, counted %d, want 3", lines, w.Count())
+	}
+}
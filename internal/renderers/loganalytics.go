@@ -0,0 +1,64 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+// LogAnalyticsRecord - A flat finding with fixed column names, compatible with Log Analytics custom tables
+type LogAnalyticsRecord struct {
+	TimeGenerated  time.Time `json:"TimeGenerated"`
+	SubscriptionID string    `json:"SubscriptionId"`
+	ResourceID     string    `json:"ResourceId"`
+	RuleID         string    `json:"RuleId"`
+	Severity       string    `json:"Severity"`
+	Broken         bool      `json:"Broken"`
+}
+
+func init() {
+	Register("loganalytics", logAnalyticsRenderer{})
+}
+
+// logAnalyticsRenderer - Writes the findings as flat records for Log Analytics custom table ingestion
+type logAnalyticsRenderer struct{}
+
+// Render - Writes the findings as a JSON array of LogAnalyticsRecord, ready for the Logs Ingestion API
+func (logAnalyticsRenderer) Render(data ReportData) error {
+	filename := fmt.Sprintf("%s.loganalytics.json", data.OutputFileName)
+	log.Printf("Generating Log Analytics Report: %s", filename)
+
+	records := getLogAnalyticsRecords(data, time.Now().UTC())
+	b, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, b, 0644)
+}
+
+func getLogAnalyticsRecords(data ReportData, timeGenerated time.Time) []LogAnalyticsRecord {
+	records := []LogAnalyticsRecord{}
+	for _, d := range data.MainData {
+		for _, r := range d.Rules {
+			if !r.IsBroken && !data.ShowPassed && r.Result == "" {
+				continue
+			}
+			records = append(records, LogAnalyticsRecord{
+				TimeGenerated:  timeGenerated,
+				SubscriptionID: scanners.MaskSubscriptionID(d.SubscriptionID, data.Mask),
+				ResourceID:     scanners.MaskResourceID(d.ResourceID, data.Mask),
+				RuleID:         r.Id,
+				Severity:       r.Severity,
+				Broken:         r.IsBroken,
+			})
+		}
+	}
+	return records
+}
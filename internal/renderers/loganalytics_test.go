@@ -0,0 +1,77 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+func TestGetLogAnalyticsRecords(t *testing.T) {
+	data := ReportData{
+		MainData: []scanners.AzureServiceResult{
+			{
+				SubscriptionID: "00000000-0000-0000-0000-000000000000",
+				ResourceID:     "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.EventHub/namespaces/evh",
+				Rules: map[string]scanners.AzureRuleResult{
+					"DiagnosticSettings": {Id: "evh-001", Severity: "Medium", IsBroken: true},
+					"Private":            {Id: "evh-003", Severity: "High"},
+				},
+			},
+		},
+	}
+
+	records := getLogAnalyticsRecords(data, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	if len(records) != 1 {
+		t.Fatalf("getLogAnalyticsRecords() returned %d records, want 1", len(records))
+	}
+
+	b, err := json.Marshal(records[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	columns := map[string]interface{}{}
+	if err := json.Unmarshal(b, &columns); err != nil {
+		t.Fatal(err)
+	}
+
+	got := []string{}
+	for c := range columns {
+		got = append(got, c)
+	}
+	sort.Strings(got)
+	want := []string{"Broken", "ResourceId", "RuleId", "Severity", "SubscriptionId", "TimeGenerated"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getLogAnalyticsRecords() columns = %v, want %v", got, want)
+	}
+	if columns["TimeGenerated"] != "2023-01-01T00:00:00Z" || columns["RuleId"] != "evh-001" || columns["Broken"] != true {
+		t.Errorf("getLogAnalyticsRecords() = %v", columns)
+	}
+}
+
+func TestGetLogAnalyticsRecords_Masked(t *testing.T) {
+	data := ReportData{
+		Mask: true,
+		MainData: []scanners.AzureServiceResult{
+			{
+				SubscriptionID: "12345678-1234-1234-1234-123456789012",
+				ResourceID:     "/subscriptions/12345678-1234-1234-1234-123456789012/resourceGroups/rg/providers/Microsoft.EventHub/namespaces/evh",
+				Rules: map[string]scanners.AzureRuleResult{
+					"DiagnosticSettings": {Id: "evh-001", Severity: "Medium", IsBroken: true},
+				},
+			},
+		},
+	}
+
+	records := getLogAnalyticsRecords(data, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	want := "/subscriptions/xxxxxxxx-xxxx-xxxx-xxxx-xxxxx6789012/resourceGroups/rg/providers/Microsoft.EventHub/namespaces/evh"
+	if len(records) != 1 || records[0].ResourceID != want {
+		t.Errorf("getLogAnalyticsRecords() = %v, want the resource id %s", records, want)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+)
+
+func init() {
+	Register("md", markdownRenderer{})
+}
+
+// markdownRenderer - Writes the findings as a Markdown report to <OutputFileName>.md, e.g. to publish it in a pull request or a wiki
+type markdownRenderer struct{}
+
+// Render - Writes the Markdown report
+func (markdownRenderer) Render(data ReportData) error {
+	filename := fmt.Sprintf("%s.md", data.OutputFileName)
+	log.Printf("Generating Report: %s", filename)
+	return writeFile(filename, func(w io.Writer) error {
+		return writeMarkdown(w, data)
+	})
+}
+
+func writeMarkdown(w io.Writer, data ReportData) error {
+	b := &strings.Builder{}
+	fmt.Fprintln(b, "# Azure Quick Review")
+	fmt.Fprintln(b)
+	fmt.Fprintf(b, "azqr version %s (%s, %s)\n", data.Version, data.Commit, data.BuildDate)
+	fmt.Fprintln(b)
+
+	fmt.Fprintln(b, "## Findings")
+	fmt.Fprintln(b)
+	findings := toFindings(data.MainData, data.Mask, data.ShowPassed)
+	if len(findings) == 0 {
+		fmt.Fprintln(b, "No findings.")
+	} else {
+		fmt.Fprintln(b, "| Severity | Rule | Broken | Resource | Resource Group | Category | Description | Result | Learn |")
+		fmt.Fprintln(b, "|---|---|---|---|---|---|---|---|---|")
+		for _, f := range findings {
+			fmt.Fprintf(b, "| %s | %s | %t | %s | %s | %s | %s | %s | %s |\n",
+				markdownCell(f.Severity), markdownCell(f.RuleID), f.Broken, markdownCell(f.Name), markdownCell(f.ResourceGroup),
+				markdownCell(f.Category), markdownCell(f.Description), markdownCell(f.Result), markdownLink(f.Learn))
+		}
+	}
+
+	if len(data.Errors) > 0 {
+		fmt.Fprintln(b)
+		fmt.Fprintln(b, "## Errors (results are partial)")
+		fmt.Fprintln(b)
+		fmt.Fprintln(b, "| Scanner/Rule | Scope | Error |")
+		fmt.Fprintln(b, "|---|---|---|")
+		for _, e := range toScanErrors(data.Errors, data.Mask) {
+			fmt.Fprintf(b, "| %s | %s | %s |\n", markdownCell(errorSource(e)), markdownCell(errorScope(e)), markdownCell(firstLine(e.Error)))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCell - Escapes the text so it fits in a table cell
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func markdownLink(url string) string {
+	if url == "" {
+		return ""
+	}
+	return fmt.Sprintf("[Learn](%s)", markdownCell(url))
+}
@@ -0,0 +1,50 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	data := ReportData{
+		MainData: []scanners.AzureServiceResult{
+			{
+				ServiceName:   "evh",
+				ResourceGroup: "rg",
+				Rules: map[string]scanners.AzureRuleResult{
+					"evh-001": {Id: "evh-001", Severity: "Medium", Category: "Monitoring", Description: "Diagnostic settings", IsBroken: true, Learn: "https://learn.microsoft.com"},
+					"SKU":     {Id: "evh-005", Severity: "High", Result: "Premium | 1\nunit"},
+					"evh-002": {Id: "evh-002", Severity: "High"},
+				},
+			},
+		},
+		Errors: []scanners.ScanError{
+			{Scanner: "kv", SubscriptionID: "sub", ResourceGroup: "rg", Error: "RESPONSE 403\nmore details"},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	if err := writeMarkdown(out, data); err != nil {
+		t.Fatal(err)
+	}
+	report := out.String()
+
+	for _, want := range []string{
+		"| Medium | evh-001 | true | evh | rg | Monitoring | Diagnostic settings |  | [Learn](https://learn.microsoft.com) |",
+		"| High | evh-005 | false | evh | rg |  |  | Premium \\| 1 unit |  |",
+		"| kv | sub/rg | RESPONSE 403 |",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("writeMarkdown() = %s, want a row %s", report, want)
+		}
+	}
+	if strings.Contains(report, "evh-002") {
+		t.Error("writeMarkdown() should not include passed rules without a result")
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// severityWeights - Weight of each severity when scoring the remediation backlog
+var severityWeights = map[string]int{
+	"High":   10,
+	"Medium": 3,
+	"Low":    1,
+}
+
+// remediationSeverities - Severity axis of the remediation matrix, from the highest to the lowest
+var remediationSeverities = []string{"High", "Medium", "Low"}
+
+// remediationImpacts - Resource count axis of the remediation matrix, from the most to the least resources affected
+var remediationImpacts = []string{"Many (>20)", "Several (6-20)", "Few (2-5)", "Single"}
+
+// RemediationItem - A broken rule of the remediation backlog, with the number of resources it affects
+type RemediationItem struct {
+	Priority    int    `json:"priority"`
+	RuleID      string `json:"ruleId"`
+	Severity    string `json:"severity"`
+	Impact      string `json:"impact"`
+	Resources   int    `json:"resources"`
+	Score       int    `json:"score"`
+	Description string `json:"description"`
+	Learn       string `json:"learn"`
+}
+
+func init() {
+	Register("remediation", remediationRenderer{})
+}
+
+// remediationRenderer - Writes the remediation backlog
+type remediationRenderer struct{}
+
+// Render - Writes the remediation backlog, ordered by priority, as JSON
+func (remediationRenderer) Render(data ReportData) error {
+	filename := fmt.Sprintf("%s.remediation.json", data.OutputFileName)
+	log.Printf("Generating Remediation Backlog: %s", filename)
+
+	b, err := json.MarshalIndent(getRemediationBacklog(data), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, b, 0644)
+}
+
+func renderRemediation(f *excelize.File, data ReportData) {
+	_, err := f.NewSheet("Remediation")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	heathers := []string{"Priority", "Id", "Severity", "Impact", "Resources", "Score", "Description", "Learn"}
+	createFirstRow(f, "Remediation", heathers)
+
+	backlog := getRemediationBacklog(data)
+	currentRow := 4
+	for _, item := range backlog {
+		currentRow += 1
+		row := []string{
+			strconv.Itoa(item.Priority),
+			item.RuleID,
+			item.Severity,
+			item.Impact,
+			strconv.Itoa(item.Resources),
+			strconv.Itoa(item.Score),
+			item.Description,
+			item.Learn,
+		}
+		cell, err := excelize.CoordinatesToCellName(1, currentRow)
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = f.SetSheetRow("Remediation", cell, &row)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		setHyperLink(f, "Remediation", 8, currentRow)
+	}
+
+	renderRemediationMatrix(f, backlog, currentRow+3)
+	configureSheet(f, "Remediation", heathers, currentRow)
+}
+
+// renderRemediationMatrix - Writes the rules of the backlog in a severity x resource count matrix, starting at row,
+// so the rules to fix first are on the top left
+func renderRemediationMatrix(f *excelize.File, backlog []RemediationItem, row int) {
+	heathers := append([]string{"Severity / Resources"}, remediationImpacts...)
+	cell, err := excelize.CoordinatesToCellName(1, row)
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = f.SetSheetRow("Remediation", cell, &heathers)
+	if err != nil {
+		log.Fatal(err)
+	}
+	style, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = f.SetRowStyle("Remediation", row, row, style)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, cells := range getRemediationMatrix(backlog) {
+		row += 1
+		cell, err := excelize.CoordinatesToCellName(1, row)
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = f.SetSheetRow("Remediation", cell, &cells)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// getRemediationMatrix - Returns a row per severity, with the rules of the backlog in each resource count bucket in priority order
+func getRemediationMatrix(backlog []RemediationItem) [][]string {
+	rules := map[string]map[string][]string{}
+	for _, item := range backlog {
+		if rules[item.Severity] == nil {
+			rules[item.Severity] = map[string][]string{}
+		}
+		rules[item.Severity][item.Impact] = append(rules[item.Severity][item.Impact], item.RuleID)
+	}
+
+	matrix := [][]string{}
+	for _, severity := range remediationSeverities {
+		row := []string{severity}
+		for _, impact := range remediationImpacts {
+			row = append(row, strings.Join(rules[severity][impact], ", "))
+		}
+		matrix = append(matrix, row)
+	}
+	return matrix
+}
+
+// getRemediationBacklog - Groups the broken rules and orders them by severity, then by the number of resources affected.
+// The score, severity weight times the number of resources affected, follows the same order within a severity.
+func getRemediationBacklog(data ReportData) []RemediationItem {
+	items := map[string]*RemediationItem{}
+	for _, d := range data.MainData {
+		for _, r := range d.Rules {
+			if !r.IsBroken {
+				continue
+			}
+			item, exists := items[r.Id]
+			if !exists {
+				item = &RemediationItem{
+					RuleID:      r.Id,
+					Severity:    r.Severity,
+					Description: r.Description,
+					Learn:       r.Learn,
+				}
+				items[r.Id] = item
+			}
+			// Rules with an adjusted severity report the highest one
+			if severityWeights[r.Severity] > severityWeights[item.Severity] {
+				item.Severity = r.Severity
+			}
+			item.Resources++
+		}
+	}
+
+	backlog := []RemediationItem{}
+	for _, item := range items {
+		item.Score = severityWeights[item.Severity] * item.Resources
+		item.Impact = getImpact(item.Resources)
+		backlog = append(backlog, *item)
+	}
+
+	// Any number of lower severity findings doesn't outrank a higher severity one
+	sort.Slice(backlog, func(i, j int) bool {
+		if severityWeights[backlog[i].Severity] != severityWeights[backlog[j].Severity] {
+			return severityWeights[backlog[i].Severity] > severityWeights[backlog[j].Severity]
+		}
+		if backlog[i].Resources != backlog[j].Resources {
+			return backlog[i].Resources > backlog[j].Resources
+		}
+		return backlog[i].RuleID < backlog[j].RuleID
+	})
+
+	for i := range backlog {
+		backlog[i].Priority = i + 1
+	}
+	return backlog
+}
+
+// getImpact - Returns the resource count bucket used as the second axis of the remediation matrix
+func getImpact(resources int) string {
+	switch {
+	case resources > 20:
+		return remediationImpacts[0]
+	case resources > 5:
+		return remediationImpacts[1]
+	case resources > 1:
+		return remediationImpacts[2]
+	default:
+		return remediationImpacts[3]
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+func TestGetRemediationBacklog(t *testing.T) {
+	high := scanners.AzureRuleResult{Id: "aks-001", Severity: "High", IsBroken: true}
+	medium := scanners.AzureRuleResult{Id: "st-002", Severity: "Medium", IsBroken: true}
+	low := scanners.AzureRuleResult{Id: "kv-003", Severity: "Low", IsBroken: true}
+	passed := scanners.AzureRuleResult{Id: "evh-004", Severity: "High"}
+
+	mainData := []scanners.AzureServiceResult{
+		{Rules: map[string]scanners.AzureRuleResult{"a": high, "b": medium, "c": passed}},
+	}
+	// 4 resources affected by the Medium rule and 12 by the Low one
+	for i := 0; i < 3; i++ {
+		mainData = append(mainData, scanners.AzureServiceResult{Rules: map[string]scanners.AzureRuleResult{"b": medium}})
+	}
+	for i := 0; i < 12; i++ {
+		mainData = append(mainData, scanners.AzureServiceResult{Rules: map[string]scanners.AzureRuleResult{"c": low}})
+	}
+
+	backlog := getRemediationBacklog(ReportData{MainData: mainData})
+
+	got := []string{}
+	for _, item := range backlog {
+		got = append(got, item.RuleID)
+	}
+	// Severity goes first: a single High finding outranks 4 Medium and 12 Low ones
+	want := []string{"aks-001", "st-002", "kv-003"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getRemediationBacklog() order = %v, want %v", got, want)
+	}
+	if backlog[0].Priority != 1 || backlog[0].Resources != 1 || backlog[0].Impact != "Single" || backlog[0].Score != 10 {
+		t.Errorf("getRemediationBacklog() first item = %+v", backlog[0])
+	}
+	if backlog[2].Priority != 3 || backlog[2].Resources != 12 || backlog[2].Impact != "Several (6-20)" || backlog[2].Score != 12 {
+		t.Errorf("getRemediationBacklog() last item = %+v", backlog[2])
+	}
+}
+
+func TestGetRemediationBacklog_SameSeverity(t *testing.T) {
+	few := scanners.AzureRuleResult{Id: "aks-001", Severity: "Medium", IsBroken: true}
+	many := scanners.AzureRuleResult{Id: "st-002", Severity: "Medium", IsBroken: true}
+
+	mainData := []scanners.AzureServiceResult{}
+	for i := 0; i < 3; i++ {
+		rules := map[string]scanners.AzureRuleResult{"b": many}
+		if i == 0 {
+			rules["a"] = few
+		}
+		mainData = append(mainData, scanners.AzureServiceResult{Rules: rules})
+	}
+
+	backlog := getRemediationBacklog(ReportData{MainData: mainData})
+	if len(backlog) != 2 || backlog[0].RuleID != "st-002" || backlog[1].RuleID != "aks-001" {
+		t.Errorf("getRemediationBacklog() = %+v, want the rule affecting more resources first", backlog)
+	}
+}
+
+func TestGetRemediationMatrix(t *testing.T) {
+	backlog := []RemediationItem{
+		{RuleID: "st-002", Severity: "Medium", Impact: "Few (2-5)"},
+		{RuleID: "kv-003", Severity: "Low", Impact: "Several (6-20)"},
+		{RuleID: "aks-005", Severity: "High", Impact: "Single"},
+		{RuleID: "aks-001", Severity: "High", Impact: "Single"},
+		{RuleID: "evh-004", Severity: "Medium", Impact: "Many (>20)"},
+	}
+
+	want := [][]string{
+		{"High", "", "", "", "aks-005, aks-001"},
+		{"Medium", "evh-004", "", "st-002", ""},
+		{"Low", "", "kv-003", "", ""},
+	}
+	if got := getRemediationMatrix(backlog); !reflect.DeepEqual(got, want) {
+		t.Errorf("getRemediationMatrix() = %v, want %v", got, want)
+	}
+}
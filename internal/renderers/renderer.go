@@ -0,0 +1,58 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Renderer - Interface for all report renderers
+type Renderer interface {
+	Render(data ReportData) error
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Renderer{}
+)
+
+// Register - Makes a renderer available by name. Built-in renderers register themselves in init,
+// custom renderers can be registered the same way before the scan runs.
+// Register panics if the renderer is nil or if it's called twice with the same name.
+func Register(name string, renderer Renderer) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if renderer == nil {
+		panic("renderers: Register renderer is nil")
+	}
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("renderers: Register called twice for renderer %s", name))
+	}
+	registry[name] = renderer
+}
+
+// Get - Returns the renderer registered with the given name
+func Get(name string) (Renderer, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	renderer, exists := registry[name]
+	return renderer, exists
+}
+
+// Names - Returns the sorted names of the registered renderers
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,244 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+type fakeRenderer struct {
+	rendered []string
+}
+
+func (f *fakeRenderer) Render(data ReportData) error {
+	f.rendered = append(f.rendered, data.OutputFileName)
+	return nil
+}
+
+func TestRegister(t *testing.T) {
+	fake := &fakeRenderer{}
+	Register("fake", fake)
+	defer func() {
+		registryMu.Lock()
+		delete(registry, "fake")
+		registryMu.Unlock()
+	}()
+
+	r, exists := Get("fake")
+	if !exists {
+		t.Fatalf("Get() fake renderer not registered")
+	}
+	if err := r.Render(ReportData{OutputFileName: "report"}); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !reflect.DeepEqual(fake.rendered, []string{"report"}) {
+		t.Errorf("Render() rendered = %v, want %v", fake.rendered, []string{"report"})
+	}
+}
+
+func TestRegister_Duplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Register() should panic when a name is registered twice")
+		}
+	}()
+	Register("excel", &fakeRenderer{})
+}
+
+func TestNames(t *testing.T) {
+	want := []string{"console", "csv", "excel", "index", "json", "loganalytics", "md", "remediation", "sarif"}
+	if got := Names(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+}
+
+func TestFindingsRenderer(t *testing.T) {
+	data := ReportData{
+		OutputFileName: filepath.Join(t.TempDir(), "report"),
+		MainData: []scanners.AzureServiceResult{
+			{
+				ServiceName: "evh",
+				Rules: map[string]scanners.AzureRuleResult{
+					"DiagnosticSettings": {Id: "evh-001", Severity: "Medium", IsBroken: true},
+					"Private":            {Id: "evh-002", Severity: "High"},
+				},
+			},
+		},
+	}
+
+	r, _ := Get("csv")
+	if err := r.Render(data); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	b, err := os.ReadFile(data.OutputFileName + ".csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != 3 || !strings.Contains(string(b), "evh-001") || !strings.Contains(string(b), "evh-002") {
+		t.Errorf("Render() csv = %v, want a header and a row for every rule evaluated, passed or not", lines)
+	}
+}
+
+func TestWriteFindingsCSV(t *testing.T) {
+	findings := []JSONLinesFinding{
+		{
+			RuleID:         "aks-045",
+			Category:       "Cost Optimization",
+			Subcategory:    "Autoscale",
+			Severity:       "Low",
+			Broken:         false,
+			Result:         `system: "Delete", user: Deallocate`,
+			Name:           "aks1",
+			ResourceGroup:  "rg",
+			SubscriptionID: "sub",
+			ResourceID:     "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/aks1",
+			Learn:          "https://learn.microsoft.com",
+		},
+	}
+
+	b := &strings.Builder{}
+	if err := writeFindingsCSV(b, findings); err != nil {
+		t.Fatalf("writeFindingsCSV() error = %v", err)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(b.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("writeFindingsCSV() wrote invalid csv: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("writeFindingsCSV() wrote %d records, want a header and 1 finding", len(records))
+	}
+	want := [][]string{
+		{"RuleID", "Category", "Subcategory", "Severity", "Broken", "Result", "ResourceName", "ResourceGroup", "SubscriptionId", "Url"},
+		{"aks-045", "Cost Optimization", "Autoscale", "Low", "false", `system: "Delete", user: Deallocate`, "aks1", "rg", "sub", "https://learn.microsoft.com"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("writeFindingsCSV() = %v, want %v", records, want)
+	}
+}
+
+func TestFindingsRenderer_Errors(t *testing.T) {
+	data := ReportData{
+		OutputFileName: filepath.Join(t.TempDir(), "report"),
+		Errors: []scanners.ScanError{
+			{
+				Scanner:        "*kv.KeyVaultScanner",
+				SubscriptionID: "00000000-0000-0000-0000-000000000000",
+				ResourceGroup:  "rg",
+				Error:          "RESPONSE 403: 403 Forbidden\nERROR CODE: AuthorizationFailed",
+			},
+		},
+	}
+
+	r, _ := Get("json")
+	if err := r.Render(data); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	b, err := os.ReadFile(data.OutputFileName + ".errors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []scanners.ScanError
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, data.Errors) {
+		t.Errorf("Render() errors = %v, want %v", got, data.Errors)
+	}
+
+	r, _ = Get("csv")
+	if err := r.Render(data); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if _, err := os.Stat(data.OutputFileName + ".errors.csv"); !os.IsNotExist(err) {
+		t.Errorf("Render() csv should not write an errors report, got error %v", err)
+	}
+}
+
+func TestToScanErrors(t *testing.T) {
+	errors := []scanners.ScanError{
+		{},
+		{SubscriptionID: "00000000-0000-0000-0000-000000000000"},
+		{
+			Rule:           "kv-008",
+			SubscriptionID: "00000000-0000-0000-0000-000000000000",
+			ResourceID:     "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.KeyVault/vaults/kv1",
+		},
+	}
+
+	got := toScanErrors(errors, true)
+	want := []scanners.ScanError{
+		{},
+		{SubscriptionID: "xxxxxxxx-xxxx-xxxx-xxxx-xxxxx0000000"},
+		{
+			Rule:           "kv-008",
+			SubscriptionID: "xxxxxxxx-xxxx-xxxx-xxxx-xxxxx0000000",
+			ResourceID:     "/subscriptions/xxxxxxxx-xxxx-xxxx-xxxx-xxxxx0000000/resourceGroups/rg/providers/Microsoft.KeyVault/vaults/kv1",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("toScanErrors() = %v, want %v", got, want)
+	}
+}
+
+func TestRenderers_Masked(t *testing.T) {
+	subscriptionID := "12345678-1234-1234-1234-123456789012"
+	dir := t.TempDir()
+	data := ReportData{
+		OutputFileName: filepath.Join(dir, "report"),
+		Mask:           true,
+		MainData: []scanners.AzureServiceResult{
+			{
+				SubscriptionID: subscriptionID,
+				ResourceGroup:  "rg",
+				ResourceID:     "/subscriptions/" + subscriptionID + "/resourceGroups/rg/providers/Microsoft.KeyVault/vaults/kv1",
+				ServiceName:    "kv1",
+				Rules: map[string]scanners.AzureRuleResult{
+					"kv-001": {Id: "kv-001", Severity: "Medium", IsBroken: true},
+					"SKU":    {Id: "kv-004", Severity: "High", Result: "standard"},
+				},
+			},
+		},
+	}
+
+	for _, name := range []string{"json", "csv", "md", "index"} {
+		r, _ := Get(name)
+		if err := r.Render(data); err != nil {
+			t.Fatalf("Render() %s error = %v", name, err)
+		}
+	}
+	b := &strings.Builder{}
+	if err := NewJSONLinesWriter(b, true, true).Write(data.MainData); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no reports written to %s: %v", dir, err)
+	}
+	for _, f := range files {
+		content, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(content), subscriptionID) {
+			t.Errorf("%s contains the subscription id with Mask", filepath.Base(f))
+		}
+	}
+	if strings.Contains(b.String(), subscriptionID) {
+		t.Errorf("JSONLinesWriter wrote the subscription id with Mask")
+	}
+	if !strings.Contains(b.String(), "/subscriptions/xxxxxxxx-xxxx-xxxx-xxxx-xxxxx6789012/resourceGroups/rg") {
+		t.Errorf("JSONLinesWriter = %s, want the masked resource id", b.String())
+	}
+}
@@ -9,9 +9,15 @@ import (
 
 type ReportData struct {
 	OutputFileName     string
+	Version            string
+	Commit             string
+	BuildDate          string
 	EnableDetailedScan bool
 	Mask               bool
+	NoColor            bool
+	ShowPassed         bool
 	MainData           []scanners.AzureServiceResult
 	DefenderData       []scanners.DefenderResult
 	AdvisorData        []scanners.AdvisorResult
+	Errors             []scanners.ScanError
 }
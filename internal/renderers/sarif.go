@@ -0,0 +1,222 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// sarifLevels - SARIF result level of each severity
+var sarifLevels = map[string]string{
+	"High":   "error",
+	"Medium": "warning",
+	"Low":    "note",
+}
+
+type (
+	// SARIFLog - A SARIF 2.1.0 log with a single run, as expected by GitHub code scanning
+	SARIFLog struct {
+		Version string     `json:"version"`
+		Schema  string     `json:"$schema"`
+		Runs    []SARIFRun `json:"runs"`
+	}
+
+	SARIFRun struct {
+		Tool    SARIFTool     `json:"tool"`
+		Results []SARIFResult `json:"results"`
+	}
+
+	SARIFTool struct {
+		Driver SARIFDriver `json:"driver"`
+	}
+
+	SARIFDriver struct {
+		Name           string                     `json:"name"`
+		Version        string                     `json:"version,omitempty"`
+		InformationURI string                     `json:"informationUri"`
+		Rules          []SARIFReportingDescriptor `json:"rules"`
+	}
+
+	// SARIFReportingDescriptor - A rule of the scan
+	SARIFReportingDescriptor struct {
+		ID                   string             `json:"id"`
+		ShortDescription     SARIFMessage       `json:"shortDescription"`
+		HelpURI              string             `json:"helpUri,omitempty"`
+		DefaultConfiguration SARIFConfiguration `json:"defaultConfiguration"`
+		Properties           SARIFRuleProperty  `json:"properties"`
+	}
+
+	SARIFConfiguration struct {
+		Level string `json:"level"`
+	}
+
+	SARIFRuleProperty struct {
+		Category    string `json:"category"`
+		Subcategory string `json:"subcategory"`
+		Severity    string `json:"severity"`
+	}
+
+	SARIFMessage struct {
+		Text string `json:"text"`
+	}
+
+	// SARIFResult - A broken rule of a resource
+	SARIFResult struct {
+		RuleID    string          `json:"ruleId"`
+		RuleIndex int             `json:"ruleIndex"`
+		Level     string          `json:"level"`
+		Message   SARIFMessage    `json:"message"`
+		Locations []SARIFLocation `json:"locations"`
+	}
+
+	SARIFLocation struct {
+		PhysicalLocation SARIFPhysicalLocation  `json:"physicalLocation"`
+		LogicalLocations []SARIFLogicalLocation `json:"logicalLocations"`
+	}
+
+	SARIFPhysicalLocation struct {
+		ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	}
+
+	SARIFArtifactLocation struct {
+		URI string `json:"uri"`
+	}
+
+	SARIFLogicalLocation struct {
+		Name               string `json:"name"`
+		FullyQualifiedName string `json:"fullyQualifiedName"`
+		Kind               string `json:"kind"`
+	}
+)
+
+func init() {
+	Register("sarif", sarifRenderer{})
+}
+
+// sarifRenderer - Writes the broken rules as a SARIF log to <OutputFileName>.sarif, for GitHub code scanning
+type sarifRenderer struct{}
+
+// Render - Writes the SARIF log of the report
+func (sarifRenderer) Render(data ReportData) error {
+	filename := fmt.Sprintf("%s.sarif", data.OutputFileName)
+	log.Printf("Generating SARIF Report: %s", filename)
+
+	b, err := json.MarshalIndent(getSARIFLog(data), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, b, 0644)
+}
+
+// getSARIFLog - Returns a reporting descriptor for every rule evaluated and a result for every broken rule.
+// The resource id is used as location, since findings are not tied to source files.
+func getSARIFLog(data ReportData) SARIFLog {
+	descriptors := map[string]SARIFReportingDescriptor{}
+	results := []SARIFResult{}
+	for _, d := range data.MainData {
+		for _, r := range d.Rules {
+			if _, ok := descriptors[r.Id]; !ok {
+				descriptors[r.Id] = SARIFReportingDescriptor{
+					ID:                   r.Id,
+					ShortDescription:     SARIFMessage{Text: r.Description},
+					HelpURI:              r.Learn,
+					DefaultConfiguration: SARIFConfiguration{Level: sarifLevel(r.Severity)},
+					Properties: SARIFRuleProperty{
+						Category:    r.Category,
+						Subcategory: r.Subcategory,
+						Severity:    r.Severity,
+					},
+				}
+			}
+			if !r.IsBroken {
+				continue
+			}
+
+			resourceID := scanners.MaskResourceID(d.ResourceID, data.Mask)
+			message := fmt.Sprintf("%s: %s", d.ServiceName, r.Description)
+			if r.Result != "" {
+				message = fmt.Sprintf("%s (%s)", message, r.Result)
+			}
+			results = append(results, SARIFResult{
+				RuleID:  r.Id,
+				Level:   sarifLevel(r.Severity),
+				Message: SARIFMessage{Text: message},
+				Locations: []SARIFLocation{
+					{
+						PhysicalLocation: SARIFPhysicalLocation{
+							ArtifactLocation: SARIFArtifactLocation{URI: strings.TrimPrefix(resourceID, "/")},
+						},
+						LogicalLocations: []SARIFLogicalLocation{
+							{
+								Name:               d.ServiceName,
+								FullyQualifiedName: resourceID,
+								Kind:               "resource",
+							},
+						},
+					},
+				},
+			})
+		}
+	}
+
+	rules := make([]SARIFReportingDescriptor, 0, len(descriptors))
+	for _, descriptor := range descriptors {
+		rules = append(rules, descriptor)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].ID < rules[j].ID
+	})
+	index := map[string]int{}
+	for i, rule := range rules {
+		index[rule.ID] = i
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].RuleID != results[j].RuleID {
+			return results[i].RuleID < results[j].RuleID
+		}
+		return results[i].Locations[0].LogicalLocations[0].FullyQualifiedName < results[j].Locations[0].LogicalLocations[0].FullyQualifiedName
+	})
+	for i := range results {
+		results[i].RuleIndex = index[results[i].RuleID]
+	}
+
+	return SARIFLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []SARIFRun{
+			{
+				Tool: SARIFTool{
+					Driver: SARIFDriver{
+						Name:           "azqr",
+						Version:        data.Version,
+						InformationURI: "https://github.com/cmendible/azqr",
+						Rules:          rules,
+					},
+				},
+				Results: results,
+			},
+		},
+	}
+}
+
+// sarifLevel - Returns the SARIF level of the severity, note if unknown
+func sarifLevel(severity string) string {
+	if level, ok := sarifLevels[severity]; ok {
+		return level
+	}
+	return "note"
+}
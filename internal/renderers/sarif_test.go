@@ -0,0 +1,219 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cmendible/azqr/internal/scanners"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// sarifSchemaShape - The subset of the SARIF 2.1.0 schema required by GitHub code scanning
+const sarifSchemaShape = `{
+	"type": "object",
+	"required": ["version", "$schema", "runs"],
+	"properties": {
+		"version": {"const": "2.1.0"},
+		"$schema": {"type": "string", "format": "uri"},
+		"runs": {
+			"type": "array",
+			"minItems": 1,
+			"items": {
+				"type": "object",
+				"required": ["tool", "results"],
+				"properties": {
+					"tool": {
+						"type": "object",
+						"required": ["driver"],
+						"properties": {
+							"driver": {
+								"type": "object",
+								"required": ["name"],
+								"properties": {
+									"name": {"type": "string"},
+									"informationUri": {"type": "string", "format": "uri"},
+									"rules": {
+										"type": "array",
+										"items": {
+											"type": "object",
+											"required": ["id"],
+											"properties": {
+												"id": {"type": "string"},
+												"shortDescription": {"$ref": "#/definitions/message"},
+												"helpUri": {"type": "string", "format": "uri"},
+												"defaultConfiguration": {
+													"type": "object",
+													"properties": {"level": {"$ref": "#/definitions/level"}}
+												}
+											}
+										}
+									}
+								}
+							}
+						}
+					},
+					"results": {
+						"type": "array",
+						"items": {
+							"type": "object",
+							"required": ["message"],
+							"properties": {
+								"ruleId": {"type": "string"},
+								"ruleIndex": {"type": "integer", "minimum": 0},
+								"level": {"$ref": "#/definitions/level"},
+								"message": {"$ref": "#/definitions/message"},
+								"locations": {
+									"type": "array",
+									"items": {
+										"type": "object",
+										"properties": {
+											"physicalLocation": {
+												"type": "object",
+												"required": ["artifactLocation"],
+												"properties": {
+													"artifactLocation": {
+														"type": "object",
+														"required": ["uri"],
+														"properties": {"uri": {"type": "string", "format": "uri-reference"}}
+													}
+												}
+											},
+											"logicalLocations": {
+												"type": "array",
+												"items": {
+													"type": "object",
+													"properties": {
+														"name": {"type": "string"},
+														"fullyQualifiedName": {"type": "string"},
+														"kind": {"type": "string"}
+													}
+												}
+											}
+										}
+									}
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	},
+	"definitions": {
+		"level": {"enum": ["none", "note", "warning", "error"]},
+		"message": {
+			"type": "object",
+			"required": ["text"],
+			"properties": {"text": {"type": "string"}}
+		}
+	}
+}`
+
+func TestSARIFRenderer(t *testing.T) {
+	schema, err := jsonschema.CompileString("sarif.schema.json", sarifSchemaShape)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resourceID := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.EventHub/namespaces/evh"
+	data := ReportData{
+		OutputFileName: filepath.Join(t.TempDir(), "report"),
+		Version:        "1.0.0",
+		MainData: []scanners.AzureServiceResult{
+			{
+				ResourceID:  resourceID,
+				ServiceName: "evh",
+				Rules: map[string]scanners.AzureRuleResult{
+					"DiagnosticSettings": {Id: "evh-001", Description: "Event Hub should have diagnostic settings enabled", Severity: "Medium", IsBroken: true, Learn: "https://learn.microsoft.com/en-us/azure/event-hubs/monitor-event-hubs"},
+					"AvailabilityZones":  {Id: "evh-002", Description: "Event Hub should have availability zones enabled", Severity: "High", IsBroken: true, Learn: "https://learn.microsoft.com/en-us/azure/event-hubs/event-hubs-geo-dr"},
+					"evh-007":            {Id: "evh-007", Description: "Event Hub should have tags", Severity: "Low", IsBroken: true, Result: "no tags"},
+					"evh-008":            {Id: "evh-008", Description: "Event Hub should have local authentication disabled", Severity: "Medium", IsBroken: false},
+				},
+			},
+		},
+	}
+
+	r, _ := Get("sarif")
+	if err := r.Render(data); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	b, err := os.ReadFile(data.OutputFileName + ".sarif")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc interface{}
+	if err := json.NewDecoder(bytes.NewReader(b)).Decode(&doc); err != nil {
+		t.Fatal(err)
+	}
+	if err := schema.Validate(doc); err != nil {
+		t.Errorf("sarif report doesn't match the SARIF schema: %#v", err)
+	}
+
+	var sarif SARIFLog
+	if err := json.Unmarshal(b, &sarif); err != nil {
+		t.Fatal(err)
+	}
+	run := sarif.Runs[0]
+	if len(run.Tool.Driver.Rules) != 4 {
+		t.Errorf("sarif report has %d rules, want 4", len(run.Tool.Driver.Rules))
+	}
+	if len(run.Results) != 3 {
+		t.Fatalf("sarif report has %d results, want the 3 broken rules", len(run.Results))
+	}
+
+	wantLevels := map[string]string{
+		"evh-001": "warning",
+		"evh-002": "error",
+		"evh-007": "note",
+	}
+	for _, result := range run.Results {
+		if result.Level != wantLevels[result.RuleID] {
+			t.Errorf("%s level = %s, want %s", result.RuleID, result.Level, wantLevels[result.RuleID])
+		}
+		rule := run.Tool.Driver.Rules[result.RuleIndex]
+		if rule.ID != result.RuleID {
+			t.Errorf("%s ruleIndex points to %s", result.RuleID, rule.ID)
+		}
+		if got := result.Locations[0].LogicalLocations[0].FullyQualifiedName; got != resourceID {
+			t.Errorf("%s location = %s, want %s", result.RuleID, got, resourceID)
+		}
+	}
+	if got := run.Tool.Driver.Rules[0].HelpURI; got != "https://learn.microsoft.com/en-us/azure/event-hubs/monitor-event-hubs" {
+		t.Errorf("evh-001 helpUri = %s", got)
+	}
+}
+
+func TestGetSARIFLog_Masked(t *testing.T) {
+	data := ReportData{
+		Mask: true,
+		MainData: []scanners.AzureServiceResult{
+			{
+				ResourceID:  "/subscriptions/12345678-1234-1234-1234-123456789012/resourceGroups/rg/providers/Microsoft.EventHub/namespaces/evh",
+				ServiceName: "evh",
+				Rules: map[string]scanners.AzureRuleResult{
+					"DiagnosticSettings": {Id: "evh-001", Severity: "Medium", IsBroken: true},
+				},
+			},
+		},
+	}
+
+	run := getSARIFLog(data).Runs[0]
+	if len(run.Results) != 1 {
+		t.Fatalf("sarif report has %d results, want 1", len(run.Results))
+	}
+	want := "/subscriptions/xxxxxxxx-xxxx-xxxx-xxxx-xxxxx6789012/resourceGroups/rg/providers/Microsoft.EventHub/namespaces/evh"
+	location := run.Results[0].Locations[0]
+	if got := location.LogicalLocations[0].FullyQualifiedName; got != want {
+		t.Errorf("fullyQualifiedName = %s, want %s", got, want)
+	}
+	if got := location.PhysicalLocation.ArtifactLocation.URI; got != want[1:] {
+		t.Errorf("artifactLocation uri = %s, want %s", got, want[1:])
+	}
+}
@@ -0,0 +1,14 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	_ "embed"
+)
+
+// ReportSchema - JSON Schema of the report written by the json renderer.
+// Update it together with JSONLinesFinding, downstream parsers rely on it.
+//
+//go:embed report.schema.json
+var ReportSchema []byte
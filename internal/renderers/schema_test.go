@@ -0,0 +1,61 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cmendible/azqr/internal/scanners"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+func TestReportSchema(t *testing.T) {
+	schema, err := jsonschema.CompileString("report.schema.json", string(ReportSchema))
+	if err != nil {
+		t.Fatalf("ReportSchema is not a valid JSON Schema: %s", err)
+	}
+
+	data := ReportData{
+		OutputFileName: filepath.Join(t.TempDir(), "report"),
+		ShowPassed:     true,
+		MainData: []scanners.AzureServiceResult{
+			{
+				SubscriptionID: "00000000-0000-0000-0000-000000000000",
+				ResourceGroup:  "rg",
+				ResourceID:     "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.EventHub/namespaces/evh",
+				ServiceName:    "evh",
+				Type:           "Microsoft.EventHub/namespaces",
+				Location:       "westeurope",
+				Rules: map[string]scanners.AzureRuleResult{
+					"DiagnosticSettings": {Id: "evh-001", Category: "Monitoring and Logging", Severity: "Medium", IsBroken: true},
+					"evh-020":            {Id: "evh-020", Category: "High Availability and Resiliency", Severity: "Medium", Result: "2 Maximum Throughput Units"},
+				},
+			},
+		},
+	}
+
+	r, _ := Get("json")
+	if err := r.Render(data); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	b, err := os.ReadFile(data.OutputFileName + ".json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var report interface{}
+	if err := json.NewDecoder(bytes.NewReader(b)).Decode(&report); err != nil {
+		t.Fatal(err)
+	}
+	if err := schema.Validate(report); err != nil {
+		t.Errorf("json report doesn't match ReportSchema: %#v", err)
+	}
+	if findings, _ := report.([]interface{}); len(findings) != 2 {
+		t.Errorf("json report has %d findings, want 2", len(findings))
+	}
+}
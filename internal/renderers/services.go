@@ -20,10 +20,37 @@ func renderServices(f *excelize.File, data ReportData) {
 
 	heathers := []string{"Subscription", "Resource Group", "Location", "Type", "Service Name", "Broken", "Category", "Subcategory", "Severity", "Description", "Result", "Learn"}
 
+	createFirstRow(f, "Services", heathers)
+
+	rows := getServicesRows(data)
+
+	currentRow := 4
+	for _, row := range rows {
+		currentRow += 1
+		cell, err := excelize.CoordinatesToCellName(1, currentRow)
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = f.SetSheetRow("Services", cell, &row)
+		if err != nil {
+			log.Fatal(err)
+		}
+		setHyperLink(f, "Services", 12, currentRow)
+	}
+
+	configureSheet(f, "Services", heathers, currentRow)
+}
+
+// getServicesRows - Returns one row per rendered rule, broken rules first.
+// Passed rules are only rendered with --show-passed, unless they carry a result (e.g. SKU or SLA).
+func getServicesRows(data ReportData) [][]string {
 	rbroken := [][]string{}
 	rok := [][]string{}
 	for _, d := range data.MainData {
 		for _, r := range d.Rules {
+			if !r.IsBroken && !data.ShowPassed && r.Result == "" {
+				continue
+			}
 			row := []string{
 				scanners.MaskSubscriptionID(d.SubscriptionID, data.Mask),
 				d.ResourceGroup,
@@ -46,23 +73,5 @@ func renderServices(f *excelize.File, data ReportData) {
 		}
 	}
 
-	createFirstRow(f, "Services", heathers)
-
-	rows := append(rbroken, rok...)
-
-	currentRow := 4
-	for _, row := range rows {
-		currentRow += 1
-		cell, err := excelize.CoordinatesToCellName(1, currentRow)
-		if err != nil {
-			log.Fatal(err)
-		}
-		err = f.SetSheetRow("Services", cell, &row)
-		if err != nil {
-			log.Fatal(err)
-		}
-		setHyperLink(f, "Services", 12, currentRow)
-	}
-
-	configureSheet(f, "Services", heathers, currentRow)
+	return append(rbroken, rok...)
 }
@@ -0,0 +1,59 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+func TestGetServicesRows(t *testing.T) {
+	mainData := []scanners.AzureServiceResult{
+		{
+			ServiceName: "aks-test",
+			Rules: map[string]scanners.AzureRuleResult{
+				"aks-001": {Id: "aks-001", Description: "broken", IsBroken: true},
+				"aks-005": {Id: "aks-005", Description: "sku", IsBroken: false, Result: "Standard"},
+				"aks-006": {Id: "aks-006", Description: "passed", IsBroken: false},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		showPassed bool
+		want       []string
+	}{
+		{
+			name:       "only broken and informational rules",
+			showPassed: false,
+			want:       []string{"broken", "sku"},
+		},
+		{
+			name:       "all rules with show passed",
+			showPassed: true,
+			want:       []string{"broken", "passed", "sku"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := getServicesRows(ReportData{MainData: mainData, ShowPassed: tt.showPassed})
+			got := []string{}
+			for _, row := range rows {
+				got = append(got, row[9])
+			}
+			sort.Strings(got)
+			if len(got) != len(tt.want) {
+				t.Fatalf("getServicesRows() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("getServicesRows() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -5,19 +5,22 @@ package scanners
 
 import (
 	"log"
+	"sort"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/advisor/armadvisor"
 )
 
 // AdvisorResult - Advisor result
 type AdvisorResult struct {
-	SubscriptionID, Name, Type, Category, Description, PotentialBenefits, Risk, LearnMoreLink string
+	SubscriptionID, Name, Type, Category, Description, PotentialBenefits, Risk, LearnMoreLink, ResourceID string
 }
 
 // AdvisorScanner - Advisor scanner
 type AdvisorScanner struct {
-	config *ScannerConfig
-	client *armadvisor.RecommendationsClient
+	config                  *ScannerConfig
+	client                  *armadvisor.RecommendationsClient
+	listRecommendationsFunc func() ([]*armadvisor.ResourceRecommendationBase, error)
 }
 
 // GetProperties - Returns the properties of the AdvisorResult
@@ -63,15 +66,9 @@ func (s *AdvisorScanner) Init(config *ScannerConfig) error {
 func (s *AdvisorScanner) ListRecommendations() ([]AdvisorResult, error) {
 	log.Println("Scanning Advisor Recommendations...")
 
-	pager := s.client.NewListPager(&armadvisor.RecommendationsClientListOptions{})
-
-	recommendations := make([]*armadvisor.ResourceRecommendationBase, 0)
-	for pager.More() {
-		resp, err := pager.NextPage(s.config.Ctx)
-		if err != nil {
-			return nil, err
-		}
-		recommendations = append(recommendations, resp.Value...)
+	recommendations, err := s.listRecommendations()
+	if err != nil {
+		return nil, err
 	}
 
 	returnRecommendations := make([]AdvisorResult, 0)
@@ -92,8 +89,67 @@ func (s *AdvisorScanner) ListRecommendations() ([]AdvisorResult, error) {
 		if recommendation.Properties.LearnMoreLink != nil {
 			ar.LearnMoreLink = *recommendation.Properties.LearnMoreLink
 		}
+		if recommendation.Properties.ResourceMetadata != nil && recommendation.Properties.ResourceMetadata.ResourceID != nil {
+			ar.ResourceID = *recommendation.Properties.ResourceMetadata.ResourceID
+		}
 		returnRecommendations = append(returnRecommendations, ar)
 	}
 
 	return returnRecommendations, nil
 }
+
+func (s *AdvisorScanner) listRecommendations() ([]*armadvisor.ResourceRecommendationBase, error) {
+	if s.listRecommendationsFunc == nil {
+		pager := s.client.NewListPager(&armadvisor.RecommendationsClientListOptions{})
+
+		recommendations := make([]*armadvisor.ResourceRecommendationBase, 0)
+		for pager.More() {
+			resp, err := pager.NextPage(s.config.Ctx)
+			if err != nil {
+				return nil, err
+			}
+			recommendations = append(recommendations, resp.Value...)
+		}
+		return recommendations, nil
+	}
+
+	return s.listRecommendationsFunc()
+}
+
+// AdvisorCategories - Sorted categories of the Advisor recommendations, by lower case resource ID
+type AdvisorCategories map[string][]string
+
+// NewAdvisorCategories - Groups the categories of the recommendations by resource ID, so they can be attached to many batches of results
+func NewAdvisorCategories(recommendations []AdvisorResult) AdvisorCategories {
+	categories := map[string]map[string]bool{}
+	for _, r := range recommendations {
+		if r.ResourceID == "" {
+			continue
+		}
+		id := strings.ToLower(r.ResourceID)
+		if _, exists := categories[id]; !exists {
+			categories[id] = map[string]bool{}
+		}
+		categories[id][r.Category] = true
+	}
+
+	sorted := AdvisorCategories{}
+	for id, c := range categories {
+		for category := range c {
+			sorted[id] = append(sorted[id], category)
+		}
+		sort.Strings(sorted[id])
+	}
+	return sorted
+}
+
+// Add - Attaches the categories matching each result's resource ID
+func (c AdvisorCategories) Add(results []AzureServiceResult) {
+	for i := range results {
+		categories, exists := c[strings.ToLower(results[i].ResourceID)]
+		if !exists {
+			continue
+		}
+		results[i].AdvisorCategories = append([]string{}, categories...)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/advisor/armadvisor"
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+// getRecommendation - Returns an Advisor recommendation of the category for the resource. An empty resourceID has no resource metadata.
+func getRecommendation(category armadvisor.Category, resourceID string) *armadvisor.ResourceRecommendationBase {
+	r := &armadvisor.ResourceRecommendationBase{
+		Properties: &armadvisor.RecommendationProperties{
+			Category:         &category,
+			ImpactedField:    to.StringPtr("Microsoft.ContainerService/managedClusters"),
+			ImpactedValue:    to.StringPtr("aks-test"),
+			ShortDescription: &armadvisor.ShortDescription{Problem: to.StringPtr("problem")},
+		},
+	}
+	if resourceID != "" {
+		r.Properties.ResourceMetadata = &armadvisor.ResourceMetadata{ResourceID: to.StringPtr(resourceID)}
+	}
+	return r
+}
+
+func TestAdvisorScanner_ListRecommendations(t *testing.T) {
+	s := AdvisorScanner{
+		config: &ScannerConfig{SubscriptionID: "sub"},
+		listRecommendationsFunc: func() ([]*armadvisor.ResourceRecommendationBase, error) {
+			return []*armadvisor.ResourceRecommendationBase{
+				getRecommendation(armadvisor.CategoryHighAvailability, "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/aks-test"),
+				getRecommendation(armadvisor.CategoryCost, "/subscriptions/sub/resourcegroups/rg/providers/microsoft.containerservice/managedclusters/aks-test"),
+				getRecommendation(armadvisor.CategorySecurity, ""),
+			}, nil
+		},
+	}
+
+	rec, err := s.ListRecommendations()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := AdvisorResult{
+		SubscriptionID: "sub",
+		Name:           "aks-test",
+		Type:           "Microsoft.ContainerService/managedClusters",
+		Category:       "HighAvailability",
+		Description:    "problem",
+		ResourceID:     "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/aks-test",
+	}
+	if len(rec) != 3 || !reflect.DeepEqual(rec[0], want) {
+		t.Fatalf("ListRecommendations() = %v, want 3 recommendations, the first %v", rec, want)
+	}
+	if rec[2].ResourceID != "" {
+		t.Errorf("ListRecommendations() ResourceID = %s, want none without resource metadata", rec[2].ResourceID)
+	}
+
+	results := []AzureServiceResult{
+		{
+			ResourceID: "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/aks-test",
+		},
+		{
+			ResourceID: "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/aks-other",
+		},
+	}
+
+	NewAdvisorCategories(rec).Add(results)
+
+	categories := []string{"Cost", "HighAvailability"}
+	if !reflect.DeepEqual(results[0].AdvisorCategories, categories) {
+		t.Errorf("AdvisorCategories.Add() = %v, want %v", results[0].AdvisorCategories, categories)
+	}
+	if results[1].AdvisorCategories != nil {
+		t.Errorf("AdvisorCategories.Add() = %v, want no categories", results[1].AdvisorCategories)
+	}
+}
@@ -12,10 +12,15 @@ import (
 
 // FrontDoorScanner - Scanner for Front Door
 type FrontDoorScanner struct {
-	config              *scanners.ScannerConfig
-	diagnosticsSettings scanners.DiagnosticsSettings
-	client              *armcdn.ProfilesClient
-	listFunc            func(resourceGroupName string) ([]*armcdn.Profile, error)
+	config               *scanners.ScannerConfig
+	diagnosticsSettings  scanners.DiagnosticsSettings
+	client               *armcdn.ProfilesClient
+	originGroupsClient   *armcdn.AFDOriginGroupsClient
+	endpointsClient      *armcdn.AFDEndpointsClient
+	routesClient         *armcdn.RoutesClient
+	listFunc             func(resourceGroupName string) ([]*armcdn.Profile, error)
+	listOriginGroupsFunc func(resourceGroupName string, profileName string) ([]*armcdn.AFDOriginGroup, error)
+	listRoutesFunc       func(resourceGroupName string, profileName string) ([]*armcdn.Route, error)
 }
 
 // Init - Initializes the FrontDoor Scanner
@@ -26,6 +31,18 @@ func (a *FrontDoorScanner) Init(config *scanners.ScannerConfig) error {
 	if err != nil {
 		return err
 	}
+	a.originGroupsClient, err = armcdn.NewAFDOriginGroupsClient(config.SubscriptionID, a.config.Cred, a.config.ClientOptions)
+	if err != nil {
+		return err
+	}
+	a.endpointsClient, err = armcdn.NewAFDEndpointsClient(config.SubscriptionID, a.config.Cred, a.config.ClientOptions)
+	if err != nil {
+		return err
+	}
+	a.routesClient, err = armcdn.NewRoutesClient(config.SubscriptionID, a.config.Cred, a.config.ClientOptions)
+	if err != nil {
+		return err
+	}
 	a.diagnosticsSettings = scanners.DiagnosticsSettings{}
 	err = a.diagnosticsSettings.Init(config)
 	if err != nil {
@@ -42,7 +59,7 @@ func (a *FrontDoorScanner) Scan(resourceGroupName string, scanContext *scanners.
 	if err != nil {
 		return nil, err
 	}
-	engine := scanners.RuleEngine{}
+	engine := scanners.RuleEngine{Cache: a.config.RuleCache}
 	rules := a.GetRules()
 	results := []scanners.AzureServiceResult{}
 
@@ -53,7 +70,10 @@ func (a *FrontDoorScanner) Scan(resourceGroupName string, scanContext *scanners.
 			SubscriptionID: a.config.SubscriptionID,
 			ResourceGroup:  resourceGroupName,
 			Location:       *g.Location,
+			Tags:           g.Tags,
+			CreatedAt:      scanners.GetCreatedAt(g),
 			Type:           *g.Type,
+			ResourceID:     *g.ID,
 			ServiceName:    *g.Name,
 			Rules:          rr,
 		})
@@ -72,9 +92,70 @@ func (a *FrontDoorScanner) list(resourceGroupName string) ([]*armcdn.Profile, er
 				return nil, err
 			}
 			services = append(services, resp.Value...)
+			if limited, ok := scanners.LimitResources(a.config, services); ok {
+				return limited, nil
+			}
 		}
 		return services, nil
 	}
 
 	return a.listFunc(resourceGroupName)
 }
+
+// listOriginGroups - Returns the origin groups of the Front Door profile
+func (a *FrontDoorScanner) listOriginGroups(resourceGroupName string, profileName string) ([]*armcdn.AFDOriginGroup, error) {
+	if a.listOriginGroupsFunc == nil {
+		pager := a.originGroupsClient.NewListByProfilePager(resourceGroupName, profileName, nil)
+
+		groups := make([]*armcdn.AFDOriginGroup, 0)
+		for pager.More() {
+			resp, err := pager.NextPage(a.config.Ctx)
+			if err != nil {
+				return nil, err
+			}
+			groups = append(groups, resp.Value...)
+		}
+		return groups, nil
+	}
+
+	return a.listOriginGroupsFunc(resourceGroupName, profileName)
+}
+
+// listRoutes - Returns the routes of all the endpoints of the Front Door profile
+func (a *FrontDoorScanner) listRoutes(resourceGroupName string, profileName string) ([]*armcdn.Route, error) {
+	if a.listRoutesFunc == nil {
+		pager := a.endpointsClient.NewListByProfilePager(resourceGroupName, profileName, nil)
+
+		routes := make([]*armcdn.Route, 0)
+		for pager.More() {
+			resp, err := pager.NextPage(a.config.Ctx)
+			if err != nil {
+				return nil, err
+			}
+			for _, endpoint := range resp.Value {
+				routesPager := a.routesClient.NewListByEndpointPager(resourceGroupName, profileName, *endpoint.Name, nil)
+				for routesPager.More() {
+					routesResp, err := routesPager.NextPage(a.config.Ctx)
+					if err != nil {
+						return nil, err
+					}
+					routes = append(routes, routesResp.Value...)
+				}
+			}
+		}
+		return routes, nil
+	}
+
+	return a.listRoutesFunc(resourceGroupName, profileName)
+}
+
+// isFrontDoor - Returns true if the profile is an Azure Front Door (Standard or Premium) and not a CDN profile
+func isFrontDoor(c *armcdn.Profile) bool {
+	return c.SKU != nil && c.SKU.Name != nil &&
+		(*c.SKU.Name == armcdn.SKUNameStandardAzureFrontDoor || *c.SKU.Name == armcdn.SKUNamePremiumAzureFrontDoor)
+}
+
+// GetResourceTypes - Returns the resource types scanned by the FrontDoorScanner
+func (a *FrontDoorScanner) GetResourceTypes() []string {
+	return []string{"Microsoft.Cdn/profiles"}
+}
@@ -4,13 +4,17 @@
 package afd
 
 import (
-	"log"
+	"fmt"
 	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/cdn/armcdn"
 	"github.com/cmendible/azqr/internal/scanners"
 )
 
+// maxProbeIntervalInSeconds - Longest health probe interval before failover to a healthy origin is considered too slow
+const maxProbeIntervalInSeconds = 120
+
 // GetRules - Returns the rules for the FrontDoorScanner
 func (a *FrontDoorScanner) GetRules() map[string]scanners.AzureRule {
 	return map[string]scanners.AzureRule{
@@ -22,12 +26,7 @@ func (a *FrontDoorScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Medium",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				service := target.(*armcdn.Profile)
-				hasDiagnostics, err := a.diagnosticsSettings.HasDiagnostics(*service.ID)
-				if err != nil {
-					log.Fatalf("Error checking diagnostic settings for service %s: %s", *service.Name, err)
-				}
-
-				return !hasDiagnostics, ""
+				return a.diagnosticsSettings.EvalDiagnostics("afd-001", *service.ID, scanContext)
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/frontdoor/standard-premium/how-to-logs",
 		},
@@ -62,7 +61,7 @@ func (a *FrontDoorScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Low",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armcdn.Profile)
-				caf := strings.HasPrefix(*c.Name, "afd")
+				caf := scanners.HasCAFPrefix(*c.Name, "afd", scanContext)
 				return !caf, ""
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
@@ -79,5 +78,88 @@ func (a *FrontDoorScanner) GetRules() map[string]scanners.AzureRule {
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
+		"afd-008": {
+			Id:          "afd-008",
+			Category:    "High Availability and Resiliency",
+			Subcategory: "Reliability",
+			Description: "Azure FrontDoor origin groups should have a health probe with a short interval",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armcdn.Profile)
+				if !isFrontDoor(c) {
+					return false, ""
+				}
+				resource, err := arm.ParseResourceID(*c.ID)
+				if err != nil {
+					return scanContext.RuleError("afd-008", *c.ID, err)
+				}
+				groups, err := a.listOriginGroups(resource.ResourceGroupName, *c.Name)
+				if err != nil {
+					return scanContext.RuleError("afd-008", *c.ID, fmt.Errorf("listing origin groups: %w", err))
+				}
+
+				broken := false
+				probes := []string{}
+				for _, g := range groups {
+					if g.Properties == nil || g.Properties.HealthProbeSettings == nil || g.Properties.HealthProbeSettings.ProbeIntervalInSeconds == nil {
+						broken = true
+						probes = append(probes, fmt.Sprintf("%s: No Probe", *g.Name))
+						continue
+					}
+					interval := *g.Properties.HealthProbeSettings.ProbeIntervalInSeconds
+					if interval > maxProbeIntervalInSeconds {
+						broken = true
+					}
+					probes = append(probes, fmt.Sprintf("%s: %ds", *g.Name, interval))
+				}
+				return broken, strings.Join(probes, ", ")
+			},
+			CacheKey: func(target interface{}, scanContext *scanners.ScanContext) interface{} {
+				c := target.(*armcdn.Profile)
+				return []interface{}{c.ID, c.SKU}
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/frontdoor/health-probes",
+		},
+		"afd-009": {
+			Id:          "afd-009",
+			Category:    "Operations",
+			Subcategory: "Performance",
+			Description: "Azure FrontDoor routes caching and compression",
+			Severity:    "Low",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armcdn.Profile)
+				if !isFrontDoor(c) {
+					return false, ""
+				}
+				resource, err := arm.ParseResourceID(*c.ID)
+				if err != nil {
+					return scanContext.RuleError("afd-009", *c.ID, err)
+				}
+				routes, err := a.listRoutes(resource.ResourceGroupName, *c.Name)
+				if err != nil {
+					return scanContext.RuleError("afd-009", *c.ID, fmt.Errorf("listing routes: %w", err))
+				}
+
+				states := []string{}
+				for _, r := range routes {
+					if r.Properties == nil || r.Properties.CacheConfiguration == nil {
+						states = append(states, fmt.Sprintf("%s: Not Cached", *r.Name))
+						continue
+					}
+					compression := "Not Compressed"
+					settings := r.Properties.CacheConfiguration.CompressionSettings
+					if settings != nil && settings.IsCompressionEnabled != nil && *settings.IsCompressionEnabled {
+						compression = "Compressed"
+					}
+					states = append(states, fmt.Sprintf("%s: Cached, %s", *r.Name, compression))
+				}
+				return false, strings.Join(states, "; ")
+			},
+			CacheKey: func(target interface{}, scanContext *scanners.ScanContext) interface{} {
+				c := target.(*armcdn.Profile)
+				return []interface{}{c.ID, c.SKU}
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/frontdoor/front-door-caching",
+		},
 	}
 }
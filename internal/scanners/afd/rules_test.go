@@ -115,3 +115,199 @@ func getSKU() *armcdn.SKUName {
 	s := armcdn.SKUNameStandardMicrosoft
 	return &s
 }
+
+func TestFrontDoorScanner_OriginGroupsRule(t *testing.T) {
+	type want struct {
+		broken bool
+		result string
+	}
+	tests := []struct {
+		name   string
+		groups []*armcdn.AFDOriginGroup
+		want   want
+	}{
+		{
+			name: "FrontDoorScanner origin group with health probe",
+			groups: []*armcdn.AFDOriginGroup{
+				{
+					Name: to.StringPtr("og"),
+					Properties: &armcdn.AFDOriginGroupProperties{
+						HealthProbeSettings: &armcdn.HealthProbeParameters{
+							ProbeIntervalInSeconds: to.Int32Ptr(30),
+						},
+					},
+				},
+			},
+			want: want{
+				broken: false,
+				result: "og: 30s",
+			},
+		},
+		{
+			name: "FrontDoorScanner origin group without health probe",
+			groups: []*armcdn.AFDOriginGroup{
+				{
+					Name:       to.StringPtr("og"),
+					Properties: &armcdn.AFDOriginGroupProperties{},
+				},
+			},
+			want: want{
+				broken: true,
+				result: "og: No Probe",
+			},
+		},
+		{
+			name: "FrontDoorScanner origin group with long probe interval",
+			groups: []*armcdn.AFDOriginGroup{
+				{
+					Name: to.StringPtr("og"),
+					Properties: &armcdn.AFDOriginGroupProperties{
+						HealthProbeSettings: &armcdn.HealthProbeParameters{
+							ProbeIntervalInSeconds: to.Int32Ptr(240),
+						},
+					},
+				},
+			},
+			want: want{
+				broken: true,
+				result: "og: 240s",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &FrontDoorScanner{
+				listOriginGroupsFunc: func(resourceGroupName string, profileName string) ([]*armcdn.AFDOriginGroup, error) {
+					if resourceGroupName != "rg" || profileName != "afd-test" {
+						t.Errorf("unexpected profile %s/%s", resourceGroupName, profileName)
+					}
+					return tt.groups, nil
+				},
+			}
+			rules := s.GetRules()
+			b, w := rules["afd-008"].Eval(&armcdn.Profile{
+				ID:   to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Cdn/profiles/afd-test"),
+				Name: to.StringPtr("afd-test"),
+				SKU: &armcdn.SKU{
+					Name: getSKUName(armcdn.SKUNameStandardAzureFrontDoor),
+				},
+			}, &scanners.ScanContext{})
+			got := want{
+				broken: b,
+				result: w,
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FrontDoorScanner Rule.Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func getSKUName(s armcdn.SKUName) *armcdn.SKUName {
+	return &s
+}
+
+func TestFrontDoorScanner_RoutesRule(t *testing.T) {
+	type want struct {
+		broken bool
+		result string
+	}
+	tests := []struct {
+		name   string
+		routes []*armcdn.Route
+		want   want
+	}{
+		{
+			name: "FrontDoorScanner cached route",
+			routes: []*armcdn.Route{
+				{
+					Name: to.StringPtr("static"),
+					Properties: &armcdn.RouteProperties{
+						CacheConfiguration: &armcdn.AfdRouteCacheConfiguration{
+							CompressionSettings: &armcdn.CompressionSettings{
+								IsCompressionEnabled: to.BoolPtr(true),
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				broken: false,
+				result: "static: Cached, Compressed",
+			},
+		},
+		{
+			name: "FrontDoorScanner uncached route",
+			routes: []*armcdn.Route{
+				{
+					Name:       to.StringPtr("api"),
+					Properties: &armcdn.RouteProperties{},
+				},
+			},
+			want: want{
+				broken: false,
+				result: "api: Not Cached",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &FrontDoorScanner{
+				listRoutesFunc: func(resourceGroupName string, profileName string) ([]*armcdn.Route, error) {
+					return tt.routes, nil
+				},
+			}
+			rules := s.GetRules()
+			b, w := rules["afd-009"].Eval(&armcdn.Profile{
+				ID:   to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Cdn/profiles/afd-test"),
+				Name: to.StringPtr("afd-test"),
+				SKU: &armcdn.SKU{
+					Name: getSKUName(armcdn.SKUNamePremiumAzureFrontDoor),
+				},
+			}, &scanners.ScanContext{})
+			got := want{
+				broken: b,
+				result: w,
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FrontDoorScanner Rule.Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFrontDoorScanner_RoutesRuleCache(t *testing.T) {
+	calls := 0
+	s := &FrontDoorScanner{
+		listRoutesFunc: func(resourceGroupName string, profileName string) ([]*armcdn.Route, error) {
+			calls++
+			return []*armcdn.Route{
+				{
+					Name:       to.StringPtr("api"),
+					Properties: &armcdn.RouteProperties{},
+				},
+			}, nil
+		},
+	}
+	engine := scanners.RuleEngine{Cache: scanners.NewRuleCache()}
+	rule := s.GetRules()["afd-009"]
+
+	// A retried scan evaluates the same profiles again
+	for i := 0; i < 2; i++ {
+		for _, name := range []string{"afd-1", "afd-2"} {
+			got := engine.EvaluateRule(rule, &armcdn.Profile{
+				ID:   to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Cdn/profiles/" + name),
+				Name: to.StringPtr(name),
+				SKU: &armcdn.SKU{
+					Name: getSKUName(armcdn.SKUNamePremiumAzureFrontDoor),
+				},
+			}, &scanners.ScanContext{})
+			if got.Result != "api: Not Cached" {
+				t.Errorf("EvaluateRule() result = %s, want api: Not Cached", got.Result)
+			}
+		}
+	}
+	if calls != 2 {
+		t.Errorf("afd-009 listed routes %d times, want once per profile", calls)
+	}
+}
@@ -42,7 +42,7 @@ func (a *FirewallScanner) Scan(resourceGroupName string, scanContext *scanners.S
 	if err != nil {
 		return nil, err
 	}
-	engine := scanners.RuleEngine{}
+	engine := scanners.RuleEngine{Cache: a.config.RuleCache}
 	rules := a.GetRules()
 	results := []scanners.AzureServiceResult{}
 
@@ -53,7 +53,10 @@ func (a *FirewallScanner) Scan(resourceGroupName string, scanContext *scanners.S
 			SubscriptionID: a.config.SubscriptionID,
 			ResourceGroup:  resourceGroupName,
 			Location:       *g.Location,
+			Tags:           g.Tags,
+			CreatedAt:      scanners.GetCreatedAt(g),
 			Type:           *g.Type,
+			ResourceID:     *g.ID,
 			ServiceName:    *g.Name,
 			Rules:          rr,
 		})
@@ -72,9 +75,17 @@ func (a *FirewallScanner) list(resourceGroupName string) ([]*armnetwork.AzureFir
 				return nil, err
 			}
 			services = append(services, resp.Value...)
+			if limited, ok := scanners.LimitResources(a.config, services); ok {
+				return limited, nil
+			}
 		}
 		return services, nil
 	}
 
 	return a.listFunc(resourceGroupName)
 }
+
+// GetResourceTypes - Returns the resource types scanned by the FirewallScanner
+func (a *FirewallScanner) GetResourceTypes() []string {
+	return []string{"Microsoft.Network/azureFirewalls"}
+}
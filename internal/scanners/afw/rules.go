@@ -4,9 +4,6 @@
 package afw
 
 import (
-	"log"
-	"strings"
-
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
 	"github.com/cmendible/azqr/internal/scanners"
 )
@@ -21,12 +18,7 @@ func (a *FirewallScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Medium",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				service := target.(*armnetwork.AzureFirewall)
-				hasDiagnostics, err := a.diagnosticsSettings.HasDiagnostics(*service.ID)
-				if err != nil {
-					log.Fatalf("Error checking diagnostic settings for service %s: %s", *service.Name, err)
-				}
-
-				return !hasDiagnostics, ""
+				return a.diagnosticsSettings.EvalDiagnostics("afw-001", *service.ID, scanContext)
 			},
 			Url: "https://docs.microsoft.com/en-us/azure/firewall/logs-and-metrics",
 		},
@@ -80,7 +72,7 @@ func (a *FirewallScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Low",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armnetwork.AzureFirewall)
-				caf := strings.HasPrefix(*c.Name, "afw")
+				caf := scanners.HasCAFPrefix(*c.Name, "afw", scanContext)
 				return !caf, ""
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
@@ -0,0 +1,103 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ResourceAgeRuleID - Id of the rule added by AddResourceAgeRule
+const ResourceAgeRuleID = "ResourceAge"
+
+// createdTagKeys - Tags, compared case insensitively, used to record when a resource was created
+var createdTagKeys = []string{"createdon", "createdat", "created", "creationdate"}
+
+// createdTagLayouts - Date formats accepted in the created tags
+var createdTagLayouts = []string{time.RFC3339, "2006-01-02", "2006/01/02", "01/02/2006"}
+
+// GetCreatedAt - Returns the value of target.SystemData.CreatedAt, or nil if the resource doesn't expose it
+func GetCreatedAt(target interface{}) *time.Time {
+	v := reflect.ValueOf(target)
+	for _, field := range []string{"SystemData", "CreatedAt"} {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return nil
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return nil
+		}
+		v = v.FieldByName(field)
+		if !v.IsValid() {
+			return nil
+		}
+	}
+
+	createdAt, ok := v.Interface().(*time.Time)
+	if !ok {
+		return nil
+	}
+	return createdAt
+}
+
+// getCreatedAt - Returns when the result was created, from its system data or else from a created tag (e.g. createdOn=2023-01-31)
+func getCreatedAt(r AzureServiceResult) (time.Time, bool) {
+	if r.CreatedAt != nil {
+		return *r.CreatedAt, true
+	}
+	for _, key := range createdTagKeys {
+		value, ok := getTagValue(r.Tags, key)
+		if !ok {
+			continue
+		}
+		for _, layout := range createdTagLayouts {
+			if t, err := time.Parse(layout, strings.TrimSpace(value)); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// NewResourceAgeRule - Returns a rule flagging results created more than maxAgeDays before now, to help find stale resources.
+// Results without creation metadata are not flagged.
+func NewResourceAgeRule(maxAgeDays int, now time.Time) AzureRule {
+	return AzureRule{
+		Id:          ResourceAgeRuleID,
+		Category:    "Governance",
+		Subcategory: "Lifecycle Management",
+		Description: fmt.Sprintf("Resources older than %d days should be reviewed", maxAgeDays),
+		Severity:    "Low",
+		Eval: func(target interface{}, scanContext *ScanContext) (bool, string) {
+			r := target.(AzureServiceResult)
+			createdAt, ok := getCreatedAt(r)
+			if !ok {
+				return false, ""
+			}
+			days := int(now.Sub(createdAt).Hours() / 24)
+			return days > maxAgeDays, fmt.Sprintf("Created %s (%d days)", createdAt.Format("2006-01-02"), days)
+		},
+		Url: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/govern/",
+	}
+}
+
+// AddResourceAgeRule - Evaluates the resource age rule against every result. Does nothing when maxAgeDays is 0 or less.
+func AddResourceAgeRule(results []AzureServiceResult, maxAgeDays int) {
+	if maxAgeDays <= 0 {
+		return
+	}
+
+	engine := RuleEngine{}
+	rule := NewResourceAgeRule(maxAgeDays, time.Now())
+	for i := range results {
+		if results[i].Rules == nil {
+			results[i].Rules = map[string]AzureRuleResult{}
+		}
+		results[i].Rules[ResourceAgeRuleID] = engine.EvaluateRule(rule, results[i], nil)
+	}
+}
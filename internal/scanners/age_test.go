@@ -0,0 +1,104 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+func TestNewResourceAgeRule(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	recent := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	type want struct {
+		broken bool
+		result string
+	}
+	tests := []struct {
+		name   string
+		result AzureServiceResult
+		want   want
+	}{
+		{
+			name:   "old resource",
+			result: AzureServiceResult{CreatedAt: &old},
+			want: want{
+				broken: true,
+				result: "Created 2022-06-01 (731 days)",
+			},
+		},
+		{
+			name:   "recent resource",
+			result: AzureServiceResult{CreatedAt: &recent},
+			want: want{
+				broken: false,
+				result: "Created 2024-05-01 (31 days)",
+			},
+		},
+		{
+			name: "old resource from tag",
+			result: AzureServiceResult{
+				Tags: map[string]*string{"CreatedOn": to.StringPtr("2023-01-31")},
+			},
+			want: want{
+				broken: true,
+				result: "Created 2023-01-31 (487 days)",
+			},
+		},
+		{
+			name: "system data takes precedence over tags",
+			result: AzureServiceResult{
+				CreatedAt: &recent,
+				Tags:      map[string]*string{"createdOn": to.StringPtr("2020-01-01")},
+			},
+			want: want{
+				broken: false,
+				result: "Created 2024-05-01 (31 days)",
+			},
+		},
+		{
+			name: "unknown creation date",
+			result: AzureServiceResult{
+				Tags: map[string]*string{"createdOn": to.StringPtr("last year")},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+	}
+	rule := NewResourceAgeRule(365, now)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broken, result := rule.Eval(tt.result, nil)
+			if broken != tt.want.broken || result != tt.want.result {
+				t.Errorf("ResourceAge Eval() = %v, %s, want %v", broken, result, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCreatedAt(t *testing.T) {
+	createdAt := time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC)
+	type systemData struct {
+		CreatedAt *time.Time
+	}
+	type resource struct {
+		SystemData *systemData
+	}
+
+	if got := GetCreatedAt(&resource{SystemData: &systemData{CreatedAt: &createdAt}}); got == nil || !got.Equal(createdAt) {
+		t.Errorf("GetCreatedAt() = %v, want %v", got, createdAt)
+	}
+	if got := GetCreatedAt(&resource{}); got != nil {
+		t.Errorf("GetCreatedAt() without system data = %v, want nil", got)
+	}
+	if got := GetCreatedAt(&struct{ Name string }{}); got != nil {
+		t.Errorf("GetCreatedAt() without a SystemData field = %v, want nil", got)
+	}
+}
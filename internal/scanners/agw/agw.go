@@ -42,7 +42,7 @@ func (a *ApplicationGatewayScanner) Scan(resourceGroupName string, scanContext *
 	if err != nil {
 		return nil, err
 	}
-	engine := scanners.RuleEngine{}
+	engine := scanners.RuleEngine{Cache: a.config.RuleCache}
 	rules := a.GetRules()
 	results := []scanners.AzureServiceResult{}
 
@@ -52,9 +52,12 @@ func (a *ApplicationGatewayScanner) Scan(resourceGroupName string, scanContext *
 		results = append(results, scanners.AzureServiceResult{
 			SubscriptionID: a.config.SubscriptionID,
 			ResourceGroup:  resourceGroupName,
+			ResourceID:     *g.ID,
 			ServiceName:    *g.Name,
 			Type:           *g.Type,
 			Location:       *g.Location,
+			Tags:           g.Tags,
+			CreatedAt:      scanners.GetCreatedAt(g),
 			Rules:          rr,
 		})
 	}
@@ -71,9 +74,17 @@ func (a *ApplicationGatewayScanner) listGateways(resourceGroupName string) ([]*a
 				return nil, err
 			}
 			results = append(results, resp.Value...)
+			if limited, ok := scanners.LimitResources(a.config, results); ok {
+				return limited, nil
+			}
 		}
 		return results, nil
 	}
 
 	return a.listGatewaysFunc(resourceGroupName)
 }
+
+// GetResourceTypes - Returns the resource types scanned by the ApplicationGatewayScanner
+func (a *ApplicationGatewayScanner) GetResourceTypes() []string {
+	return []string{"Microsoft.Network/applicationGateways"}
+}
@@ -4,18 +4,25 @@
 package aks
 
 import (
+	"fmt"
 	"log"
+	"strings"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v4"
 	"github.com/cmendible/azqr/internal/scanners"
 )
 
 // AKSScanner - Scanner for AKS Clusters
 type AKSScanner struct {
-	config              *scanners.ScannerConfig
-	diagnosticsSettings scanners.DiagnosticsSettings
-	clustersClient      *armcontainerservice.ManagedClustersClient
-	listClustersFunc    func(resourceGroupName string) ([]*armcontainerservice.ManagedCluster, error)
+	config                 *scanners.ScannerConfig
+	diagnosticsSettings    scanners.DiagnosticsSettings
+	clustersClient         *armcontainerservice.ManagedClustersClient
+	graph                  scanners.ResourceGraph
+	listClustersFunc       func(resourceGroupName string) ([]*armcontainerservice.ManagedCluster, error)
+	listVnetIntegratedFunc func() ([]string, error)
+	listLockedFunc         func() ([]string, error)
+	listInsightsFunc       func() ([]string, error)
+	listConstraintsFunc    func() (map[string]int, error)
 }
 
 // Init - Initializes the AKSScanner
@@ -26,6 +33,11 @@ func (a *AKSScanner) Init(config *scanners.ScannerConfig) error {
 	if err != nil {
 		return err
 	}
+	a.graph = scanners.ResourceGraph{}
+	err = a.graph.Init(config)
+	if err != nil {
+		return err
+	}
 	a.diagnosticsSettings = scanners.DiagnosticsSettings{}
 	err = a.diagnosticsSettings.Init(config)
 	if err != nil {
@@ -42,7 +54,7 @@ func (a *AKSScanner) Scan(resourceGroupName string, scanContext *scanners.ScanCo
 	if err != nil {
 		return nil, err
 	}
-	engine := scanners.RuleEngine{}
+	engine := scanners.RuleEngine{Cache: a.config.RuleCache}
 	rules := a.GetRules()
 	results := []scanners.AzureServiceResult{}
 
@@ -54,7 +66,10 @@ func (a *AKSScanner) Scan(resourceGroupName string, scanContext *scanners.ScanCo
 			SubscriptionID: a.config.SubscriptionID,
 			ResourceGroup:  resourceGroupName,
 			Location:       *c.Location,
+			Tags:           c.Tags,
+			CreatedAt:      scanners.GetCreatedAt(c),
 			Type:           *c.Type,
+			ResourceID:     *c.ID,
 			ServiceName:    *c.Name,
 			Rules:          rr,
 		})
@@ -63,6 +78,45 @@ func (a *AKSScanner) Scan(resourceGroupName string, scanContext *scanners.ScanCo
 	return results, nil
 }
 
+// LoadScanContext - Loads the cluster settings read from Resource Graph, and the locked resource groups, into the ScanContext.
+// Each setting is read with a single query for the whole subscription.
+func (a *AKSScanner) LoadScanContext(resourceGroups []string, scanContext *scanners.ScanContext) error {
+	vnetIntegrated, err := a.listVnetIntegrated()
+	if err != nil {
+		return err
+	}
+	insights, err := a.listInsights()
+	if err != nil {
+		return err
+	}
+	constraints, err := a.listConstraints()
+	if err != nil {
+		return err
+	}
+	locked, err := a.listLocked()
+	if err != nil {
+		return err
+	}
+
+	scanContext.VnetIntegratedClusters = map[string]bool{}
+	for _, id := range vnetIntegrated {
+		scanContext.VnetIntegratedClusters[strings.ToLower(id)] = true
+	}
+	scanContext.ContainerInsightsClusters = map[string]bool{}
+	for _, id := range insights {
+		scanContext.ContainerInsightsClusters[strings.ToLower(id)] = true
+	}
+	scanContext.PolicyConstraints = map[string]int{}
+	for id, count := range constraints {
+		scanContext.PolicyConstraints[strings.ToLower(id)] = count
+	}
+	scanContext.LockedResourceGroups = map[string]bool{}
+	for _, rg := range locked {
+		scanContext.LockedResourceGroups[strings.ToLower(rg)] = true
+	}
+	return nil
+}
+
 func (a *AKSScanner) listClusters(resourceGroupName string) ([]*armcontainerservice.ManagedCluster, error) {
 	if a.listClustersFunc == nil {
 		pager := a.clustersClient.NewListByResourceGroupPager(resourceGroupName, nil)
@@ -74,9 +128,109 @@ func (a *AKSScanner) listClusters(resourceGroupName string) ([]*armcontainerserv
 				return nil, err
 			}
 			clusters = append(clusters, resp.Value...)
+			if limited, ok := scanners.LimitResources(a.config, clusters); ok {
+				return limited, nil
+			}
 		}
 		return clusters, nil
 	}
 
 	return a.listClustersFunc(resourceGroupName)
 }
+
+// listVnetIntegrated - Returns the ids of the clusters in the subscription with API Server VNet Integration enabled.
+// The setting is not exposed by the stable SDK, so it's read from Resource Graph.
+func (a *AKSScanner) listVnetIntegrated() ([]string, error) {
+	if a.listVnetIntegratedFunc == nil {
+		query := "resources | where type =~ 'microsoft.containerservice/managedclusters' and properties.apiServerAccessProfile.enableVnetIntegration == true | project id"
+		rows, err := a.graph.Query(query, []string{a.config.SubscriptionID})
+		if err != nil {
+			return nil, err
+		}
+		ids := []string{}
+		for _, row := range rows {
+			if id, ok := row["id"].(string); ok {
+				ids = append(ids, id)
+			}
+		}
+		return ids, nil
+	}
+
+	return a.listVnetIntegratedFunc()
+}
+
+// listInsights - Returns the ids of the clusters in the subscription with Container Insights enabled in their Azure Monitor profile.
+// The setting is not exposed by the stable SDK, so it's read from Resource Graph.
+func (a *AKSScanner) listInsights() ([]string, error) {
+	if a.listInsightsFunc == nil {
+		query := "resources | where type =~ 'microsoft.containerservice/managedclusters' and properties.azureMonitorProfile.containerInsights.enabled == true | project id"
+		rows, err := a.graph.Query(query, []string{a.config.SubscriptionID})
+		if err != nil {
+			return nil, err
+		}
+		ids := []string{}
+		for _, row := range rows {
+			if id, ok := row["id"].(string); ok {
+				ids = append(ids, id)
+			}
+		}
+		return ids, nil
+	}
+
+	return a.listInsightsFunc()
+}
+
+// listConstraints - Returns the number of Kubernetes policies, enforced as Gatekeeper constraints by the Azure Policy add-on,
+// assigned to each cluster in the subscription. Policy states are read from Resource Graph.
+func (a *AKSScanner) listConstraints() (map[string]int, error) {
+	if a.listConstraintsFunc == nil {
+		query := "policyresources | where type =~ 'microsoft.policyinsights/policystates' and tostring(properties.resourceType) =~ 'microsoft.containerservice/managedclusters' | extend definitionId=tolower(tostring(properties.policyDefinitionId)) | join kind=inner (policyresources | where type =~ 'microsoft.authorization/policydefinitions' and tostring(properties.mode) =~ 'Microsoft.Kubernetes.Data' | project definitionId=tolower(id)) on definitionId | summarize count=dcount(definitionId) by id=tolower(tostring(properties.resourceId))"
+		rows, err := a.graph.Query(query, []string{a.config.SubscriptionID})
+		if err != nil {
+			return nil, err
+		}
+		constraints := map[string]int{}
+		for _, row := range rows {
+			id, ok := row["id"].(string)
+			if !ok {
+				continue
+			}
+			if count, ok := row["count"].(float64); ok {
+				constraints[id] = int(count)
+			}
+		}
+		return constraints, nil
+	}
+
+	return a.listConstraintsFunc()
+}
+
+// listLocked - Returns the resource groups in the subscription with a CanNotDelete or ReadOnly lock at resource group level.
+// Locks are read from Resource Graph since the locks SDK is not a dependency.
+func (a *AKSScanner) listLocked() ([]string, error) {
+	if a.listLockedFunc == nil {
+		query := "resources | where type =~ 'microsoft.authorization/locks' and properties.level in~ ('CanNotDelete', 'ReadOnly') | project id, resourceGroup"
+		rows, err := a.graph.Query(query, []string{a.config.SubscriptionID})
+		if err != nil {
+			return nil, err
+		}
+		locked := []string{}
+		for _, row := range rows {
+			id, _ := row["id"].(string)
+			rg, _ := row["resourceGroup"].(string)
+			// Only locks on the resource group itself, not on the resources it contains
+			scope := fmt.Sprintf("/subscriptions/%s/resourcegroups/%s/providers/microsoft.authorization/locks/", strings.ToLower(a.config.SubscriptionID), strings.ToLower(rg))
+			if strings.HasPrefix(strings.ToLower(id), scope) {
+				locked = append(locked, rg)
+			}
+		}
+		return locked, nil
+	}
+
+	return a.listLockedFunc()
+}
+
+// GetResourceTypes - Returns the resource types scanned by the AKSScanner
+func (a *AKSScanner) GetResourceTypes() []string {
+	return []string{"Microsoft.ContainerService/managedClusters"}
+}
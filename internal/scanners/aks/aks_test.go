@@ -0,0 +1,154 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package aks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	azfake "github.com/Azure/azure-sdk-for-go/sdk/azcore/fake"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v4"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v4/fake"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+// countingTransport - Counts the requests sent to the fake server
+type countingTransport struct {
+	transport *fake.ManagedClustersServerTransport
+	requests  int
+}
+
+func (c *countingTransport) Do(req *http.Request) (*http.Response, error) {
+	c.requests++
+	return c.transport.Do(req)
+}
+
+func TestAKSScanner_Limit(t *testing.T) {
+	srv := fake.ManagedClustersServer{
+		NewListByResourceGroupPager: func(resourceGroupName string, options *armcontainerservice.ManagedClustersClientListByResourceGroupOptions) (resp azfake.PagerResponder[armcontainerservice.ManagedClustersClientListByResourceGroupResponse]) {
+			for p := 0; p < 3; p++ {
+				page := armcontainerservice.ManagedClustersClientListByResourceGroupResponse{}
+				for c := 0; c < 3; c++ {
+					page.Value = append(page.Value, &armcontainerservice.ManagedCluster{
+						Name: to.StringPtr(fmt.Sprintf("aks-%d-%d", p, c)),
+					})
+				}
+				resp.AddPage(http.StatusOK, page, nil)
+			}
+			return
+		},
+	}
+
+	tests := []struct {
+		name     string
+		limit    int
+		want     int
+		requests int
+	}{
+		{
+			name:     "AKSScanner limit within the first page",
+			limit:    2,
+			want:     2,
+			requests: 1,
+		},
+		{
+			name:     "AKSScanner limit across pages",
+			limit:    5,
+			want:     5,
+			requests: 2,
+		},
+		{
+			name:     "AKSScanner no limit",
+			limit:    0,
+			want:     9,
+			requests: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &countingTransport{transport: fake.NewManagedClustersServerTransport(&srv)}
+			a := &AKSScanner{}
+			err := a.Init(&scanners.ScannerConfig{
+				Ctx:            context.Background(),
+				Cred:           &azfake.TokenCredential{},
+				SubscriptionID: "sub",
+				ClientOptions: &arm.ClientOptions{
+					ClientOptions: azcore.ClientOptions{
+						Transport: transport,
+					},
+				},
+				Limit: tt.limit,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			clusters, err := a.listClusters("rg")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(clusters) != tt.want {
+				t.Errorf("listClusters() returned %d clusters, want %d", len(clusters), tt.want)
+			}
+			if transport.requests != tt.requests {
+				t.Errorf("listClusters() fetched %d pages, want %d", transport.requests, tt.requests)
+			}
+		})
+	}
+}
+
+func TestAKSScanner_LoadScanContext(t *testing.T) {
+	a := &AKSScanner{
+		listVnetIntegratedFunc: func() ([]string, error) {
+			return []string{"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/Private"}, nil
+		},
+		listInsightsFunc: func() ([]string, error) {
+			return []string{"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/Monitored"}, nil
+		},
+		listConstraintsFunc: func() (map[string]int, error) {
+			return map[string]int{"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/Policy": 2}, nil
+		},
+		listLockedFunc: func() ([]string, error) {
+			return []string{"RG-Nodes"}, nil
+		},
+	}
+
+	scanContext := &scanners.ScanContext{}
+	if err := a.LoadScanContext([]string{"rg"}, scanContext); err != nil {
+		t.Fatal(err)
+	}
+
+	want := &scanners.ScanContext{
+		VnetIntegratedClusters: map[string]bool{
+			"/subscriptions/sub/resourcegroups/rg/providers/microsoft.containerservice/managedclusters/private": true,
+		},
+		ContainerInsightsClusters: map[string]bool{
+			"/subscriptions/sub/resourcegroups/rg/providers/microsoft.containerservice/managedclusters/monitored": true,
+		},
+		PolicyConstraints: map[string]int{
+			"/subscriptions/sub/resourcegroups/rg/providers/microsoft.containerservice/managedclusters/policy": 2,
+		},
+		LockedResourceGroups: map[string]bool{
+			"rg-nodes": true,
+		},
+	}
+	if !reflect.DeepEqual(scanContext.VnetIntegratedClusters, want.VnetIntegratedClusters) {
+		t.Errorf("LoadScanContext() VnetIntegratedClusters = %v, want %v", scanContext.VnetIntegratedClusters, want.VnetIntegratedClusters)
+	}
+	if !reflect.DeepEqual(scanContext.ContainerInsightsClusters, want.ContainerInsightsClusters) {
+		t.Errorf("LoadScanContext() ContainerInsightsClusters = %v, want %v", scanContext.ContainerInsightsClusters, want.ContainerInsightsClusters)
+	}
+	if !reflect.DeepEqual(scanContext.PolicyConstraints, want.PolicyConstraints) {
+		t.Errorf("LoadScanContext() PolicyConstraints = %v, want %v", scanContext.PolicyConstraints, want.PolicyConstraints)
+	}
+	if !reflect.DeepEqual(scanContext.LockedResourceGroups, want.LockedResourceGroups) {
+		t.Errorf("LoadScanContext() LockedResourceGroups = %v, want %v", scanContext.LockedResourceGroups, want.LockedResourceGroups)
+	}
+}
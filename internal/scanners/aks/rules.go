@@ -4,10 +4,11 @@
 package aks
 
 import (
-	"log"
+	"fmt"
+	"strconv"
 	"strings"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v4"
 	"github.com/cmendible/azqr/internal/scanners"
 )
 
@@ -22,12 +23,7 @@ func (a *AKSScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Medium",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				service := target.(*armcontainerservice.ManagedCluster)
-				hasDiagnostics, err := a.diagnosticsSettings.HasDiagnostics(*service.ID)
-				if err != nil {
-					log.Fatalf("Error checking diagnostic settings for service %s: %s", *service.Name, err)
-				}
-
-				return !hasDiagnostics, ""
+				return a.diagnosticsSettings.EvalDiagnostics("aks-001", *service.ID, scanContext)
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/aks/monitor-aks#collect-resource-logs",
 		},
@@ -105,8 +101,19 @@ func (a *AKSScanner) GetRules() map[string]scanners.AzureRule {
 				if c.SKU != nil && c.SKU.Tier != nil {
 					sku = string(*c.SKU.Tier)
 				}
+				// Free tier clusters tagged as production are always called out, whatever their severity
+				if tag, ok := scanners.GetProductionTag(c.Tags); ok && sku == "Free" {
+					return true, fmt.Sprintf("Free tier in production (%s)", tag)
+				}
 				return sku == "Free", sku
 			},
+			AdjustSeverity: func(target interface{}, scanContext *scanners.ScanContext) string {
+				c := target.(*armcontainerservice.ManagedCluster)
+				if a.config != nil && a.config.ProductionSeverity && !scanners.IsProduction(c.Tags) {
+					return "Medium"
+				}
+				return ""
+			},
 			Url: "https://learn.microsoft.com/en-us/azure/aks/free-standard-pricing-tiers",
 		},
 		"CAF": {
@@ -117,7 +124,7 @@ func (a *AKSScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Low",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armcontainerservice.ManagedCluster)
-				caf := strings.HasPrefix(*c.Name, "aks")
+				caf := scanners.HasCAFPrefix(*c.Name, "aks", scanContext)
 				return !caf, ""
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
@@ -251,5 +258,400 @@ func (a *AKSScanner) GetRules() map[string]scanners.AzureRule {
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
+		"aks-031": {
+			Id:          "aks-031",
+			Category:    "Security",
+			Subcategory: "Identity and Access Control",
+			Description: "AKS with local accounts disabled should use Azure RBAC and AAD admin groups",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armcontainerservice.ManagedCluster)
+				if c.Properties == nil || c.Properties.DisableLocalAccounts == nil || !*c.Properties.DisableLocalAccounts {
+					return false, ""
+				}
+
+				missing := []string{}
+				aad := c.Properties.AADProfile
+				if aad == nil || aad.EnableAzureRBAC == nil || !*aad.EnableAzureRBAC {
+					missing = append(missing, "EnableAzureRBAC")
+				}
+				if aad == nil || len(aad.AdminGroupObjectIDs) == 0 {
+					missing = append(missing, "AdminGroupObjectIDs")
+				}
+				return len(missing) > 0, strings.Join(missing, ", ")
+			},
+			Url: "https://learn.microsoft.com/azure/aks/managed-aad",
+		},
+		"aks-032": {
+			Id:          "aks-032",
+			Category:    "High Availability and Resiliency",
+			Subcategory: "Reliability",
+			Description: "AKS should use Standard Load Balancer SKU",
+			Severity:    "High",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armcontainerservice.ManagedCluster)
+				if c.Properties == nil || c.Properties.NetworkProfile == nil || c.Properties.NetworkProfile.LoadBalancerSKU == nil {
+					return false, ""
+				}
+				sku := *c.Properties.NetworkProfile.LoadBalancerSKU
+				return sku == armcontainerservice.LoadBalancerSKUBasic, string(sku)
+			},
+			Url: "https://learn.microsoft.com/azure/aks/load-balancer-standard",
+		},
+		"aks-033": {
+			Id:          "aks-033",
+			Category:    "Security",
+			Subcategory: "Identity and Access Control",
+			Description: "AKS should have the OIDC issuer and workload identity enabled",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armcontainerservice.ManagedCluster)
+				if c.Properties == nil {
+					return true, "OIDC Issuer, Workload Identity"
+				}
+
+				missing := []string{}
+				oidc := c.Properties.OidcIssuerProfile
+				if oidc == nil || oidc.Enabled == nil || !*oidc.Enabled {
+					missing = append(missing, "OIDC Issuer")
+				}
+				security := c.Properties.SecurityProfile
+				if security == nil || security.WorkloadIdentity == nil || security.WorkloadIdentity.Enabled == nil || !*security.WorkloadIdentity.Enabled {
+					missing = append(missing, "Workload Identity")
+				}
+				return len(missing) > 0, strings.Join(missing, ", ")
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/aks/workload-identity-overview",
+		},
+		"aks-034": {
+			Id:          "aks-034",
+			Category:    "Networking",
+			Subcategory: "Best Practices",
+			Description: "AKS network plugin mode should be Azure CNI Overlay",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armcontainerservice.ManagedCluster)
+				if c.Properties == nil || c.Properties.NetworkProfile == nil {
+					return false, ""
+				}
+				// Without a mode the plugin runs its default, non overlay, networking: reported, not broken.
+				// The kubenet plugin itself is flagged by aks-013.
+				mode := c.Properties.NetworkProfile.NetworkPluginMode
+				if mode == nil || *mode == "" {
+					return false, "None"
+				}
+				return *mode != armcontainerservice.NetworkPluginModeOverlay, string(*mode)
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/aks/azure-cni-overlay",
+		},
+		"aks-035": {
+			Id:          "aks-035",
+			Category:    "High Availability and Resiliency",
+			Subcategory: "Availability Zones",
+			Description: "AKS Cluster with an SLA should spread every user node pool across availability zones",
+			Severity:    "High",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armcontainerservice.ManagedCluster)
+				if c.Properties == nil || c.SKU == nil || c.SKU.Tier == nil || *c.SKU.Tier == armcontainerservice.ManagedClusterSKUTierFree {
+					return false, ""
+				}
+
+				broken := false
+				pools := []string{}
+				for _, profile := range c.Properties.AgentPoolProfiles {
+					if profile == nil {
+						continue
+					}
+					if profile.Mode == nil || *profile.Mode != armcontainerservice.AgentPoolModeUser {
+						continue
+					}
+					zones := len(profile.AvailabilityZones)
+					if zones <= 1 {
+						broken = true
+					}
+					name := ""
+					if profile.Name != nil {
+						name = *profile.Name
+					}
+					pools = append(pools, fmt.Sprintf("%s: %d", name, zones))
+				}
+				return broken, strings.Join(pools, ", ")
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/aks/availability-zones",
+		},
+		"aks-036": {
+			Id:          "aks-036",
+			Category:    "Governance",
+			Subcategory: "Cost Optimization",
+			Description: "AKS Cluster node pool priorities (Spot node pools can reduce costs)",
+			Severity:    "Low",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armcontainerservice.ManagedCluster)
+				if c.Properties == nil {
+					return false, ""
+				}
+
+				pools := []string{}
+				for _, profile := range c.Properties.AgentPoolProfiles {
+					if profile == nil {
+						continue
+					}
+					priority := armcontainerservice.ScaleSetPriorityRegular
+					if profile.ScaleSetPriority != nil {
+						priority = *profile.ScaleSetPriority
+					}
+					name := ""
+					if profile.Name != nil {
+						name = *profile.Name
+					}
+					pools = append(pools, fmt.Sprintf("%s: %s", name, priority))
+				}
+				return false, strings.Join(pools, ", ")
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/aks/spot-node-pool",
+		},
+		"aks-037": {
+			Id:          "aks-037",
+			Category:    "High Availability and Resiliency",
+			Subcategory: "Reliability",
+			Description: "AKS System node pools should have the CriticalAddonsOnly taint",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armcontainerservice.ManagedCluster)
+				if c.Properties == nil {
+					return false, ""
+				}
+
+				untainted := []string{}
+				for _, profile := range c.Properties.AgentPoolProfiles {
+					if profile == nil {
+						continue
+					}
+					if profile.Mode == nil || *profile.Mode != armcontainerservice.AgentPoolModeSystem {
+						continue
+					}
+					tainted := false
+					for _, taint := range profile.NodeTaints {
+						if taint != nil && strings.HasPrefix(*taint, "CriticalAddonsOnly") {
+							tainted = true
+							break
+						}
+					}
+					if !tainted && profile.Name != nil {
+						untainted = append(untainted, *profile.Name)
+					}
+				}
+				return len(untainted) > 0, strings.Join(untainted, ", ")
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/aks/use-system-pools",
+		},
+		"aks-038": {
+			Id:          "aks-038",
+			Category:    "Security",
+			Subcategory: "Networking",
+			Description: "AKS should use API Server VNet Integration",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armcontainerservice.ManagedCluster)
+				enabled := scanContext.VnetIntegratedClusters[strings.ToLower(*c.ID)]
+				return !enabled, strconv.FormatBool(enabled)
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/aks/api-server-vnet-integration",
+		},
+		"aks-039": {
+			Id:          "aks-039",
+			Category:    "Governance",
+			Subcategory: "Naming Convention (CAF)",
+			Description: "AKS node resource group should comply with naming conventions and have a delete lock",
+			Severity:    "Low",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armcontainerservice.ManagedCluster)
+				if c.Properties == nil || c.Properties.NodeResourceGroup == nil {
+					return false, ""
+				}
+				nodeResourceGroup := *c.Properties.NodeResourceGroup
+				// The default node resource group, MC_<resource group>_<cluster>_<region>, is generated by Azure and can't follow the CAF prefix
+				name := strings.ToLower(nodeResourceGroup)
+				caf := strings.HasPrefix(name, "mc_") || scanners.HasCAFPrefix(name, "rg", scanContext)
+				locked := scanContext.LockedResourceGroups[name]
+				return !caf || !locked, nodeResourceGroup
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/aks/faq#can-i-provide-my-own-name-for-the-aks-node-resource-group",
+		},
+		"aks-040": {
+			Id:          "aks-040",
+			Category:    "Networking",
+			Subcategory: "Best Practices",
+			Description: "AKS should use the application routing add-on instead of the deprecated HTTP application routing add-on",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armcontainerservice.ManagedCluster)
+				if c.Properties == nil {
+					return false, ""
+				}
+
+				http := false
+				if p, exists := c.Properties.AddonProfiles["httpApplicationRouting"]; exists && p != nil && p.Enabled != nil {
+					http = *p.Enabled
+				}
+				webApp := c.Properties.IngressProfile != nil && c.Properties.IngressProfile.WebAppRouting != nil &&
+					c.Properties.IngressProfile.WebAppRouting.Enabled != nil && *c.Properties.IngressProfile.WebAppRouting.Enabled
+
+				ingress := []string{}
+				if http {
+					ingress = append(ingress, "httpApplicationRouting")
+				}
+				if webApp {
+					ingress = append(ingress, "webApplicationRouting")
+				}
+				if len(ingress) == 0 {
+					return false, "None"
+				}
+				return http, strings.Join(ingress, ", ")
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/aks/app-routing-migration",
+		},
+		"aks-041": {
+			Id:          "aks-041",
+			Category:    "Operations",
+			Subcategory: "Best Practices",
+			Description: "AKS node pools should not use a deprecated OS SKU",
+			Severity:    "Low",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armcontainerservice.ManagedCluster)
+				if c.Properties == nil {
+					return false, ""
+				}
+
+				deprecated := false
+				pools := []string{}
+				for _, profile := range c.Properties.AgentPoolProfiles {
+					if profile == nil || profile.Name == nil {
+						continue
+					}
+					osSKU := "Default"
+					if profile.OSSKU != nil {
+						osSKU = string(*profile.OSSKU)
+						switch *profile.OSSKU {
+						case armcontainerservice.OSSKUCBLMariner, armcontainerservice.OSSKUWindows2019:
+							deprecated = true
+						}
+					}
+					pools = append(pools, fmt.Sprintf("%s: %s", *profile.Name, osSKU))
+				}
+				return deprecated, strings.Join(pools, ", ")
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/azure-linux/intro-azure-linux",
+		},
+		"aks-042": {
+			Id:          "aks-042",
+			Category:    "Monitoring and Logging",
+			Subcategory: "Monitoring",
+			Description: "AKS should have Azure Monitor managed Prometheus enabled",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armcontainerservice.ManagedCluster)
+				enabled := c.Properties != nil && c.Properties.AzureMonitorProfile != nil && c.Properties.AzureMonitorProfile.Metrics != nil &&
+					c.Properties.AzureMonitorProfile.Metrics.Enabled != nil && *c.Properties.AzureMonitorProfile.Metrics.Enabled
+				if enabled {
+					return false, "Enabled"
+				}
+				return true, "Disabled"
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/azure-monitor/containers/kubernetes-monitoring-enable",
+		},
+		"aks-043": {
+			Id:          "aks-043",
+			Category:    "Monitoring and Logging",
+			Subcategory: "Monitoring",
+			Description: "AKS should have Container Insights enabled in its Azure Monitor profile instead of the legacy omsagent add-on",
+			Severity:    "Low",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armcontainerservice.ManagedCluster)
+				if scanContext.ContainerInsightsClusters[strings.ToLower(*c.ID)] {
+					return false, "Azure Monitor profile"
+				}
+
+				legacy := false
+				if c.Properties != nil {
+					if p, exists := c.Properties.AddonProfiles["omsagent"]; exists && p != nil && p.Enabled != nil {
+						legacy = *p.Enabled
+					}
+				}
+				if legacy {
+					return true, "omsagent add-on"
+				}
+				return true, "None"
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/azure-monitor/containers/kubernetes-monitoring-enable",
+		},
+		"aks-044": {
+			Id:          "aks-044",
+			Category:    "Security",
+			Subcategory: "Best Practices",
+			Description: "AKS should have the Azure Policy add-on enabled with Gatekeeper constraints assigned",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armcontainerservice.ManagedCluster)
+				enabled := false
+				if c.Properties != nil {
+					if p, exists := c.Properties.AddonProfiles["azurepolicy"]; exists && p != nil && p.Enabled != nil {
+						enabled = *p.Enabled
+					}
+				}
+				if !enabled {
+					return true, "Azure Policy add-on disabled"
+				}
+
+				constraints := scanContext.PolicyConstraints[strings.ToLower(*c.ID)]
+				return constraints == 0, fmt.Sprintf("%d constraints", constraints)
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/governance/policy/concepts/policy-for-kubernetes",
+		},
+		"aks-045": {
+			Id:          "aks-045",
+			Category:    "Governance",
+			Subcategory: "Cost Optimization",
+			Description: "AKS Cluster node pool scale-down modes (Deallocate speeds up scale-up but keeps paying for the disks)",
+			Severity:    "Low",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armcontainerservice.ManagedCluster)
+				if c.Properties == nil {
+					return false, ""
+				}
+
+				pools := []string{}
+				for _, profile := range c.Properties.AgentPoolProfiles {
+					if profile == nil || profile.Name == nil {
+						continue
+					}
+					// Scale-down mode defaults to Delete
+					mode := armcontainerservice.ScaleDownModeDelete
+					if profile.ScaleDownMode != nil {
+						mode = *profile.ScaleDownMode
+					}
+					pools = append(pools, fmt.Sprintf("%s: %s", *profile.Name, mode))
+				}
+				return false, strings.Join(pools, ", ")
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/aks/scale-down-mode",
+		},
+		"aks-046": {
+			Id:          "aks-046",
+			Category:    "Security",
+			Subcategory: "Identity and Access Control",
+			Description: "AKS should have the Key Vault secrets provider add-on enabled",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armcontainerservice.ManagedCluster)
+				if c.Properties == nil {
+					return true, ""
+				}
+				p, exists := c.Properties.AddonProfiles["azureKeyvaultSecretsProvider"]
+				broken := !exists || p == nil || p.Enabled == nil || !*p.Enabled
+				return broken, ""
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/aks/csi-secrets-store-driver",
+		},
 	}
 }
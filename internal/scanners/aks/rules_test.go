@@ -7,7 +7,7 @@ import (
 	"reflect"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v4"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/cmendible/azqr/internal/scanners"
 )
@@ -53,7 +53,7 @@ func TestAKSScanner_Rules(t *testing.T) {
 				rule: "AvailabilityZones",
 				target: &armcontainerservice.ManagedCluster{
 					SKU: &armcontainerservice.ManagedClusterSKU{
-						Tier: getSKUTierPaid(),
+						Tier: getSKUTierStandard(),
 					},
 					Properties: &armcontainerservice.ManagedClusterProperties{
 						AgentPoolProfiles: []*armcontainerservice.ManagedClusterAgentPoolProfile{
@@ -77,7 +77,7 @@ func TestAKSScanner_Rules(t *testing.T) {
 				rule: "Private",
 				target: &armcontainerservice.ManagedCluster{
 					SKU: &armcontainerservice.ManagedClusterSKU{
-						Tier: getSKUTierPaid(),
+						Tier: getSKUTierStandard(),
 					},
 					Properties: &armcontainerservice.ManagedClusterProperties{
 						APIServerAccessProfile: &armcontainerservice.ManagedClusterAPIServerAccessProfile{
@@ -118,12 +118,12 @@ func TestAKSScanner_Rules(t *testing.T) {
 			},
 		},
 		{
-			name: "AKSScanner SLA Paid",
+			name: "AKSScanner SLA Standard",
 			fields: fields{
 				rule: "SLA",
 				target: &armcontainerservice.ManagedCluster{
 					SKU: &armcontainerservice.ManagedClusterSKU{
-						Tier: getSKUTierPaid(),
+						Tier: getSKUTierStandard(),
 					},
 					Properties: &armcontainerservice.ManagedClusterProperties{
 						AgentPoolProfiles: []*armcontainerservice.ManagedClusterAgentPoolProfile{
@@ -142,12 +142,12 @@ func TestAKSScanner_Rules(t *testing.T) {
 			},
 		},
 		{
-			name: "AKSScanner SLA Paid with AZ",
+			name: "AKSScanner SLA Standard with AZ",
 			fields: fields{
 				rule: "SLA",
 				target: &armcontainerservice.ManagedCluster{
 					SKU: &armcontainerservice.ManagedClusterSKU{
-						Tier: getSKUTierPaid(),
+						Tier: getSKUTierStandard(),
 					},
 					Properties: &armcontainerservice.ManagedClusterProperties{
 						AgentPoolProfiles: []*armcontainerservice.ManagedClusterAgentPoolProfile{
@@ -550,6 +550,1175 @@ func TestAKSScanner_Rules(t *testing.T) {
 				result: "",
 			},
 		},
+		{
+			name: "AKSScanner local accounts disabled with Azure RBAC and admin groups",
+			fields: fields{
+				rule: "aks-031",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						DisableLocalAccounts: to.BoolPtr(true),
+						AADProfile: &armcontainerservice.ManagedClusterAADProfile{
+							EnableAzureRBAC:     to.BoolPtr(true),
+							AdminGroupObjectIDs: []*string{to.StringPtr("group")},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "AKSScanner local accounts disabled without Azure RBAC",
+			fields: fields{
+				rule: "aks-031",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						DisableLocalAccounts: to.BoolPtr(true),
+						AADProfile: &armcontainerservice.ManagedClusterAADProfile{
+							EnableAzureRBAC:     to.BoolPtr(false),
+							AdminGroupObjectIDs: []*string{to.StringPtr("group")},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "EnableAzureRBAC",
+			},
+		},
+		{
+			name: "AKSScanner local accounts disabled without admin groups",
+			fields: fields{
+				rule: "aks-031",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						DisableLocalAccounts: to.BoolPtr(true),
+						AADProfile: &armcontainerservice.ManagedClusterAADProfile{
+							EnableAzureRBAC: to.BoolPtr(true),
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "AdminGroupObjectIDs",
+			},
+		},
+		{
+			name: "AKSScanner local accounts disabled without AAD profile",
+			fields: fields{
+				rule: "aks-031",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						DisableLocalAccounts: to.BoolPtr(true),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "EnableAzureRBAC, AdminGroupObjectIDs",
+			},
+		},
+		{
+			name: "AKSScanner local accounts enabled",
+			fields: fields{
+				rule: "aks-031",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						DisableLocalAccounts: to.BoolPtr(false),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "AKSScanner Standard Load Balancer",
+			fields: fields{
+				rule: "aks-032",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						NetworkProfile: &armcontainerservice.NetworkProfile{
+							LoadBalancerSKU: getLoadBalancerSKU(armcontainerservice.LoadBalancerSKUStandard),
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "standard",
+			},
+		},
+		{
+			name: "AKSScanner Basic Load Balancer",
+			fields: fields{
+				rule: "aks-032",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						NetworkProfile: &armcontainerservice.NetworkProfile{
+							LoadBalancerSKU: getLoadBalancerSKU(armcontainerservice.LoadBalancerSKUBasic),
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "basic",
+			},
+		},
+		{
+			name: "AKSScanner without Load Balancer SKU",
+			fields: fields{
+				rule: "aks-032",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "AKSScanner OIDC issuer and workload identity enabled",
+			fields: fields{
+				rule: "aks-033",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						OidcIssuerProfile: &armcontainerservice.ManagedClusterOIDCIssuerProfile{
+							Enabled: to.BoolPtr(true),
+						},
+						SecurityProfile: &armcontainerservice.ManagedClusterSecurityProfile{
+							WorkloadIdentity: &armcontainerservice.ManagedClusterSecurityProfileWorkloadIdentity{
+								Enabled: to.BoolPtr(true),
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "AKSScanner without OIDC issuer and workload identity",
+			fields: fields{
+				rule: "aks-033",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "OIDC Issuer, Workload Identity",
+			},
+		},
+		{
+			name: "AKSScanner network plugin mode overlay",
+			fields: fields{
+				rule: "aks-034",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						NetworkProfile: &armcontainerservice.NetworkProfile{
+							NetworkPlugin:     getNetworkPlugin(armcontainerservice.NetworkPluginAzure),
+							NetworkPluginMode: getNetworkPluginMode(armcontainerservice.NetworkPluginModeOverlay),
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "overlay",
+			},
+		},
+		{
+			name: "AKSScanner network plugin mode not set",
+			fields: fields{
+				rule: "aks-034",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						NetworkProfile: &armcontainerservice.NetworkProfile{
+							NetworkPlugin: getNetworkPlugin(armcontainerservice.NetworkPluginAzure),
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "None",
+			},
+		},
+		{
+			name: "AKSScanner kubenet network plugin left to aks-013",
+			fields: fields{
+				rule: "aks-034",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						NetworkProfile: &armcontainerservice.NetworkProfile{
+							NetworkPlugin: getNetworkPluginKubenet(),
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "None",
+			},
+		},
+		{
+			name: "AKSScanner network plugin mode not overlay",
+			fields: fields{
+				rule: "aks-034",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						NetworkProfile: &armcontainerservice.NetworkProfile{
+							NetworkPlugin:     getNetworkPlugin(armcontainerservice.NetworkPluginAzure),
+							NetworkPluginMode: getNetworkPluginMode(armcontainerservice.NetworkPluginMode("legacy")),
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "legacy",
+			},
+		},
+		{
+			name: "AKSScanner single zone user node pool",
+			fields: fields{
+				rule: "aks-035",
+				target: &armcontainerservice.ManagedCluster{
+					SKU: &armcontainerservice.ManagedClusterSKU{
+						Tier: getSKUTierStandard(),
+					},
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						AgentPoolProfiles: []*armcontainerservice.ManagedClusterAgentPoolProfile{
+							{
+								Name:              to.StringPtr("system"),
+								Mode:              getAgentPoolMode(armcontainerservice.AgentPoolModeSystem),
+								AvailabilityZones: []*string{to.StringPtr("1"), to.StringPtr("2"), to.StringPtr("3")},
+							},
+							{
+								Name:              to.StringPtr("apps"),
+								Mode:              getAgentPoolMode(armcontainerservice.AgentPoolModeUser),
+								AvailabilityZones: []*string{to.StringPtr("1")},
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "apps: 1",
+			},
+		},
+		{
+			name: "AKSScanner multi zone user node pool",
+			fields: fields{
+				rule: "aks-035",
+				target: &armcontainerservice.ManagedCluster{
+					SKU: &armcontainerservice.ManagedClusterSKU{
+						Tier: getSKUTierStandard(),
+					},
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						AgentPoolProfiles: []*armcontainerservice.ManagedClusterAgentPoolProfile{
+							{
+								Name:              to.StringPtr("apps"),
+								Mode:              getAgentPoolMode(armcontainerservice.AgentPoolModeUser),
+								AvailabilityZones: []*string{to.StringPtr("1"), to.StringPtr("2"), to.StringPtr("3")},
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "apps: 3",
+			},
+		},
+		{
+			name: "AKSScanner spot node pool",
+			fields: fields{
+				rule: "aks-036",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						AgentPoolProfiles: []*armcontainerservice.ManagedClusterAgentPoolProfile{
+							{
+								Name: to.StringPtr("system"),
+							},
+							{
+								Name:             to.StringPtr("spot"),
+								ScaleSetPriority: getScaleSetPriority(armcontainerservice.ScaleSetPrioritySpot),
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "system: Regular, spot: Spot",
+			},
+		},
+		{
+			name: "AKSScanner regular node pools",
+			fields: fields{
+				rule: "aks-036",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						AgentPoolProfiles: []*armcontainerservice.ManagedClusterAgentPoolProfile{
+							{
+								Name:             to.StringPtr("system"),
+								ScaleSetPriority: getScaleSetPriority(armcontainerservice.ScaleSetPriorityRegular),
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "system: Regular",
+			},
+		},
+		{
+			name: "AKSScanner tainted system node pool",
+			fields: fields{
+				rule: "aks-037",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						AgentPoolProfiles: []*armcontainerservice.ManagedClusterAgentPoolProfile{
+							{
+								Name:       to.StringPtr("system"),
+								Mode:       getAgentPoolMode(armcontainerservice.AgentPoolModeSystem),
+								NodeTaints: []*string{to.StringPtr("CriticalAddonsOnly=true:NoSchedule")},
+							},
+							{
+								Name: to.StringPtr("apps"),
+								Mode: getAgentPoolMode(armcontainerservice.AgentPoolModeUser),
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "AKSScanner untainted system node pool",
+			fields: fields{
+				rule: "aks-037",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						AgentPoolProfiles: []*armcontainerservice.ManagedClusterAgentPoolProfile{
+							{
+								Name: to.StringPtr("system"),
+								Mode: getAgentPoolMode(armcontainerservice.AgentPoolModeSystem),
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "system",
+			},
+		},
+		{
+			name: "AKSScanner API Server VNet Integration enabled",
+			fields: fields{
+				rule: "aks-038",
+				target: &armcontainerservice.ManagedCluster{
+					ID:         to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/aks"),
+					Properties: &armcontainerservice.ManagedClusterProperties{},
+				},
+				scanContext: &scanners.ScanContext{
+					VnetIntegratedClusters: map[string]bool{
+						"/subscriptions/sub/resourcegroups/rg/providers/microsoft.containerservice/managedclusters/aks": true,
+					},
+				},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "true",
+			},
+		},
+		{
+			name: "AKSScanner API Server VNet Integration disabled",
+			fields: fields{
+				rule: "aks-038",
+				target: &armcontainerservice.ManagedCluster{
+					ID:         to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/aks"),
+					Properties: &armcontainerservice.ManagedClusterProperties{},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "false",
+			},
+		},
+		{
+			name: "AKSScanner node resource group compliant and locked",
+			fields: fields{
+				rule: "aks-039",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						NodeResourceGroup: to.StringPtr("rg-aks-nodes"),
+					},
+				},
+				scanContext: &scanners.ScanContext{
+					LockedResourceGroups: map[string]bool{
+						"rg-aks-nodes": true,
+					},
+				},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "rg-aks-nodes",
+			},
+		},
+		{
+			name: "AKSScanner node resource group with default name",
+			fields: fields{
+				rule: "aks-039",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						NodeResourceGroup: to.StringPtr("MC_rg_aks_westeurope"),
+					},
+				},
+				scanContext: &scanners.ScanContext{
+					LockedResourceGroups: map[string]bool{
+						"mc_rg_aks_westeurope": true,
+					},
+				},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "MC_rg_aks_westeurope",
+			},
+		},
+		{
+			name: "AKSScanner node resource group with default name without lock",
+			fields: fields{
+				rule: "aks-039",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						NodeResourceGroup: to.StringPtr("MC_rg_aks_westeurope"),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "MC_rg_aks_westeurope",
+			},
+		},
+		{
+			name: "AKSScanner node resource group with non compliant name",
+			fields: fields{
+				rule: "aks-039",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						NodeResourceGroup: to.StringPtr("aks-nodes"),
+					},
+				},
+				scanContext: &scanners.ScanContext{
+					LockedResourceGroups: map[string]bool{
+						"aks-nodes": true,
+					},
+				},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "aks-nodes",
+			},
+		},
+		{
+			name: "AKSScanner node resource group without lock",
+			fields: fields{
+				rule: "aks-039",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						NodeResourceGroup: to.StringPtr("rg-aks-nodes"),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "rg-aks-nodes",
+			},
+		},
+		{
+			name: "AKSScanner CAF with custom prefix",
+			fields: fields{
+				rule: "CAF",
+				target: &armcontainerservice.ManagedCluster{
+					Name: to.StringPtr("k8s-test"),
+				},
+				scanContext: &scanners.ScanContext{
+					CAFPrefixes: map[string][]string{
+						"aks": {"k8s"},
+					},
+				},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "AKSScanner CAF with custom prefix and default name",
+			fields: fields{
+				rule: "CAF",
+				target: &armcontainerservice.ManagedCluster{
+					Name: to.StringPtr("aks-test"),
+				},
+				scanContext: &scanners.ScanContext{
+					CAFPrefixes: map[string][]string{
+						"aks": {"k8s"},
+					},
+				},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "",
+			},
+		},
+		{
+			name: "AKSScanner httpApplicationRouting and webApplicationRouting",
+			fields: fields{
+				rule: "aks-040",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						AddonProfiles: map[string]*armcontainerservice.ManagedClusterAddonProfile{
+							"httpApplicationRouting": {
+								Enabled: to.BoolPtr(true),
+							},
+						},
+						IngressProfile: &armcontainerservice.ManagedClusterIngressProfile{
+							WebAppRouting: &armcontainerservice.ManagedClusterIngressProfileWebAppRouting{
+								Enabled: to.BoolPtr(true),
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "httpApplicationRouting, webApplicationRouting",
+			},
+		},
+		{
+			name: "AKSScanner webApplicationRouting only",
+			fields: fields{
+				rule: "aks-040",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						IngressProfile: &armcontainerservice.ManagedClusterIngressProfile{
+							WebAppRouting: &armcontainerservice.ManagedClusterIngressProfileWebAppRouting{
+								Enabled: to.BoolPtr(true),
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "webApplicationRouting",
+			},
+		},
+		{
+			name: "AKSScanner httpApplicationRouting only",
+			fields: fields{
+				rule: "aks-040",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						AddonProfiles: map[string]*armcontainerservice.ManagedClusterAddonProfile{
+							"httpApplicationRouting": {
+								Enabled: to.BoolPtr(true),
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "httpApplicationRouting",
+			},
+		},
+		{
+			name: "AKSScanner without ingress add-ons",
+			fields: fields{
+				rule: "aks-040",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "None",
+			},
+		},
+		{
+			name: "AKSScanner AzureLinux and Ubuntu node pools",
+			fields: fields{
+				rule: "aks-041",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						AgentPoolProfiles: []*armcontainerservice.ManagedClusterAgentPoolProfile{
+							{
+								Name:  to.StringPtr("system"),
+								OSSKU: getOSSKU(armcontainerservice.OSSKUAzureLinux),
+							},
+							{
+								Name:  to.StringPtr("user"),
+								OSSKU: getOSSKU(armcontainerservice.OSSKUUbuntu),
+							},
+							{
+								Name: to.StringPtr("default"),
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "system: AzureLinux, user: Ubuntu, default: Default",
+			},
+		},
+		{
+			name: "AKSScanner deprecated CBLMariner node pool",
+			fields: fields{
+				rule: "aks-041",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						AgentPoolProfiles: []*armcontainerservice.ManagedClusterAgentPoolProfile{
+							{
+								Name:  to.StringPtr("system"),
+								OSSKU: getOSSKU(armcontainerservice.OSSKUCBLMariner),
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "system: CBLMariner",
+			},
+		},
+		{
+			name: "AKSScanner OS SKU without properties",
+			fields: fields{
+				rule:                "aks-041",
+				target:              &armcontainerservice.ManagedCluster{},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "AKSScanner managed Prometheus enabled",
+			fields: fields{
+				rule: "aks-042",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						AzureMonitorProfile: &armcontainerservice.ManagedClusterAzureMonitorProfile{
+							Metrics: &armcontainerservice.ManagedClusterAzureMonitorProfileMetrics{
+								Enabled: to.BoolPtr(true),
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "Enabled",
+			},
+		},
+		{
+			name: "AKSScanner managed Prometheus disabled",
+			fields: fields{
+				rule: "aks-042",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						AzureMonitorProfile: &armcontainerservice.ManagedClusterAzureMonitorProfile{
+							Metrics: &armcontainerservice.ManagedClusterAzureMonitorProfileMetrics{
+								Enabled: to.BoolPtr(false),
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "Disabled",
+			},
+		},
+		{
+			name: "AKSScanner managed Prometheus without Azure Monitor profile",
+			fields: fields{
+				rule: "aks-042",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "Disabled",
+			},
+		},
+		{
+			name: "AKSScanner Container Insights in Azure Monitor profile",
+			fields: fields{
+				rule: "aks-043",
+				target: &armcontainerservice.ManagedCluster{
+					ID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/aks"),
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						AddonProfiles: map[string]*armcontainerservice.ManagedClusterAddonProfile{
+							"omsagent": {
+								Enabled: to.BoolPtr(true),
+							},
+						},
+					},
+				},
+				scanContext: &scanners.ScanContext{
+					ContainerInsightsClusters: map[string]bool{
+						"/subscriptions/sub/resourcegroups/rg/providers/microsoft.containerservice/managedclusters/aks": true,
+					},
+				},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "Azure Monitor profile",
+			},
+		},
+		{
+			name: "AKSScanner Container Insights with legacy omsagent add-on only",
+			fields: fields{
+				rule: "aks-043",
+				target: &armcontainerservice.ManagedCluster{
+					ID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/aks"),
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						AddonProfiles: map[string]*armcontainerservice.ManagedClusterAddonProfile{
+							"omsagent": {
+								Enabled: to.BoolPtr(true),
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "omsagent add-on",
+			},
+		},
+		{
+			name: "AKSScanner without Container Insights",
+			fields: fields{
+				rule: "aks-043",
+				target: &armcontainerservice.ManagedCluster{
+					ID:         to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/aks"),
+					Properties: &armcontainerservice.ManagedClusterProperties{},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "None",
+			},
+		},
+		{
+			name: "AKSScanner Azure Policy add-on with constraints",
+			fields: fields{
+				rule: "aks-044",
+				target: &armcontainerservice.ManagedCluster{
+					ID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/aks"),
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						AddonProfiles: map[string]*armcontainerservice.ManagedClusterAddonProfile{
+							"azurepolicy": {
+								Enabled: to.BoolPtr(true),
+							},
+						},
+					},
+				},
+				scanContext: &scanners.ScanContext{
+					PolicyConstraints: map[string]int{
+						"/subscriptions/sub/resourcegroups/rg/providers/microsoft.containerservice/managedclusters/aks": 3,
+					},
+				},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "3 constraints",
+			},
+		},
+		{
+			name: "AKSScanner Azure Policy add-on without constraints",
+			fields: fields{
+				rule: "aks-044",
+				target: &armcontainerservice.ManagedCluster{
+					ID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/aks"),
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						AddonProfiles: map[string]*armcontainerservice.ManagedClusterAddonProfile{
+							"azurepolicy": {
+								Enabled: to.BoolPtr(true),
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "0 constraints",
+			},
+		},
+		{
+			name: "AKSScanner Azure Policy add-on disabled",
+			fields: fields{
+				rule: "aks-044",
+				target: &armcontainerservice.ManagedCluster{
+					ID:         to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerService/managedClusters/aks"),
+					Properties: &armcontainerservice.ManagedClusterProperties{},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "Azure Policy add-on disabled",
+			},
+		},
+		{
+			name: "AKSScanner node pool scale-down modes",
+			fields: fields{
+				rule: "aks-045",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						AgentPoolProfiles: []*armcontainerservice.ManagedClusterAgentPoolProfile{
+							{
+								Name:          to.StringPtr("system"),
+								ScaleDownMode: getScaleDownMode(armcontainerservice.ScaleDownModeDelete),
+							},
+							{
+								Name:          to.StringPtr("user"),
+								ScaleDownMode: getScaleDownMode(armcontainerservice.ScaleDownModeDeallocate),
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "system: Delete, user: Deallocate",
+			},
+		},
+		{
+			name: "AKSScanner node pool default scale-down mode",
+			fields: fields{
+				rule: "aks-045",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						AgentPoolProfiles: []*armcontainerservice.ManagedClusterAgentPoolProfile{
+							{
+								Name: to.StringPtr("system"),
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "system: Delete",
+			},
+		},
+		{
+			name: "AKSScanner Key Vault secrets provider enabled",
+			fields: fields{
+				rule: "aks-046",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						AddonProfiles: map[string]*armcontainerservice.ManagedClusterAddonProfile{
+							"azureKeyvaultSecretsProvider": {
+								Enabled: to.BoolPtr(true),
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "AKSScanner Key Vault secrets provider disabled",
+			fields: fields{
+				rule: "aks-046",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						AddonProfiles: map[string]*armcontainerservice.ManagedClusterAddonProfile{
+							"azureKeyvaultSecretsProvider": {
+								Enabled: to.BoolPtr(false),
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "",
+			},
+		},
+		{
+			name: "AKSScanner Key Vault secrets provider not present",
+			fields: fields{
+				rule: "aks-046",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						AddonProfiles: map[string]*armcontainerservice.ManagedClusterAddonProfile{},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "",
+			},
+		},
+		{
+			name: "AKSScanner OIDC issuer without properties",
+			fields: fields{
+				rule:                "aks-033",
+				target:              &armcontainerservice.ManagedCluster{},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "OIDC Issuer, Workload Identity",
+			},
+		},
+		{
+			name: "AKSScanner network plugin without properties",
+			fields: fields{
+				rule:                "aks-034",
+				target:              &armcontainerservice.ManagedCluster{},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "AKSScanner user node pool zones without properties",
+			fields: fields{
+				rule: "aks-035",
+				target: &armcontainerservice.ManagedCluster{
+					SKU: &armcontainerservice.ManagedClusterSKU{
+						Tier: getSKUTierStandard(),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "AKSScanner node pool priorities without properties",
+			fields: fields{
+				rule:                "aks-036",
+				target:              &armcontainerservice.ManagedCluster{},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "AKSScanner system node pool taints without properties",
+			fields: fields{
+				rule:                "aks-037",
+				target:              &armcontainerservice.ManagedCluster{},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "AKSScanner user node pool zones with a nil node pool",
+			fields: fields{
+				rule: "aks-035",
+				target: &armcontainerservice.ManagedCluster{
+					SKU: &armcontainerservice.ManagedClusterSKU{
+						Tier: getSKUTierStandard(),
+					},
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						AgentPoolProfiles: []*armcontainerservice.ManagedClusterAgentPoolProfile{
+							nil,
+							{
+								Name:              to.StringPtr("apps"),
+								Mode:              getAgentPoolMode(armcontainerservice.AgentPoolModeUser),
+								AvailabilityZones: []*string{to.StringPtr("1")},
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "apps: 1",
+			},
+		},
+		{
+			name: "AKSScanner node pool priorities with a nil node pool",
+			fields: fields{
+				rule: "aks-036",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						AgentPoolProfiles: []*armcontainerservice.ManagedClusterAgentPoolProfile{
+							nil,
+							{
+								Name: to.StringPtr("system"),
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "system: Regular",
+			},
+		},
+		{
+			name: "AKSScanner system node pool taints with a nil node pool",
+			fields: fields{
+				rule: "aks-037",
+				target: &armcontainerservice.ManagedCluster{
+					Properties: &armcontainerservice.ManagedClusterProperties{
+						AgentPoolProfiles: []*armcontainerservice.ManagedClusterAgentPoolProfile{
+							nil,
+							{
+								Name: to.StringPtr("system"),
+								Mode: getAgentPoolMode(armcontainerservice.AgentPoolModeSystem),
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "system",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -569,6 +1738,116 @@ func TestAKSScanner_Rules(t *testing.T) {
 	}
 }
 
+func TestAKSScanner_SKUSeverity(t *testing.T) {
+	type want struct {
+		broken   bool
+		result   string
+		severity string
+	}
+	tests := []struct {
+		name               string
+		productionSeverity bool
+		target             *armcontainerservice.ManagedCluster
+		want               want
+	}{
+		{
+			name: "AKSScanner SKU Free in production",
+			target: &armcontainerservice.ManagedCluster{
+				SKU: &armcontainerservice.ManagedClusterSKU{
+					Tier: getSKUTierFree(),
+				},
+				Tags: map[string]*string{
+					"env": to.StringPtr("prod"),
+				},
+			},
+			want: want{
+				broken:   true,
+				result:   "Free tier in production (env=prod)",
+				severity: "High",
+			},
+		},
+		{
+			name: "AKSScanner SKU Free in dev",
+			target: &armcontainerservice.ManagedCluster{
+				SKU: &armcontainerservice.ManagedClusterSKU{
+					Tier: getSKUTierFree(),
+				},
+				Tags: map[string]*string{
+					"env": to.StringPtr("dev"),
+				},
+			},
+			want: want{
+				broken:   true,
+				result:   "Free",
+				severity: "High",
+			},
+		},
+		{
+			name:               "AKSScanner SKU Free in production with production severity",
+			productionSeverity: true,
+			target: &armcontainerservice.ManagedCluster{
+				SKU: &armcontainerservice.ManagedClusterSKU{
+					Tier: getSKUTierFree(),
+				},
+				Tags: map[string]*string{
+					"env": to.StringPtr("prod"),
+				},
+			},
+			want: want{
+				broken:   true,
+				result:   "Free tier in production (env=prod)",
+				severity: "High",
+			},
+		},
+		{
+			name:               "AKSScanner SKU Free in dev with production severity",
+			productionSeverity: true,
+			target: &armcontainerservice.ManagedCluster{
+				SKU: &armcontainerservice.ManagedClusterSKU{
+					Tier: getSKUTierFree(),
+				},
+				Tags: map[string]*string{
+					"env": to.StringPtr("dev"),
+				},
+			},
+			want: want{
+				broken:   true,
+				result:   "Free",
+				severity: "Medium",
+			},
+		},
+		{
+			name:               "AKSScanner SKU Standard with production severity",
+			productionSeverity: true,
+			target: &armcontainerservice.ManagedCluster{
+				SKU: &armcontainerservice.ManagedClusterSKU{
+					Tier: getSKUTierStandard(),
+				},
+			},
+			want: want{
+				broken:   false,
+				result:   "Standard",
+				severity: "High",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &AKSScanner{config: &scanners.ScannerConfig{ProductionSeverity: tt.productionSeverity}}
+			engine := scanners.RuleEngine{}
+			r := engine.EvaluateRule(s.GetRules()["SKU"], tt.target, &scanners.ScanContext{})
+			got := want{
+				broken:   r.IsBroken,
+				result:   r.Result,
+				severity: r.Severity,
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AKSScanner Rule.Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func getNetworkPluginKubenet() *armcontainerservice.NetworkPlugin {
 	s := armcontainerservice.NetworkPluginKubenet
 	return &s
@@ -579,8 +1858,8 @@ func getSKUTierFree() *armcontainerservice.ManagedClusterSKUTier {
 	return &s
 }
 
-func getSKUTierPaid() *armcontainerservice.ManagedClusterSKUTier {
-	s := armcontainerservice.ManagedClusterSKUTierPaid
+func getSKUTierStandard() *armcontainerservice.ManagedClusterSKUTier {
+	s := armcontainerservice.ManagedClusterSKUTierStandard
 	return &s
 }
 
@@ -593,3 +1872,31 @@ func getOutboundTypeLoadBalancer() *armcontainerservice.OutboundType {
 	s := armcontainerservice.OutboundTypeLoadBalancer
 	return &s
 }
+
+func getLoadBalancerSKU(s armcontainerservice.LoadBalancerSKU) *armcontainerservice.LoadBalancerSKU {
+	return &s
+}
+
+func getNetworkPlugin(p armcontainerservice.NetworkPlugin) *armcontainerservice.NetworkPlugin {
+	return &p
+}
+
+func getNetworkPluginMode(m armcontainerservice.NetworkPluginMode) *armcontainerservice.NetworkPluginMode {
+	return &m
+}
+
+func getAgentPoolMode(m armcontainerservice.AgentPoolMode) *armcontainerservice.AgentPoolMode {
+	return &m
+}
+
+func getScaleSetPriority(p armcontainerservice.ScaleSetPriority) *armcontainerservice.ScaleSetPriority {
+	return &p
+}
+
+func getOSSKU(s armcontainerservice.OSSKU) *armcontainerservice.OSSKU {
+	return &s
+}
+
+func getScaleDownMode(m armcontainerservice.ScaleDownMode) *armcontainerservice.ScaleDownMode {
+	return &m
+}
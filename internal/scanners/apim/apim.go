@@ -42,7 +42,7 @@ func (a *APIManagementScanner) Scan(resourceGroupName string, scanContext *scann
 	if err != nil {
 		return nil, err
 	}
-	engine := scanners.RuleEngine{}
+	engine := scanners.RuleEngine{Cache: a.config.RuleCache}
 	rules := a.GetRules()
 	results := []scanners.AzureServiceResult{}
 
@@ -52,9 +52,12 @@ func (a *APIManagementScanner) Scan(resourceGroupName string, scanContext *scann
 		results = append(results, scanners.AzureServiceResult{
 			SubscriptionID: a.config.SubscriptionID,
 			ResourceGroup:  resourceGroupName,
+			ResourceID:     *s.ID,
 			ServiceName:    *s.Name,
 			Type:           *s.Type,
 			Location:       *s.Location,
+			Tags:           s.Tags,
+			CreatedAt:      scanners.GetCreatedAt(s),
 			Rules:          rr,
 		})
 	}
@@ -72,9 +75,17 @@ func (a *APIManagementScanner) listServices(resourceGroupName string) ([]*armapi
 				return nil, err
 			}
 			services = append(services, resp.Value...)
+			if limited, ok := scanners.LimitResources(a.config, services); ok {
+				return limited, nil
+			}
 		}
 		return services, nil
 	}
 
 	return a.listServicesFunc(resourceGroupName)
 }
+
+// GetResourceTypes - Returns the resource types scanned by the APIManagementScanner
+func (a *APIManagementScanner) GetResourceTypes() []string {
+	return []string{"Microsoft.ApiManagement/service"}
+}
@@ -4,7 +4,6 @@
 package apim
 
 import (
-	"log"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/apimanagement/armapimanagement"
@@ -22,12 +21,7 @@ func (a *APIManagementScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Medium",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				service := target.(*armapimanagement.ServiceResource)
-				hasDiagnostics, err := a.diagnosticsSettings.HasDiagnostics(*service.ID)
-				if err != nil {
-					log.Fatalf("Error checking diagnostic settings for service %s: %s", *service.Name, err)
-				}
-
-				return !hasDiagnostics, ""
+				return a.diagnosticsSettings.EvalDiagnostics("apim-001", *service.ID, scanContext)
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/api-management/api-management-howto-use-azure-monitor#resource-logs",
 		},
@@ -98,7 +92,7 @@ func (a *APIManagementScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Low",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armapimanagement.ServiceResource)
-				caf := strings.HasPrefix(*c.Name, "apim")
+				caf := scanners.HasCAFPrefix(*c.Name, "apim", scanContext)
 				return !caf, ""
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
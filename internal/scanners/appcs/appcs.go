@@ -42,7 +42,7 @@ func (a *AppConfigurationScanner) Scan(resourceGroupName string, scanContext *sc
 	if err != nil {
 		return nil, err
 	}
-	engine := scanners.RuleEngine{}
+	engine := scanners.RuleEngine{Cache: a.config.RuleCache}
 	rules := a.GetRules()
 	results := []scanners.AzureServiceResult{}
 
@@ -52,9 +52,12 @@ func (a *AppConfigurationScanner) Scan(resourceGroupName string, scanContext *sc
 		results = append(results, scanners.AzureServiceResult{
 			SubscriptionID: a.config.SubscriptionID,
 			ResourceGroup:  resourceGroupName,
+			ResourceID:     *app.ID,
 			ServiceName:    *app.Name,
 			Type:           *app.Type,
 			Location:       *app.Location,
+			Tags:           app.Tags,
+			CreatedAt:      scanners.GetCreatedAt(app),
 			Rules:          rr,
 		})
 	}
@@ -71,9 +74,17 @@ func (a *AppConfigurationScanner) list(resourceGroupName string) ([]*armappconfi
 				return nil, err
 			}
 			apps = append(apps, resp.Value...)
+			if limited, ok := scanners.LimitResources(a.config, apps); ok {
+				return limited, nil
+			}
 		}
 		return apps, nil
 	}
 
 	return a.listFunc(resourceGroupName)
 }
+
+// GetResourceTypes - Returns the resource types scanned by the AppConfigurationScanner
+func (a *AppConfigurationScanner) GetResourceTypes() []string {
+	return []string{"Microsoft.AppConfiguration/configurationStores"}
+}
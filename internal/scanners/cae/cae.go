@@ -5,8 +5,12 @@ package cae
 
 import (
 	"log"
+	"strings"
+	"sync"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
 	"github.com/cmendible/azqr/internal/scanners"
 )
 
@@ -15,17 +19,33 @@ type ContainerAppsScanner struct {
 	config              *scanners.ScannerConfig
 	diagnosticsSettings scanners.DiagnosticsSettings
 	appsClient          *armappcontainers.ManagedEnvironmentsClient
-	listAppsFunc        func(resourceGroupName string) ([]*armappcontainers.ManagedEnvironment, error)
+	containerAppsClient *armappcontainers.ContainerAppsClient
+	// mu - Guards the settings of the environments loaded by Scan, read by the rules while other Resource Groups are scanned
+	mu                    sync.RWMutex
+	daprEnvironments      map[string]bool
+	revisionModes         map[string]map[string]string
+	subnetPrefixes        map[string]string
+	listAppsFunc          func(resourceGroupName string) ([]*armappcontainers.ManagedEnvironment, error)
+	listContainerAppsFunc func(resourceGroupName string) ([]*armappcontainers.ContainerApp, error)
+	getSubnetPrefixFunc   func(subnetID string) (string, error)
 }
 
 // Init - Initializes the ContainerAppsScanner
 func (a *ContainerAppsScanner) Init(config *scanners.ScannerConfig) error {
 	a.config = config
+	// The environment settings loaded by Scan belong to the subscription of the previous config
+	a.daprEnvironments = nil
+	a.revisionModes = nil
+	a.subnetPrefixes = nil
 	var err error
 	a.appsClient, err = armappcontainers.NewManagedEnvironmentsClient(config.SubscriptionID, config.Cred, config.ClientOptions)
 	if err != nil {
 		return err
 	}
+	a.containerAppsClient, err = armappcontainers.NewContainerAppsClient(config.SubscriptionID, config.Cred, config.ClientOptions)
+	if err != nil {
+		return err
+	}
 	a.diagnosticsSettings = scanners.DiagnosticsSettings{}
 	err = a.diagnosticsSettings.Init(config)
 	if err != nil {
@@ -42,25 +62,119 @@ func (a *ContainerAppsScanner) Scan(resourceGroupName string, scanContext *scann
 	if err != nil {
 		return nil, err
 	}
-	engine := scanners.RuleEngine{}
+	engine := scanners.RuleEngine{Cache: a.config.RuleCache}
 	rules := a.GetRules()
 	results := []scanners.AzureServiceResult{}
 
+	containerApps, err := a.listContainerApps(resourceGroupName)
+	if err != nil {
+		return nil, err
+	}
+	daprEnvironments := map[string]bool{}
+	revisionModes := map[string]map[string]string{}
+	for _, app := range containerApps {
+		if app.Name == nil || app.Properties == nil || app.Properties.ManagedEnvironmentID == nil {
+			continue
+		}
+		environmentID := strings.ToLower(*app.Properties.ManagedEnvironmentID)
+
+		// Single is the default revision mode
+		mode := string(armappcontainers.ActiveRevisionsModeSingle)
+		if c := app.Properties.Configuration; c != nil {
+			if c.Dapr != nil && c.Dapr.Enabled != nil && *c.Dapr.Enabled {
+				daprEnvironments[environmentID] = true
+			}
+			if c.ActiveRevisionsMode != nil {
+				mode = string(*c.ActiveRevisionsMode)
+			}
+		}
+		if revisionModes[environmentID] == nil {
+			revisionModes[environmentID] = map[string]string{}
+		}
+		revisionModes[environmentID][*app.Name] = mode
+	}
+
+	subnetPrefixes := map[string]string{}
+	for _, app := range apps {
+		if app.Properties == nil || app.Properties.VnetConfiguration == nil || app.Properties.VnetConfiguration.InfrastructureSubnetID == nil {
+			continue
+		}
+		subnetID := *app.Properties.VnetConfiguration.InfrastructureSubnetID
+		prefix, err := a.getSubnetPrefix(subnetID)
+		if err != nil {
+			// e.g. a VNet in another subscription without read access, only cae-013 needs the prefix
+			log.Printf("WARNING: Unable to read the infrastructure subnet of Container Apps Environment %s, skipping cae-013: %s", *app.Name, err)
+			continue
+		}
+		subnetPrefixes[strings.ToLower(subnetID)] = prefix
+	}
+
+	a.mu.Lock()
+	if a.daprEnvironments == nil {
+		a.daprEnvironments = map[string]bool{}
+		a.revisionModes = map[string]map[string]string{}
+		a.subnetPrefixes = map[string]string{}
+	}
+	for id := range daprEnvironments {
+		a.daprEnvironments[id] = true
+	}
+	// Container Apps of other Resource Groups may run in the same environment
+	for id, modes := range revisionModes {
+		if a.revisionModes[id] == nil {
+			a.revisionModes[id] = map[string]string{}
+		}
+		for name, mode := range modes {
+			a.revisionModes[id][name] = mode
+		}
+	}
+	for id, prefix := range subnetPrefixes {
+		a.subnetPrefixes[id] = prefix
+	}
+	a.mu.Unlock()
+
 	for _, app := range apps {
 		rr := engine.EvaluateRules(rules, app, scanContext)
 
 		results = append(results, scanners.AzureServiceResult{
 			SubscriptionID: a.config.SubscriptionID,
 			ResourceGroup:  resourceGroupName,
+			ResourceID:     *app.ID,
 			ServiceName:    *app.Name,
 			Type:           *app.Type,
 			Location:       *app.Location,
+			Tags:           app.Tags,
+			CreatedAt:      scanners.GetCreatedAt(app),
 			Rules:          rr,
 		})
 	}
 	return results, nil
 }
 
+// usesDapr - Returns true if any Container App of the environment has Dapr enabled
+func (a *ContainerAppsScanner) usesDapr(environmentID string) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.daprEnvironments[strings.ToLower(environmentID)]
+}
+
+// getRevisionModes - Returns a copy of the revision modes of the Container Apps of the environment, by Container App name
+func (a *ContainerAppsScanner) getRevisionModes(environmentID string) map[string]string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	modes := map[string]string{}
+	for name, mode := range a.revisionModes[strings.ToLower(environmentID)] {
+		modes[name] = mode
+	}
+	return modes
+}
+
+// getLoadedSubnetPrefix - Returns the address prefix of the infrastructure subnet loaded by Scan
+func (a *ContainerAppsScanner) getLoadedSubnetPrefix(subnetID string) string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.subnetPrefixes[strings.ToLower(subnetID)]
+}
+
 func (a *ContainerAppsScanner) listApps(resourceGroupName string) ([]*armappcontainers.ManagedEnvironment, error) {
 	if a.listAppsFunc == nil {
 		pager := a.appsClient.NewListByResourceGroupPager(resourceGroupName, nil)
@@ -71,9 +185,66 @@ func (a *ContainerAppsScanner) listApps(resourceGroupName string) ([]*armappcont
 				return nil, err
 			}
 			apps = append(apps, resp.Value...)
+			if limited, ok := scanners.LimitResources(a.config, apps); ok {
+				return limited, nil
+			}
 		}
 		return apps, nil
 	}
 
 	return a.listAppsFunc(resourceGroupName)
 }
+
+// listContainerApps - Returns the Container Apps in the Resource Group
+func (a *ContainerAppsScanner) listContainerApps(resourceGroupName string) ([]*armappcontainers.ContainerApp, error) {
+	if a.listContainerAppsFunc == nil {
+		pager := a.containerAppsClient.NewListByResourceGroupPager(resourceGroupName, nil)
+		apps := make([]*armappcontainers.ContainerApp, 0)
+		for pager.More() {
+			resp, err := pager.NextPage(a.config.Ctx)
+			if err != nil {
+				return nil, err
+			}
+			apps = append(apps, resp.Value...)
+		}
+		return apps, nil
+	}
+
+	return a.listContainerAppsFunc(resourceGroupName)
+}
+
+// getSubnetPrefix - Returns the address prefix of the subnet
+func (a *ContainerAppsScanner) getSubnetPrefix(subnetID string) (string, error) {
+	if a.getSubnetPrefixFunc == nil {
+		id, err := arm.ParseResourceID(subnetID)
+		if err != nil {
+			return "", err
+		}
+		// The subnet may belong to a virtual network in another subscription
+		client, err := armnetwork.NewSubnetsClient(id.SubscriptionID, a.config.Cred, a.config.ClientOptions)
+		if err != nil {
+			return "", err
+		}
+		resp, err := client.Get(a.config.Ctx, id.ResourceGroupName, id.Parent.Name, id.Name, nil)
+		if err != nil {
+			return "", err
+		}
+		if resp.Properties == nil {
+			return "", nil
+		}
+		if resp.Properties.AddressPrefix != nil {
+			return *resp.Properties.AddressPrefix, nil
+		}
+		if len(resp.Properties.AddressPrefixes) > 0 && resp.Properties.AddressPrefixes[0] != nil {
+			return *resp.Properties.AddressPrefixes[0], nil
+		}
+		return "", nil
+	}
+
+	return a.getSubnetPrefixFunc(subnetID)
+}
+
+// GetResourceTypes - Returns the resource types scanned by the ContainerAppsScanner
+func (a *ContainerAppsScanner) GetResourceTypes() []string {
+	return []string{"Microsoft.App/managedEnvironments"}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package cae
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+func TestContainerAppsScanner_ScanRevisionModes(t *testing.T) {
+	environmentID := "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.App/managedEnvironments/cae"
+	multiple := armappcontainers.ActiveRevisionsModeMultiple
+	a := &ContainerAppsScanner{
+		config: &scanners.ScannerConfig{SubscriptionID: "sub"},
+		listAppsFunc: func(resourceGroupName string) ([]*armappcontainers.ManagedEnvironment, error) {
+			return []*armappcontainers.ManagedEnvironment{}, nil
+		},
+		listContainerAppsFunc: func(resourceGroupName string) ([]*armappcontainers.ContainerApp, error) {
+			return []*armappcontainers.ContainerApp{
+				{
+					Name: to.StringPtr("ca-api"),
+					Properties: &armappcontainers.ContainerAppProperties{
+						ManagedEnvironmentID: to.StringPtr(environmentID),
+						Configuration: &armappcontainers.Configuration{
+							ActiveRevisionsMode: &multiple,
+							Dapr: &armappcontainers.Dapr{
+								Enabled: to.BoolPtr(true),
+							},
+						},
+					},
+				},
+				{
+					Name: to.StringPtr("ca-web"),
+					Properties: &armappcontainers.ContainerAppProperties{
+						ManagedEnvironmentID: to.StringPtr(environmentID),
+					},
+				},
+			}, nil
+		},
+	}
+
+	scanContext := &scanners.ScanContext{}
+	if _, err := a.Scan("rg", scanContext); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]map[string]string{
+		"/subscriptions/sub/resourcegroups/rg/providers/microsoft.app/managedenvironments/cae": {
+			"ca-api": "Multiple",
+			"ca-web": "Single",
+		},
+	}
+	if !reflect.DeepEqual(a.revisionModes, want) {
+		t.Errorf("Scan() revision modes = %v, want %v", a.revisionModes, want)
+	}
+	if !a.usesDapr(environmentID) {
+		t.Errorf("Scan() Dapr environments = %v, want the environment with Dapr enabled", a.daprEnvironments)
+	}
+}
+
+func TestContainerAppsScanner_ScanSubnetError(t *testing.T) {
+	a := &ContainerAppsScanner{
+		config: &scanners.ScannerConfig{SubscriptionID: "sub"},
+		listAppsFunc: func(resourceGroupName string) ([]*armappcontainers.ManagedEnvironment, error) {
+			return []*armappcontainers.ManagedEnvironment{
+				{
+					ID:       to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.App/managedEnvironments/cae"),
+					Name:     to.StringPtr("cae"),
+					Type:     to.StringPtr("Microsoft.App/managedEnvironments"),
+					Location: to.StringPtr("westeurope"),
+					Properties: &armappcontainers.ManagedEnvironmentProperties{
+						ZoneRedundant: to.BoolPtr(true),
+						VnetConfiguration: &armappcontainers.VnetConfiguration{
+							Internal:               to.BoolPtr(true),
+							InfrastructureSubnetID: to.StringPtr("/subscriptions/other/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/cae"),
+						},
+					},
+				},
+			}, nil
+		},
+		listContainerAppsFunc: func(resourceGroupName string) ([]*armappcontainers.ContainerApp, error) {
+			return []*armappcontainers.ContainerApp{}, nil
+		},
+		getSubnetPrefixFunc: func(subnetID string) (string, error) {
+			return "", errors.New("AuthorizationFailed")
+		},
+		diagnosticsSettings: scanners.DiagnosticsSettings{
+			HasDiagnosticsFunc: func(resourceId string) (bool, error) {
+				return true, nil
+			},
+		},
+	}
+
+	results, err := a.Scan("rg", &scanners.ScanContext{})
+	if err != nil {
+		t.Fatalf("Scan() error = %v, the environment should be scanned without cae-013", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Scan() = %d results, want 1", len(results))
+	}
+	if r := results[0].Rules["cae-013"]; r.IsBroken || r.Result != "" {
+		t.Errorf("Scan() cae-013 = %v, %s, want it skipped", r.IsBroken, r.Result)
+	}
+}
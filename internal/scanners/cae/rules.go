@@ -4,10 +4,12 @@
 package cae
 
 import (
-	"log"
+	"fmt"
+	"net"
+	"sort"
 	"strings"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
 	"github.com/cmendible/azqr/internal/scanners"
 )
 
@@ -22,12 +24,7 @@ func (a *ContainerAppsScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Medium",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				service := target.(*armappcontainers.ManagedEnvironment)
-				hasDiagnostics, err := a.diagnosticsSettings.HasDiagnostics(*service.ID)
-				if err != nil {
-					log.Fatalf("Error checking diagnostic settings for service %s: %s", *service.Name, err)
-				}
-
-				return !hasDiagnostics, ""
+				return a.diagnosticsSettings.EvalDiagnostics("cae-001", *service.ID, scanContext)
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/container-apps/log-options#diagnostic-settings",
 		},
@@ -76,7 +73,7 @@ func (a *ContainerAppsScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Low",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armappcontainers.ManagedEnvironment)
-				caf := strings.HasPrefix(*c.Name, "cae")
+				caf := scanners.HasCAFPrefix(*c.Name, "cae", scanContext)
 				return !caf, ""
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
@@ -93,5 +90,97 @@ func (a *ContainerAppsScanner) GetRules() map[string]scanners.AzureRule {
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
+		"cae-012": {
+			Id:          "cae-012",
+			Category:    "Security",
+			Subcategory: "Networking",
+			Description: "ContainerApp Environment using Dapr should enforce mTLS",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				app := target.(*armappcontainers.ManagedEnvironment)
+				if !a.usesDapr(*app.ID) {
+					return false, ""
+				}
+
+				mtls := app.Properties != nil && app.Properties.PeerAuthentication != nil && app.Properties.PeerAuthentication.Mtls != nil &&
+					app.Properties.PeerAuthentication.Mtls.Enabled != nil && *app.Properties.PeerAuthentication.Mtls.Enabled
+				if !mtls {
+					return true, "mTLS Disabled"
+				}
+				return false, "mTLS Enabled"
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/container-apps/dapr-overview",
+		},
+		"cae-013": {
+			Id:          "cae-013",
+			Category:    "Networking",
+			Subcategory: "Best Practices",
+			Description: "ContainerApp Environment infrastructure subnet should be at least a /23",
+			Severity:    "High",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				app := target.(*armappcontainers.ManagedEnvironment)
+				if app.Properties == nil || app.Properties.VnetConfiguration == nil || app.Properties.VnetConfiguration.InfrastructureSubnetID == nil {
+					return false, ""
+				}
+
+				prefix := a.getLoadedSubnetPrefix(*app.Properties.VnetConfiguration.InfrastructureSubnetID)
+				_, network, err := net.ParseCIDR(prefix)
+				if err != nil {
+					return false, prefix
+				}
+				ones, _ := network.Mask.Size()
+				return ones > 23, prefix
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/container-apps/networking",
+		},
+		"cae-014": {
+			Id:          "cae-014",
+			Category:    "Security",
+			Subcategory: "Encryption",
+			Description: "ContainerApp Environment with VNet integration should encrypt peer-to-peer traffic",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				app := target.(*armappcontainers.ManagedEnvironment)
+				if app.Properties == nil || app.Properties.VnetConfiguration == nil || app.Properties.VnetConfiguration.InfrastructureSubnetID == nil {
+					return false, ""
+				}
+
+				p := app.Properties
+				if p.PeerTrafficConfiguration != nil && p.PeerTrafficConfiguration.Encryption != nil &&
+					p.PeerTrafficConfiguration.Encryption.Enabled != nil && *p.PeerTrafficConfiguration.Encryption.Enabled {
+					return false, "Enabled"
+				}
+				if p.PeerAuthentication != nil && p.PeerAuthentication.Mtls != nil &&
+					p.PeerAuthentication.Mtls.Enabled != nil && *p.PeerAuthentication.Mtls.Enabled {
+					return false, "Enabled (mTLS)"
+				}
+				return true, "Disabled"
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/container-apps/networking#peer-to-peer-encryption",
+		},
+		"cae-015": {
+			Id:          "cae-015",
+			Category:    "Operations",
+			Subcategory: "Best Practices",
+			Description: "Container Apps revision mode should be reviewed against the deployment strategy",
+			Severity:    "Low",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				app := target.(*armappcontainers.ManagedEnvironment)
+				modes := a.getRevisionModes(*app.ID)
+
+				names := make([]string, 0, len(modes))
+				for name := range modes {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+
+				apps := make([]string, 0, len(names))
+				for _, name := range names {
+					apps = append(apps, fmt.Sprintf("%s: %s", name, modes[name]))
+				}
+				return false, strings.Join(apps, ", ")
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/container-apps/revisions#revision-modes",
+		},
 	}
 }
@@ -7,7 +7,7 @@ import (
 	"reflect"
 	"testing"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/cmendible/azqr/internal/scanners"
 )
@@ -18,6 +18,9 @@ func TestContainerAppsScanner_Rules(t *testing.T) {
 		target              interface{}
 		scanContext         *scanners.ScanContext
 		diagnosticsSettings scanners.DiagnosticsSettings
+		daprEnvironments    map[string]bool
+		revisionModes       map[string]map[string]string
+		subnetPrefixes      map[string]string
 	}
 	type want struct {
 		broken bool
@@ -128,11 +131,214 @@ func TestContainerAppsScanner_Rules(t *testing.T) {
 				result: "",
 			},
 		},
+		{
+			name: "ContainerAppsScanner Dapr with mTLS enforced",
+			fields: fields{
+				rule: "cae-012",
+				target: &armappcontainers.ManagedEnvironment{
+					ID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.App/managedEnvironments/cae"),
+					Properties: &armappcontainers.ManagedEnvironmentProperties{
+						PeerAuthentication: &armappcontainers.ManagedEnvironmentPropertiesPeerAuthentication{
+							Mtls: &armappcontainers.Mtls{
+								Enabled: to.BoolPtr(true),
+							},
+						},
+					},
+				},
+				scanContext: &scanners.ScanContext{},
+				daprEnvironments: map[string]bool{
+					"/subscriptions/sub/resourcegroups/rg/providers/microsoft.app/managedenvironments/cae": true,
+				},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "mTLS Enabled",
+			},
+		},
+		{
+			name: "ContainerAppsScanner Dapr without mTLS",
+			fields: fields{
+				rule: "cae-012",
+				target: &armappcontainers.ManagedEnvironment{
+					ID:         to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.App/managedEnvironments/cae"),
+					Properties: &armappcontainers.ManagedEnvironmentProperties{},
+				},
+				scanContext: &scanners.ScanContext{},
+				daprEnvironments: map[string]bool{
+					"/subscriptions/sub/resourcegroups/rg/providers/microsoft.app/managedenvironments/cae": true,
+				},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "mTLS Disabled",
+			},
+		},
+		{
+			name: "ContainerAppsScanner without Dapr",
+			fields: fields{
+				rule: "cae-012",
+				target: &armappcontainers.ManagedEnvironment{
+					ID:         to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.App/managedEnvironments/cae"),
+					Properties: &armappcontainers.ManagedEnvironmentProperties{},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "ContainerAppsScanner infrastructure subnet /23",
+			fields: fields{
+				rule: "cae-013",
+				target: &armappcontainers.ManagedEnvironment{
+					ID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.App/managedEnvironments/cae"),
+					Properties: &armappcontainers.ManagedEnvironmentProperties{
+						VnetConfiguration: &armappcontainers.VnetConfiguration{
+							InfrastructureSubnetID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/cae"),
+						},
+					},
+				},
+				scanContext: &scanners.ScanContext{},
+				subnetPrefixes: map[string]string{
+					"/subscriptions/sub/resourcegroups/rg/providers/microsoft.network/virtualnetworks/vnet/subnets/cae": "10.0.0.0/23",
+				},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "10.0.0.0/23",
+			},
+		},
+		{
+			name: "ContainerAppsScanner infrastructure subnet /27",
+			fields: fields{
+				rule: "cae-013",
+				target: &armappcontainers.ManagedEnvironment{
+					ID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.App/managedEnvironments/cae"),
+					Properties: &armappcontainers.ManagedEnvironmentProperties{
+						VnetConfiguration: &armappcontainers.VnetConfiguration{
+							InfrastructureSubnetID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/cae"),
+						},
+					},
+				},
+				scanContext: &scanners.ScanContext{},
+				subnetPrefixes: map[string]string{
+					"/subscriptions/sub/resourcegroups/rg/providers/microsoft.network/virtualnetworks/vnet/subnets/cae": "10.0.0.0/27",
+				},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "10.0.0.0/27",
+			},
+		},
+		{
+			name: "ContainerAppsScanner peer-to-peer encryption enabled",
+			fields: fields{
+				rule: "cae-014",
+				target: &armappcontainers.ManagedEnvironment{
+					Properties: &armappcontainers.ManagedEnvironmentProperties{
+						VnetConfiguration: &armappcontainers.VnetConfiguration{
+							InfrastructureSubnetID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/cae"),
+						},
+						PeerTrafficConfiguration: &armappcontainers.ManagedEnvironmentPropertiesPeerTrafficConfiguration{
+							Encryption: &armappcontainers.ManagedEnvironmentPropertiesPeerTrafficConfigurationEncryption{
+								Enabled: to.BoolPtr(true),
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "Enabled",
+			},
+		},
+		{
+			name: "ContainerAppsScanner peer-to-peer encryption disabled",
+			fields: fields{
+				rule: "cae-014",
+				target: &armappcontainers.ManagedEnvironment{
+					Properties: &armappcontainers.ManagedEnvironmentProperties{
+						VnetConfiguration: &armappcontainers.VnetConfiguration{
+							InfrastructureSubnetID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/cae"),
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "Disabled",
+			},
+		},
+		{
+			name: "ContainerAppsScanner peer-to-peer encryption without VNet integration",
+			fields: fields{
+				rule: "cae-014",
+				target: &armappcontainers.ManagedEnvironment{
+					Properties: &armappcontainers.ManagedEnvironmentProperties{},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "ContainerAppsScanner single and multiple revision modes",
+			fields: fields{
+				rule: "cae-015",
+				target: &armappcontainers.ManagedEnvironment{
+					ID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.App/managedEnvironments/cae"),
+				},
+				scanContext: &scanners.ScanContext{},
+				revisionModes: map[string]map[string]string{
+					"/subscriptions/sub/resourcegroups/rg/providers/microsoft.app/managedenvironments/cae": {
+						"ca-web": "Single",
+						"ca-api": "Multiple",
+					},
+				},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "ca-api: Multiple, ca-web: Single",
+			},
+		},
+		{
+			name: "ContainerAppsScanner revision mode without container apps",
+			fields: fields{
+				rule: "cae-015",
+				target: &armappcontainers.ManagedEnvironment{
+					ID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.App/managedEnvironments/cae"),
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s := &ContainerAppsScanner{
 				diagnosticsSettings: tt.fields.diagnosticsSettings,
+				daprEnvironments:    tt.fields.daprEnvironments,
+				revisionModes:       tt.fields.revisionModes,
+				subnetPrefixes:      tt.fields.subnetPrefixes,
 			}
 			rules := s.GetRules()
 			b, w := rules[tt.fields.rule].Eval(tt.fields.target, tt.fields.scanContext)
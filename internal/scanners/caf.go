@@ -0,0 +1,46 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadCAFPrefixes - Loads a YAML file mapping resource abbreviations to the accepted name prefixes, e.g.:
+//
+//	aks: [aks, k8s]
+//	st: [st, sa]
+func LoadCAFPrefixes(filename string) (map[string][]string, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	prefixes := map[string][]string{}
+	if err := yaml.Unmarshal(b, &prefixes); err != nil {
+		return nil, err
+	}
+	return prefixes, nil
+}
+
+// HasCAFPrefix - Returns true if the name starts with one of the prefixes accepted for the abbreviation.
+// When no prefixes are configured for the abbreviation, the abbreviation itself is the only accepted prefix.
+func HasCAFPrefix(name, abbreviation string, scanContext *ScanContext) bool {
+	prefixes := []string{abbreviation}
+	if scanContext != nil {
+		if configured, ok := scanContext.CAFPrefixes[abbreviation]; ok {
+			prefixes = configured
+		}
+	}
+
+	for _, p := range prefixes {
+		if strings.HasPrefix(name, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestHasCAFPrefix(t *testing.T) {
+	custom := &ScanContext{
+		CAFPrefixes: map[string][]string{
+			"aks": {"k8s", "aks"},
+		},
+	}
+	tests := []struct {
+		name         string
+		resourceName string
+		scanContext  *ScanContext
+		want         bool
+	}{
+		{
+			name:         "default prefix",
+			resourceName: "aks-prod",
+			scanContext:  &ScanContext{},
+			want:         true,
+		},
+		{
+			name:         "custom prefix with defaults",
+			resourceName: "k8s-prod",
+			scanContext:  &ScanContext{},
+			want:         false,
+		},
+		{
+			name:         "custom prefix",
+			resourceName: "k8s-prod",
+			scanContext:  custom,
+			want:         true,
+		},
+		{
+			name:         "unknown prefix",
+			resourceName: "cluster-prod",
+			scanContext:  custom,
+			want:         false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasCAFPrefix(tt.resourceName, "aks", tt.scanContext); got != tt.want {
+				t.Errorf("HasCAFPrefix() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadCAFPrefixes(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "caf.yaml")
+	if err := os.WriteFile(filename, []byte("aks: [aks, k8s]\nst:\n  - sa\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadCAFPrefixes(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		"aks": {"aks", "k8s"},
+		"st":  {"sa"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadCAFPrefixes() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+type (
+	// Checkpoint - Tracks completed scan units so an interrupted scan can be resumed.
+	// Units are appended to the file, one JSON line each, as soon as they complete. Subscription ids are stored
+	// unmasked, so units of different subscriptions never collide and resumed results can be rendered with or without masking.
+	Checkpoint struct {
+		mu   sync.Mutex
+		path string
+		file *os.File
+		// resumed - Set once the file holds units of this scan or, by LoadCheckpoint, of a previous one,
+		// so it is appended to instead of replaced
+		resumed bool
+		// units - Units loaded from a previous scan, not yet replayed
+		units map[string]CheckpointUnit
+	}
+
+	// CheckpointUnit - A (subscription, resource group, scanner) tuple and its results
+	CheckpointUnit struct {
+		SubscriptionID string
+		ResourceGroup  string
+		Scanner        string
+		Results        []AzureServiceResult
+	}
+)
+
+// NewCheckpoint - Creates an empty Checkpoint persisted to path. An existing file is replaced on the first Complete.
+func NewCheckpoint(path string) *Checkpoint {
+	return &Checkpoint{
+		path:  path,
+		units: map[string]CheckpointUnit{},
+	}
+}
+
+// LoadCheckpoint - Loads a Checkpoint previously saved to path. New units are appended to the same file.
+// A unit truncated by an interrupted write is discarded, so its scanner runs again.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	c := NewCheckpoint(path)
+	c.resumed = true
+	dec := json.NewDecoder(f)
+	for {
+		offset := dec.InputOffset()
+		var u CheckpointUnit
+		err := dec.Decode(&u)
+		if err == io.EOF {
+			break
+		}
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			if err := f.Truncate(offset); err != nil {
+				return nil, err
+			}
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid checkpoint file %s: %w", path, err)
+		}
+		c.units[checkpointKey(u.SubscriptionID, u.ResourceGroup, u.Scanner)] = u
+	}
+	return c, nil
+}
+
+// GetScannerName - Returns the name used to identify a scanner in a Checkpoint
+func GetScannerName(scanner IAzureScanner) string {
+	return fmt.Sprintf("%T", scanner)
+}
+
+func checkpointKey(subscriptionID, resourceGroup, scanner string) string {
+	return fmt.Sprintf("%s/%s/%s", subscriptionID, resourceGroup, scanner)
+}
+
+// Completed - Returns the stored results if the unit was completed by a previous scan.
+// Results are only returned once, so they are not kept in memory after being replayed.
+func (c *Checkpoint) Completed(subscriptionID, resourceGroup, scanner string) ([]AzureServiceResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := checkpointKey(subscriptionID, resourceGroup, scanner)
+	u, ok := c.units[key]
+	if !ok {
+		return nil, false
+	}
+	delete(c.units, key)
+	return u.Results, true
+}
+
+// IsCompleted - Returns true if the unit was completed by a previous scan and its results are not replayed yet
+func (c *Checkpoint) IsCompleted(subscriptionID, resourceGroup, scanner string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.units[checkpointKey(subscriptionID, resourceGroup, scanner)]
+	return ok
+}
+
+// Complete - Appends a completed unit and its results to the checkpoint file, truncating the file left by another scan
+// on the first unit of a new Checkpoint. The results are written before
+// Complete returns, so callers can keep adding rules to them.
+func (c *Checkpoint) Complete(subscriptionID, resourceGroup, scanner string, results []AzureServiceResult) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := json.Marshal(CheckpointUnit{
+		SubscriptionID: subscriptionID,
+		ResourceGroup:  resourceGroup,
+		Scanner:        scanner,
+		Results:        results,
+	})
+	if err != nil {
+		return err
+	}
+
+	if c.file == nil {
+		flag := os.O_APPEND | os.O_CREATE | os.O_WRONLY
+		if !c.resumed {
+			flag |= os.O_TRUNC
+		}
+		c.file, err = os.OpenFile(c.path, flag, 0600)
+		if err != nil {
+			return err
+		}
+		c.resumed = true
+	}
+	_, err = c.file.Write(append(data, '\n'))
+	return err
+}
+
+// Delete - Removes the Checkpoint from disk once the scan has completed
+func (c *Checkpoint) Delete() error {
+	if err := c.Close(); err != nil {
+		return err
+	}
+	err := os.Remove(c.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Close - Closes the checkpoint file, keeping it on disk so the scan can be resumed
+func (c *Checkpoint) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file == nil {
+		return nil
+	}
+	err := c.file.Close()
+	c.file = nil
+	return err
+}
@@ -0,0 +1,138 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckpoint(t *testing.T) {
+	subscriptionID := "00000000-0000-0000-0000-000000000000"
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	checkpoint := NewCheckpoint(path)
+	for _, rg := range []string{"rg1", "rg2"} {
+		err := checkpoint.Complete(subscriptionID, rg, "scanner", []AzureServiceResult{
+			{
+				SubscriptionID: subscriptionID,
+				ResourceGroup:  rg,
+				ServiceName:    "name",
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := checkpoint.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a scan interrupted while writing a third unit
+	if err := os.WriteFile(path, append(data, []byte(`{"SubscriptionID":"xxx`)...), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Subscriptions with the same last characters must not share their units
+	if _, ok := loaded.Completed("11111111-1111-1111-1111-111110000000", "rg1", "scanner"); ok {
+		t.Error("Completed() should not return the units of another subscription")
+	}
+	res, ok := loaded.Completed(subscriptionID, "rg1", "scanner")
+	if !ok || len(res) != 1 || res[0].ServiceName != "name" || res[0].SubscriptionID != subscriptionID {
+		t.Errorf("Completed() = %v, %v, want the results of rg1 with the subscription id unmasked", res, ok)
+	}
+	if _, ok := loaded.Completed(subscriptionID, "rg1", "scanner"); ok {
+		t.Error("Completed() should only return the results of a unit once")
+	}
+	if _, ok := loaded.Completed(subscriptionID, "rg3", "scanner"); ok {
+		t.Error("Completed() should not return units that were not completed")
+	}
+
+	if err := loaded.Complete(subscriptionID, "rg3", "scanner", []AzureServiceResult{}); err != nil {
+		t.Fatal(err)
+	}
+	loaded = reload(t, loaded, path)
+	for _, rg := range []string{"rg2", "rg3"} {
+		if _, ok := loaded.Completed(subscriptionID, rg, "scanner"); !ok {
+			t.Errorf("Completed() should return the results of %s after the truncated unit", rg)
+		}
+	}
+
+	if err := loaded.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Delete() should remove %s", path)
+	}
+}
+
+func reload(t *testing.T, checkpoint *Checkpoint, path string) *Checkpoint {
+	t.Helper()
+	if err := checkpoint.Close(); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loaded
+}
+
+func TestCheckpoint_Truncate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	for _, rg := range []string{"rg1", "rg2"} {
+		checkpoint := NewCheckpoint(path)
+		if err := checkpoint.Complete("sub", rg, "scanner", []AzureServiceResult{}); err != nil {
+			t.Fatal(err)
+		}
+		if err := checkpoint.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	loaded, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.IsCompleted("sub", "rg1", "scanner") {
+		t.Error("NewCheckpoint() should replace the units left in the file by a previous scan")
+	}
+	if !loaded.IsCompleted("sub", "rg2", "scanner") {
+		t.Error("NewCheckpoint() should record the units of its own scan")
+	}
+
+	// A resumed scan appends to the units of the interrupted one, also after being closed
+	if err := loaded.Complete("sub", "rg3", "scanner", []AzureServiceResult{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.Complete("sub", "rg4", "scanner", []AzureServiceResult{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	resumed, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, rg := range []string{"rg2", "rg3", "rg4"} {
+		if !resumed.IsCompleted("sub", rg, "scanner") {
+			t.Errorf("LoadCheckpoint() should append the units of the resumed scan, %s is missing", rg)
+		}
+	}
+}
@@ -42,7 +42,7 @@ func (c *ContainerInstanceScanner) Scan(resourceGroupName string, scanContext *s
 	if err != nil {
 		return nil, err
 	}
-	engine := scanners.RuleEngine{}
+	engine := scanners.RuleEngine{Cache: c.config.RuleCache}
 	rules := c.GetRules()
 	results := []scanners.AzureServiceResult{}
 
@@ -52,9 +52,12 @@ func (c *ContainerInstanceScanner) Scan(resourceGroupName string, scanContext *s
 		results = append(results, scanners.AzureServiceResult{
 			SubscriptionID: c.config.SubscriptionID,
 			ResourceGroup:  resourceGroupName,
+			ResourceID:     *instance.ID,
 			ServiceName:    *instance.Name,
 			Type:           *instance.Type,
 			Location:       *instance.Location,
+			Tags:           instance.Tags,
+			CreatedAt:      scanners.GetCreatedAt(instance),
 			Rules:          rr,
 		})
 	}
@@ -71,9 +74,17 @@ func (c *ContainerInstanceScanner) listInstances(resourceGroupName string) ([]*a
 				return nil, err
 			}
 			apps = append(apps, resp.Value...)
+			if limited, ok := scanners.LimitResources(c.config, apps); ok {
+				return limited, nil
+			}
 		}
 		return apps, nil
 	}
 
 	return c.listInstancesFunc(resourceGroupName)
 }
+
+// GetResourceTypes - Returns the resource types scanned by the ContainerInstanceScanner
+func (c *ContainerInstanceScanner) GetResourceTypes() []string {
+	return []string{"Microsoft.ContainerInstance/containerGroups"}
+}
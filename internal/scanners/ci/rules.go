@@ -4,8 +4,6 @@
 package ci
 
 import (
-	"strings"
-
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerinstance/armcontainerinstance"
 	"github.com/cmendible/azqr/internal/scanners"
 )
@@ -72,7 +70,7 @@ func (a *ContainerInstanceScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Low",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armcontainerinstance.ContainerGroup)
-				caf := strings.HasPrefix(*c.Name, "ci")
+				caf := scanners.HasCAFPrefix(*c.Name, "ci", scanContext)
 				return !caf, ""
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
@@ -42,7 +42,7 @@ func (c *CosmosDBScanner) Scan(resourceGroupName string, scanContext *scanners.S
 	if err != nil {
 		return nil, err
 	}
-	engine := scanners.RuleEngine{}
+	engine := scanners.RuleEngine{Cache: c.config.RuleCache}
 	rules := c.GetRules()
 	results := []scanners.AzureServiceResult{}
 
@@ -52,9 +52,12 @@ func (c *CosmosDBScanner) Scan(resourceGroupName string, scanContext *scanners.S
 		results = append(results, scanners.AzureServiceResult{
 			SubscriptionID: c.config.SubscriptionID,
 			ResourceGroup:  resourceGroupName,
+			ResourceID:     *database.ID,
 			ServiceName:    *database.Name,
 			Type:           *database.Type,
 			Location:       *database.Location,
+			Tags:           database.Tags,
+			CreatedAt:      scanners.GetCreatedAt(database),
 			Rules:          rr,
 		})
 	}
@@ -72,9 +75,17 @@ func (c *CosmosDBScanner) listDatabases(resourceGroupName string) ([]*armcosmos.
 				return nil, err
 			}
 			domains = append(domains, resp.Value...)
+			if limited, ok := scanners.LimitResources(c.config, domains); ok {
+				return limited, nil
+			}
 		}
 		return domains, nil
 	}
 
 	return c.listDatabasesFunc(resourceGroupName)
 }
+
+// GetResourceTypes - Returns the resource types scanned by the CosmosDBScanner
+func (c *CosmosDBScanner) GetResourceTypes() []string {
+	return []string{"Microsoft.DocumentDB/databaseAccounts"}
+}
@@ -4,8 +4,7 @@
 package cosmos
 
 import (
-	"log"
-	"strings"
+	"fmt"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/cosmos/armcosmos"
 	"github.com/cmendible/azqr/internal/scanners"
@@ -22,12 +21,7 @@ func (a *CosmosDBScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Medium",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				service := target.(*armcosmos.DatabaseAccountGetResults)
-				hasDiagnostics, err := a.diagnosticsSettings.HasDiagnostics(*service.ID)
-				if err != nil {
-					log.Fatalf("Error checking diagnostic settings for service %s: %s", *service.Name, err)
-				}
-
-				return !hasDiagnostics, ""
+				return a.diagnosticsSettings.EvalDiagnostics("cosmos-001", *service.ID, scanContext)
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/cosmos-db/monitor-resource-logs",
 		},
@@ -119,7 +113,7 @@ func (a *CosmosDBScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Low",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armcosmos.DatabaseAccountGetResults)
-				caf := strings.HasPrefix(*c.Name, "cosmos")
+				caf := scanners.HasCAFPrefix(*c.Name, "cosmos", scanContext)
 				return !caf, ""
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
@@ -136,5 +130,85 @@ func (a *CosmosDBScanner) GetRules() map[string]scanners.AzureRule {
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
+		"cosmos-008": {
+			Id:          "cosmos-008",
+			Category:    "High Availability and Resiliency",
+			Subcategory: "SKU",
+			Description: "CosmosDB production accounts should use provisioned throughput",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armcosmos.DatabaseAccountGetResults)
+				mode := "Provisioned"
+				if c.Properties != nil {
+					for _, capability := range c.Properties.Capabilities {
+						if capability.Name != nil && *capability.Name == "EnableServerless" {
+							mode = "Serverless"
+						}
+					}
+				}
+				return mode == "Serverless" && scanners.IsProduction(c.Tags), mode
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/cosmos-db/throughput-serverless",
+		},
+		"cosmos-009": scanners.NewPublicNetworkAccessRule("cosmos-009", "CosmosDB", "https://learn.microsoft.com/en-us/azure/cosmos-db/how-to-configure-firewall#disable-public-network-access"),
+		"cosmos-010": {
+			Id:          "cosmos-010",
+			Category:    "High Availability and Resiliency",
+			Subcategory: "Reliability",
+			Description: "CosmosDB with analytical store should use continuous backup",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armcosmos.DatabaseAccountGetResults)
+				if c.Properties == nil || c.Properties.EnableAnalyticalStorage == nil || !*c.Properties.EnableAnalyticalStorage {
+					return false, ""
+				}
+
+				backup := armcosmos.BackupPolicyTypePeriodic
+				if c.Properties.BackupPolicy != nil && c.Properties.BackupPolicy.GetBackupPolicy().Type != nil {
+					backup = *c.Properties.BackupPolicy.GetBackupPolicy().Type
+				}
+				return backup == armcosmos.BackupPolicyTypePeriodic, fmt.Sprintf("Analytical Store, %s Backup", backup)
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/cosmos-db/analytical-store-introduction#backup",
+		},
+		"cosmos-011": {
+			Id:          "cosmos-011",
+			Category:    "High Availability and Resiliency",
+			Subcategory: "SKU",
+			Description: "CosmosDB production accounts should not use the free tier",
+			Severity:    "High",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armcosmos.DatabaseAccountGetResults)
+				if c.Properties == nil || c.Properties.EnableFreeTier == nil || !*c.Properties.EnableFreeTier {
+					return false, ""
+				}
+
+				if tag, ok := scanners.GetProductionTag(c.Tags); ok {
+					return true, fmt.Sprintf("Free Tier (%s)", tag)
+				}
+				return false, "Free Tier"
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/cosmos-db/free-tier",
+		},
+		"cosmos-012": {
+			Id:          "cosmos-012",
+			Category:    "Security",
+			Subcategory: "Identity and Access Control",
+			Description: "CosmosDB should use RBAC data plane roles instead of keys",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armcosmos.DatabaseAccountGetResults)
+				if c.Properties != nil && c.Properties.DisableLocalAuth != nil && *c.Properties.DisableLocalAuth {
+					return false, "Key Based Access: Disabled"
+				}
+
+				metadataWrite := "Enabled"
+				if c.Properties != nil && c.Properties.DisableKeyBasedMetadataWriteAccess != nil && *c.Properties.DisableKeyBasedMetadataWriteAccess {
+					metadataWrite = "Disabled"
+				}
+				return true, fmt.Sprintf("Key Based Access: Enabled, Key Based Metadata Write: %s", metadataWrite)
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/cosmos-db/how-to-setup-rbac#disable-local-auth",
+		},
 	}
 }
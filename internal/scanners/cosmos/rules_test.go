@@ -184,6 +184,215 @@ func TestCosmosDBScanner_Rules(t *testing.T) {
 				result: "",
 			},
 		},
+		{
+			name: "CosmosDBScanner Serverless in production",
+			fields: fields{
+				rule: "cosmos-008",
+				target: &armcosmos.DatabaseAccountGetResults{
+					Tags: map[string]*string{
+						"env": to.StringPtr("prod"),
+					},
+					Properties: &armcosmos.DatabaseAccountGetProperties{
+						Capabilities: []*armcosmos.Capability{
+							{
+								Name: to.StringPtr("EnableServerless"),
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "Serverless",
+			},
+		},
+		{
+			name: "CosmosDBScanner Provisioned in production",
+			fields: fields{
+				rule: "cosmos-008",
+				target: &armcosmos.DatabaseAccountGetResults{
+					Tags: map[string]*string{
+						"env": to.StringPtr("prod"),
+					},
+					Properties: &armcosmos.DatabaseAccountGetProperties{
+						Capabilities: []*armcosmos.Capability{},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "Provisioned",
+			},
+		},
+		{
+			name: "CosmosDBScanner public network access disabled",
+			fields: fields{
+				rule: "cosmos-009",
+				target: &armcosmos.DatabaseAccountGetResults{
+					Properties: &armcosmos.DatabaseAccountGetProperties{
+						PublicNetworkAccess: getPublicNetworkAccessDisabled(),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "Disabled",
+			},
+		},
+		{
+			name: "CosmosDBScanner analytical store with periodic backup",
+			fields: fields{
+				rule: "cosmos-010",
+				target: &armcosmos.DatabaseAccountGetResults{
+					Properties: &armcosmos.DatabaseAccountGetProperties{
+						EnableAnalyticalStorage: to.BoolPtr(true),
+						BackupPolicy: &armcosmos.PeriodicModeBackupPolicy{
+							Type: getBackupPolicyType(armcosmos.BackupPolicyTypePeriodic),
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "Analytical Store, Periodic Backup",
+			},
+		},
+		{
+			name: "CosmosDBScanner analytical store with continuous backup",
+			fields: fields{
+				rule: "cosmos-010",
+				target: &armcosmos.DatabaseAccountGetResults{
+					Properties: &armcosmos.DatabaseAccountGetProperties{
+						EnableAnalyticalStorage: to.BoolPtr(true),
+						BackupPolicy: &armcosmos.ContinuousModeBackupPolicy{
+							Type: getBackupPolicyType(armcosmos.BackupPolicyTypeContinuous),
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "Analytical Store, Continuous Backup",
+			},
+		},
+		{
+			name: "CosmosDBScanner free tier in production",
+			fields: fields{
+				rule: "cosmos-011",
+				target: &armcosmos.DatabaseAccountGetResults{
+					Properties: &armcosmos.DatabaseAccountGetProperties{
+						EnableFreeTier: to.BoolPtr(true),
+					},
+					Tags: map[string]*string{
+						"environment": to.StringPtr("production"),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "Free Tier (environment=production)",
+			},
+		},
+		{
+			name: "CosmosDBScanner free tier in dev",
+			fields: fields{
+				rule: "cosmos-011",
+				target: &armcosmos.DatabaseAccountGetResults{
+					Properties: &armcosmos.DatabaseAccountGetProperties{
+						EnableFreeTier: to.BoolPtr(true),
+					},
+					Tags: map[string]*string{
+						"environment": to.StringPtr("dev"),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "Free Tier",
+			},
+		},
+		{
+			name: "CosmosDBScanner standard in production",
+			fields: fields{
+				rule: "cosmos-011",
+				target: &armcosmos.DatabaseAccountGetResults{
+					Properties: &armcosmos.DatabaseAccountGetProperties{
+						EnableFreeTier: to.BoolPtr(false),
+					},
+					Tags: map[string]*string{
+						"environment": to.StringPtr("production"),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "CosmosDBScanner keys disabled",
+			fields: fields{
+				rule: "cosmos-012",
+				target: &armcosmos.DatabaseAccountGetResults{
+					Properties: &armcosmos.DatabaseAccountGetProperties{
+						DisableLocalAuth: to.BoolPtr(true),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "Key Based Access: Disabled",
+			},
+		},
+		{
+			name: "CosmosDBScanner key based access",
+			fields: fields{
+				rule: "cosmos-012",
+				target: &armcosmos.DatabaseAccountGetResults{
+					Properties: &armcosmos.DatabaseAccountGetProperties{
+						DisableLocalAuth:                   to.BoolPtr(false),
+						DisableKeyBasedMetadataWriteAccess: to.BoolPtr(true),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "Key Based Access: Enabled, Key Based Metadata Write: Disabled",
+			},
+		},
+		{
+			name: "CosmosDBScanner key based access without properties",
+			fields: fields{
+				rule:                "cosmos-012",
+				target:              &armcosmos.DatabaseAccountGetResults{},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "Key Based Access: Enabled, Key Based Metadata Write: Enabled",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -207,3 +416,12 @@ func getDatabaseAccountOfferType() *string {
 	s := "Standard"
 	return &s
 }
+
+func getPublicNetworkAccessDisabled() *armcosmos.PublicNetworkAccess {
+	s := armcosmos.PublicNetworkAccessDisabled
+	return &s
+}
+
+func getBackupPolicyType(t armcosmos.BackupPolicyType) *armcosmos.BackupPolicyType {
+	return &t
+}
@@ -12,10 +12,12 @@ import (
 
 // ContainerRegistryScanner - Scanner for Container Registries
 type ContainerRegistryScanner struct {
-	config              *scanners.ScannerConfig
-	diagnosticsSettings scanners.DiagnosticsSettings
-	registriesClient    *armcontainerregistry.RegistriesClient
-	listRegistriesFunc  func(resourceGroupName string) ([]*armcontainerregistry.Registry, error)
+	config               *scanners.ScannerConfig
+	diagnosticsSettings  scanners.DiagnosticsSettings
+	registriesClient     *armcontainerregistry.RegistriesClient
+	replicationsClient   *armcontainerregistry.ReplicationsClient
+	listRegistriesFunc   func(resourceGroupName string) ([]*armcontainerregistry.Registry, error)
+	listReplicationsFunc func(resourceGroupName string, registryName string) ([]*armcontainerregistry.Replication, error)
 }
 
 // Init - Initializes the ContainerRegistryScanner
@@ -26,6 +28,10 @@ func (c *ContainerRegistryScanner) Init(config *scanners.ScannerConfig) error {
 	if err != nil {
 		return err
 	}
+	c.replicationsClient, err = armcontainerregistry.NewReplicationsClient(config.SubscriptionID, config.Cred, config.ClientOptions)
+	if err != nil {
+		return err
+	}
 	c.diagnosticsSettings = scanners.DiagnosticsSettings{}
 	err = c.diagnosticsSettings.Init(config)
 	if err != nil {
@@ -42,7 +48,7 @@ func (c *ContainerRegistryScanner) Scan(resourceGroupName string, scanContext *s
 	if err != nil {
 		return nil, err
 	}
-	engine := scanners.RuleEngine{}
+	engine := scanners.RuleEngine{Cache: c.config.RuleCache}
 	rules := c.GetRules()
 	results := []scanners.AzureServiceResult{}
 
@@ -52,9 +58,12 @@ func (c *ContainerRegistryScanner) Scan(resourceGroupName string, scanContext *s
 		results = append(results, scanners.AzureServiceResult{
 			SubscriptionID: c.config.SubscriptionID,
 			ResourceGroup:  resourceGroupName,
+			ResourceID:     *registry.ID,
 			ServiceName:    *registry.Name,
 			Type:           *registry.Type,
 			Location:       *registry.Location,
+			Tags:           registry.Tags,
+			CreatedAt:      scanners.GetCreatedAt(registry),
 			Rules:          rr,
 		})
 	}
@@ -72,9 +81,36 @@ func (c *ContainerRegistryScanner) listRegistries(resourceGroupName string) ([]*
 				return nil, err
 			}
 			registries = append(registries, resp.Value...)
+			if limited, ok := scanners.LimitResources(c.config, registries); ok {
+				return limited, nil
+			}
 		}
 		return registries, nil
 	}
 
 	return c.listRegistriesFunc(resourceGroupName)
 }
+
+// listReplications - Returns the replications of the registry, including the one in the home region
+func (c *ContainerRegistryScanner) listReplications(resourceGroupName string, registryName string) ([]*armcontainerregistry.Replication, error) {
+	if c.listReplicationsFunc == nil {
+		pager := c.replicationsClient.NewListPager(resourceGroupName, registryName, nil)
+
+		replications := make([]*armcontainerregistry.Replication, 0)
+		for pager.More() {
+			resp, err := pager.NextPage(c.config.Ctx)
+			if err != nil {
+				return nil, err
+			}
+			replications = append(replications, resp.Value...)
+		}
+		return replications, nil
+	}
+
+	return c.listReplicationsFunc(resourceGroupName, registryName)
+}
+
+// GetResourceTypes - Returns the resource types scanned by the ContainerRegistryScanner
+func (c *ContainerRegistryScanner) GetResourceTypes() []string {
+	return []string{"Microsoft.ContainerRegistry/registries"}
+}
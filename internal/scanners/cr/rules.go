@@ -4,9 +4,11 @@
 package cr
 
 import (
-	"log"
+	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerregistry/armcontainerregistry"
 	"github.com/cmendible/azqr/internal/scanners"
 )
@@ -22,12 +24,7 @@ func (a *ContainerRegistryScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Medium",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				service := target.(*armcontainerregistry.Registry)
-				hasDiagnostics, err := a.diagnosticsSettings.HasDiagnostics(*service.ID)
-				if err != nil {
-					log.Fatalf("Error checking diagnostic settings for service %s: %s", *service.Name, err)
-				}
-
-				return !hasDiagnostics, ""
+				return a.diagnosticsSettings.EvalDiagnostics("cr-001", *service.ID, scanContext)
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/container-registry/monitor-service",
 		},
@@ -88,7 +85,7 @@ func (a *ContainerRegistryScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Low",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armcontainerregistry.Registry)
-				caf := strings.HasPrefix(*c.Name, "cr")
+				caf := scanners.HasCAFPrefix(*c.Name, "cr", scanContext)
 				return !caf, ""
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
@@ -146,5 +143,68 @@ func (a *ContainerRegistryScanner) GetRules() map[string]scanners.AzureRule {
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/container-registry/container-registry-retention-policy",
 		},
+		"cr-011": {
+			Id:          "cr-011",
+			Category:    "High Availability and Resiliency",
+			Subcategory: "Geo-Replication",
+			Description: "ContainerRegistry Premium should be geo-replicated to a secondary region",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armcontainerregistry.Registry)
+				if c.SKU == nil || c.SKU.Name == nil || *c.SKU.Name != armcontainerregistry.SKUNamePremium {
+					return false, ""
+				}
+				resource, err := arm.ParseResourceID(*c.ID)
+				if err != nil {
+					return scanContext.RuleError("cr-011", *c.ID, err)
+				}
+				replications, err := a.listReplications(resource.ResourceGroupName, *c.Name)
+				if err != nil {
+					return scanContext.RuleError("cr-011", *c.ID, fmt.Errorf("listing replications: %w", err))
+				}
+
+				home := strings.ToLower(strings.ReplaceAll(*c.Location, " ", ""))
+				regions := []string{}
+				secondary := false
+				for _, replication := range replications {
+					if replication.Location == nil {
+						continue
+					}
+					region := strings.ToLower(strings.ReplaceAll(*replication.Location, " ", ""))
+					if region != home {
+						secondary = true
+					}
+					regions = append(regions, region)
+				}
+				sort.Strings(regions)
+				return !secondary, strings.Join(regions, ", ")
+			},
+			CacheKey: func(target interface{}, scanContext *scanners.ScanContext) interface{} {
+				c := target.(*armcontainerregistry.Registry)
+				return []interface{}{c.ID, c.SKU, c.Location}
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/container-registry/container-registry-geo-replication",
+		},
+		"cr-012": {
+			Id:          "cr-012",
+			Category:    "Security",
+			Subcategory: "Networking",
+			Description: "ContainerRegistry with private endpoints should have public network access disabled",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armcontainerregistry.Registry)
+				if c.Properties == nil || len(c.Properties.PrivateEndpointConnections) == 0 {
+					return false, ""
+				}
+
+				// Public network access defaults to Enabled
+				access := armcontainerregistry.PublicNetworkAccessEnabled
+				if c.Properties.PublicNetworkAccess != nil {
+					access = *c.Properties.PublicNetworkAccess
+				}
+				return access == armcontainerregistry.PublicNetworkAccessEnabled, string(access)
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/container-registry/container-registry-access-selected-networks#disable-public-network-access",
+		},
 	}
 }
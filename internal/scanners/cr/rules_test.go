@@ -4,6 +4,7 @@
 package cr
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
@@ -230,6 +231,88 @@ func TestContainerRegistryScanner_Rules(t *testing.T) {
 				result: "",
 			},
 		},
+		{
+			name: "ContainerRegistryScanner public network access enabled with private endpoints",
+			fields: fields{
+				rule: "cr-012",
+				target: &armcontainerregistry.Registry{
+					Properties: &armcontainerregistry.RegistryProperties{
+						PublicNetworkAccess: getPublicNetworkAccess(armcontainerregistry.PublicNetworkAccessEnabled),
+						PrivateEndpointConnections: []*armcontainerregistry.PrivateEndpointConnection{
+							{
+								ID: to.StringPtr("test"),
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "Enabled",
+			},
+		},
+		{
+			name: "ContainerRegistryScanner public network access disabled with private endpoints",
+			fields: fields{
+				rule: "cr-012",
+				target: &armcontainerregistry.Registry{
+					Properties: &armcontainerregistry.RegistryProperties{
+						PublicNetworkAccess: getPublicNetworkAccess(armcontainerregistry.PublicNetworkAccessDisabled),
+						PrivateEndpointConnections: []*armcontainerregistry.PrivateEndpointConnection{
+							{
+								ID: to.StringPtr("test"),
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "Disabled",
+			},
+		},
+		{
+			name: "ContainerRegistryScanner public network access not set with private endpoints",
+			fields: fields{
+				rule: "cr-012",
+				target: &armcontainerregistry.Registry{
+					Properties: &armcontainerregistry.RegistryProperties{
+						PrivateEndpointConnections: []*armcontainerregistry.PrivateEndpointConnection{
+							{
+								ID: to.StringPtr("test"),
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "Enabled",
+			},
+		},
+		{
+			name: "ContainerRegistryScanner without private endpoints",
+			fields: fields{
+				rule: "cr-012",
+				target: &armcontainerregistry.Registry{
+					Properties: &armcontainerregistry.RegistryProperties{
+						PublicNetworkAccess: getPublicNetworkAccess(armcontainerregistry.PublicNetworkAccessEnabled),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -263,3 +346,105 @@ func getPolicyStatusDisabled() *armcontainerregistry.PolicyStatus {
 	s := armcontainerregistry.PolicyStatusDisabled
 	return &s
 }
+
+func getPublicNetworkAccess(a armcontainerregistry.PublicNetworkAccess) *armcontainerregistry.PublicNetworkAccess {
+	return &a
+}
+
+func TestContainerRegistryScanner_ReplicationsRule(t *testing.T) {
+	type want struct {
+		broken bool
+		result string
+	}
+	tests := []struct {
+		name         string
+		sku          armcontainerregistry.SKUName
+		replications []string
+		want         want
+	}{
+		{
+			name:         "ContainerRegistryScanner Premium multi region",
+			sku:          armcontainerregistry.SKUNamePremium,
+			replications: []string{"westeurope", "northeurope"},
+			want: want{
+				broken: false,
+				result: "northeurope, westeurope",
+			},
+		},
+		{
+			name:         "ContainerRegistryScanner Premium single region",
+			sku:          armcontainerregistry.SKUNamePremium,
+			replications: []string{"westeurope"},
+			want: want{
+				broken: true,
+				result: "westeurope",
+			},
+		},
+		{
+			name:         "ContainerRegistryScanner Standard",
+			sku:          armcontainerregistry.SKUNameStandard,
+			replications: []string{},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ContainerRegistryScanner{
+				listReplicationsFunc: func(resourceGroupName string, registryName string) ([]*armcontainerregistry.Replication, error) {
+					replications := []*armcontainerregistry.Replication{}
+					for _, location := range tt.replications {
+						replications = append(replications, &armcontainerregistry.Replication{
+							Location: to.StringPtr(location),
+						})
+					}
+					return replications, nil
+				},
+			}
+			rules := s.GetRules()
+			sku := tt.sku
+			b, w := rules["cr-011"].Eval(&armcontainerregistry.Registry{
+				ID:       to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerRegistry/registries/crtest"),
+				Name:     to.StringPtr("crtest"),
+				Location: to.StringPtr("westeurope"),
+				SKU: &armcontainerregistry.SKU{
+					Name: &sku,
+				},
+			}, &scanners.ScanContext{})
+			got := want{
+				broken: b,
+				result: w,
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ContainerRegistryScanner Rule.Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainerRegistryScanner_ReplicationsRuleError(t *testing.T) {
+	s := &ContainerRegistryScanner{
+		listReplicationsFunc: func(resourceGroupName string, registryName string) ([]*armcontainerregistry.Replication, error) {
+			return nil, errors.New("throttled")
+		},
+	}
+	sku := armcontainerregistry.SKUNamePremium
+	id := "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerRegistry/registries/crtest"
+	scanContext := &scanners.ScanContext{}
+	broken, result := s.GetRules()["cr-011"].Eval(&armcontainerregistry.Registry{
+		ID:       to.StringPtr(id),
+		Name:     to.StringPtr("crtest"),
+		Location: to.StringPtr("westeurope"),
+		SKU:      &armcontainerregistry.SKU{Name: &sku},
+	}, scanContext)
+	if broken || result != "Unknown" {
+		t.Errorf("ContainerRegistryScanner Rule.Eval() = %v, %s, want false, Unknown", broken, result)
+	}
+
+	errs := scanContext.RuleErrors()
+	if len(errs) != 1 || errs[0].Rule != "cr-011" || errs[0].ResourceID != id || errs[0].ResourceGroup != "rg" {
+		t.Errorf("ContainerRegistryScanner Rule.Eval() errors = %+v, want the cr-011 error of %s", errs, id)
+	}
+}
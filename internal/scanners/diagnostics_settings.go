@@ -4,19 +4,133 @@
 package scanners
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
 )
 
+// DefaultMinRetentionDays - Default minimum log retention, in days, for storage based diagnostic settings
+const DefaultMinRetentionDays = 30
+
+// AllMetricsCategory - Metric category that sends all the platform metrics of a resource
+const AllMetricsCategory = "AllMetrics"
+
+// Diagnostic settings destination types returned by GetDestinationTypes
+const (
+	DestinationLogAnalytics = "Log Analytics"
+	DestinationStorage      = "Storage"
+	DestinationEventHub     = "Event Hub"
+)
+
+// Retry settings used by HasDiagnostics and ListDiagnosticSettings for transient errors
+const (
+	DiagnosticsMaxAttempts       = 3
+	DefaultDiagnosticsRetryDelay = 500 * time.Millisecond
+)
+
+// diagnosticsCacheSize - Number of resources whose diagnostic settings are kept, enough for the resources
+// evaluated at the same time when Resource Groups are scanned concurrently
+const diagnosticsCacheSize = 64
+
+// DiagnosticsError - Error returned by HasDiagnostics and ListDiagnosticSettings once the diagnostic settings of a resource can't be read.
+// Retryable is true when the last error was transient (e.g. throttling) and the attempts were exhausted.
+type DiagnosticsError struct {
+	ResourceID string
+	Attempts   int
+	Retryable  bool
+	Err        error
+}
+
+func (e *DiagnosticsError) Error() string {
+	return fmt.Sprintf("checking diagnostic settings for %s failed after %d attempt(s): %s", e.ResourceID, e.Attempts, e.Err)
+}
+
+func (e *DiagnosticsError) Unwrap() error {
+	return e.Err
+}
+
+// IsRetryableError - Returns true if the error is transient: throttling, timeouts or server errors
+func IsRetryableError(err error) bool {
+	var diagnosticsErr *DiagnosticsError
+	if errors.As(err, &diagnosticsErr) {
+		return diagnosticsErr.Retryable
+	}
+
+	var responseErr *azcore.ResponseError
+	if errors.As(err, &responseErr) {
+		switch responseErr.StatusCode {
+		case http.StatusRequestTimeout,
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout:
+			return true
+		}
+	}
+	return false
+}
+
 // DiagnosticsSettings - analyzer
 type DiagnosticsSettings struct {
-	config                    *ScannerConfig
-	diagnosticsSettingsClient *armmonitor.DiagnosticSettingsClient
-	HasDiagnosticsFunc        func(resourceId string) (bool, error)
+	config                     *ScannerConfig
+	diagnosticsSettingsClient  *armmonitor.DiagnosticSettingsClient
+	MinRetentionDays           int
+	RetryDelay                 time.Duration
+	HasDiagnosticsFunc         func(resourceId string) (bool, error)
+	ListDiagnosticSettingsFunc func(resourceId string) ([]*armmonitor.DiagnosticSettingsResource, error)
+	// cache - Diagnostic settings of the last resources listed, shared by the rules of a resource. Created by Init.
+	cache *diagnosticsCache
+}
+
+// diagnosticsCache - Diagnostic settings by resource id, the oldest resource is evicted once diagnosticsCacheSize
+// resources are kept. Guarded by mu since a scanner may scan several Resource Groups concurrently.
+type diagnosticsCache struct {
+	mu       sync.Mutex
+	settings map[string][]*armmonitor.DiagnosticSettingsResource
+	order    []string
+}
+
+func newDiagnosticsCache() *diagnosticsCache {
+	return &diagnosticsCache{
+		settings: map[string][]*armmonitor.DiagnosticSettingsResource{},
+	}
+}
+
+func (c *diagnosticsCache) get(resourceID string) ([]*armmonitor.DiagnosticSettingsResource, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	settings, ok := c.settings[resourceID]
+	return settings, ok
+}
+
+func (c *diagnosticsCache) add(resourceID string, settings []*armmonitor.DiagnosticSettingsResource) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.settings[resourceID]; !ok {
+		if len(c.order) >= diagnosticsCacheSize {
+			delete(c.settings, c.order[0])
+			c.order = c.order[1:]
+		}
+		c.order = append(c.order, resourceID)
+	}
+	c.settings[resourceID] = settings
 }
 
 // Init - Initializes the DiagnosticsSettings
 func (s *DiagnosticsSettings) Init(config *ScannerConfig) error {
 	s.config = config
+	s.MinRetentionDays = config.MinRetentionDays
+	s.cache = newDiagnosticsCache()
 	var err error
 	s.diagnosticsSettingsClient, err = armmonitor.NewDiagnosticSettingsClient(s.config.Cred, config.ClientOptions)
 	if err != nil {
@@ -25,23 +139,257 @@ func (s *DiagnosticsSettings) Init(config *ScannerConfig) error {
 	return nil
 }
 
-// HasDiagnostics - Checks if a resource has diagnostics settings
+// HasDiagnostics - Checks if a resource has diagnostics settings. Transient errors are retried,
+// any error returned is a *DiagnosticsError.
 func (s *DiagnosticsSettings) HasDiagnostics(resourceID string) (bool, error) {
+	var hasDiagnostics bool
+	err := s.retry(resourceID, func() error {
+		var err error
+		hasDiagnostics, err = s.hasDiagnostics(resourceID)
+		return err
+	})
+	return hasDiagnostics, err
+}
+
+func (s *DiagnosticsSettings) hasDiagnostics(resourceID string) (bool, error) {
 	if s.HasDiagnosticsFunc == nil {
-		pager := s.diagnosticsSettingsClient.NewListPager(resourceID, nil)
+		settings, err := s.listDiagnosticSettings(resourceID)
+		if err != nil {
+			return false, err
+		}
+		return len(settings) > 0, nil
+	}
 
+	return s.HasDiagnosticsFunc(resourceID)
+}
+
+// ListDiagnosticSettings - Returns the diagnostic settings of a resource. Transient errors are retried,
+// any error returned is a *DiagnosticsError.
+func (s *DiagnosticsSettings) ListDiagnosticSettings(resourceID string) ([]*armmonitor.DiagnosticSettingsResource, error) {
+	var settings []*armmonitor.DiagnosticSettingsResource
+	err := s.retry(resourceID, func() error {
+		var err error
+		settings, err = s.listDiagnosticSettings(resourceID)
+		return err
+	})
+	return settings, err
+}
+
+// listDiagnosticSettings - Lists the diagnostic settings of a resource. The settings of the last resources are kept,
+// since rules are evaluated one resource at a time, so all the diagnostic settings rules of a resource share a single call.
+func (s *DiagnosticsSettings) listDiagnosticSettings(resourceID string) ([]*armmonitor.DiagnosticSettingsResource, error) {
+	if s.cache != nil {
+		if settings, ok := s.cache.get(resourceID); ok {
+			return settings, nil
+		}
+	}
+
+	settings := []*armmonitor.DiagnosticSettingsResource{}
+	if s.ListDiagnosticSettingsFunc == nil {
+		pager := s.diagnosticsSettingsClient.NewListPager(resourceID, nil)
 		for pager.More() {
 			resp, err := pager.NextPage(s.config.Ctx)
 			if err != nil {
-				return false, err
+				return nil, err
 			}
-			if len(resp.Value) > 0 {
-				return true, nil
+			settings = append(settings, resp.Value...)
+		}
+	} else {
+		var err error
+		settings, err = s.ListDiagnosticSettingsFunc(resourceID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if s.cache != nil {
+		s.cache.add(resourceID, settings)
+	}
+	return settings, nil
+}
+
+// retry - Calls f until it succeeds, fails with a non transient error, DiagnosticsMaxAttempts are made
+// or the context of the scan is done. Returns a *DiagnosticsError if f doesn't succeed.
+func (s *DiagnosticsSettings) retry(resourceID string, f func() error) error {
+	delay := s.RetryDelay
+	if delay <= 0 {
+		delay = DefaultDiagnosticsRetryDelay
+	}
+	ctx := context.Background()
+	if s.config != nil && s.config.Ctx != nil {
+		ctx = s.config.Ctx
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := f()
+		if err == nil {
+			return nil
+		}
+
+		retryable := IsRetryableError(err)
+		if !retryable || attempt >= DiagnosticsMaxAttempts {
+			return &DiagnosticsError{
+				ResourceID: resourceID,
+				Attempts:   attempt,
+				Retryable:  retryable,
+				Err:        err,
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return &DiagnosticsError{
+				ResourceID: resourceID,
+				Attempts:   attempt,
+				Err:        ctx.Err(),
 			}
+		case <-time.After(delay):
 		}
+		delay *= 2
+	}
+}
+
+// HasSubResourceDiagnostics - Checks if a sub-resource (e.g. blobServices/default of a storage account) has diagnostics settings
+func (s *DiagnosticsSettings) HasSubResourceDiagnostics(resourceID, subResource string) (bool, error) {
+	return s.HasDiagnostics(fmt.Sprintf("%s/%s", strings.TrimSuffix(resourceID, "/"), subResource))
+}
 
-		return false, nil
+// EvalDiagnostics - Evaluates the diagnostic settings rule of a resource: broken when it has no diagnostic settings.
+// Non retryable errors are logged as a warning and reported as Unknown, so a single resource doesn't stop the scan.
+// Errors still failing after the retries are recorded in the scan context as errors of the rule.
+func (s *DiagnosticsSettings) EvalDiagnostics(ruleID, resourceID string, scanContext *ScanContext) (bool, string) {
+	hasDiagnostics, err := s.HasDiagnostics(resourceID)
+	if err != nil {
+		return evalDiagnosticsError(ruleID, resourceID, scanContext, err)
 	}
 
-	return s.HasDiagnosticsFunc(resourceID)
+	return !hasDiagnostics, ""
+}
+
+// EvalSubResourceDiagnostics - Evaluates the diagnostic settings of the sub-resources of a resource, keyed by name
+// (e.g. blob: blobServices/default). Broken when any of them has no diagnostic settings, the result lists their sorted names.
+// Errors are handled as in EvalDiagnostics.
+func (s *DiagnosticsSettings) EvalSubResourceDiagnostics(ruleID, resourceID string, subResources map[string]string, scanContext *ScanContext) (bool, string) {
+	names := make([]string, 0, len(subResources))
+	for name := range subResources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	missing := []string{}
+	for _, name := range names {
+		hasDiagnostics, err := s.HasSubResourceDiagnostics(resourceID, subResources[name])
+		if err != nil {
+			return evalDiagnosticsError(ruleID, resourceID, scanContext, err)
+		}
+		if !hasDiagnostics {
+			missing = append(missing, name)
+		}
+	}
+
+	return len(missing) > 0, strings.Join(missing, ", ")
+}
+
+func evalDiagnosticsError(ruleID, resourceID string, scanContext *ScanContext, err error) (bool, string) {
+	if IsRetryableError(err) {
+		return scanContext.RuleError(ruleID, resourceID, err)
+	}
+	log.Printf("WARNING: %s", err)
+	return false, "Unknown"
+}
+
+// GetMinRetentionDays - Returns the minimum log retention, in days, expected for storage based diagnostic settings
+func (s *DiagnosticsSettings) GetMinRetentionDays() int {
+	if s.MinRetentionDays <= 0 {
+		return DefaultMinRetentionDays
+	}
+	return s.MinRetentionDays
+}
+
+// GetLogRetentionDays - Returns the shortest log retention, in days, of the diagnostic settings sending logs to a storage account.
+// Returns false if no diagnostic setting uses a storage based retention policy (0 days means logs are retained forever).
+func (s *DiagnosticsSettings) GetLogRetentionDays(resourceID string) (int, bool, error) {
+	settings, err := s.ListDiagnosticSettings(resourceID)
+	if err != nil {
+		return 0, false, err
+	}
+
+	days := 0
+	found := false
+	for _, setting := range settings {
+		if setting.Properties == nil || setting.Properties.StorageAccountID == nil || *setting.Properties.StorageAccountID == "" {
+			continue
+		}
+		for _, l := range setting.Properties.Logs {
+			if l.Enabled == nil || !*l.Enabled || l.RetentionPolicy == nil {
+				continue
+			}
+			policy := l.RetentionPolicy
+			if policy.Enabled == nil || !*policy.Enabled || policy.Days == nil || *policy.Days == 0 {
+				continue
+			}
+			if !found || int(*policy.Days) < days {
+				days = int(*policy.Days)
+				found = true
+			}
+		}
+	}
+	return days, found, nil
+}
+
+// GetDestinationTypes - Returns the sorted destination types (Log Analytics, Storage or Event Hub) of the diagnostic settings of a resource
+func (s *DiagnosticsSettings) GetDestinationTypes(resourceID string) ([]string, error) {
+	settings, err := s.ListDiagnosticSettings(resourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	types := map[string]bool{}
+	for _, setting := range settings {
+		if setting.Properties == nil {
+			continue
+		}
+		if setting.Properties.WorkspaceID != nil && *setting.Properties.WorkspaceID != "" {
+			types[DestinationLogAnalytics] = true
+		}
+		if setting.Properties.StorageAccountID != nil && *setting.Properties.StorageAccountID != "" {
+			types[DestinationStorage] = true
+		}
+		if setting.Properties.EventHubAuthorizationRuleID != nil && *setting.Properties.EventHubAuthorizationRuleID != "" {
+			types[DestinationEventHub] = true
+		}
+	}
+
+	destinations := []string{}
+	for t := range types {
+		destinations = append(destinations, t)
+	}
+	sort.Strings(destinations)
+	return destinations, nil
+}
+
+// GetMetricCategories - Returns the sorted metric categories (e.g. AllMetrics) enabled in the diagnostic settings of a resource
+func (s *DiagnosticsSettings) GetMetricCategories(resourceID string) ([]string, error) {
+	settings, err := s.ListDiagnosticSettings(resourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := map[string]bool{}
+	for _, setting := range settings {
+		if setting.Properties == nil {
+			continue
+		}
+		for _, m := range setting.Properties.Metrics {
+			if m.Enabled != nil && *m.Enabled && m.Category != nil {
+				categories[*m.Category] = true
+			}
+		}
+	}
+
+	enabled := []string{}
+	for c := range categories {
+		enabled = append(enabled, c)
+	}
+	sort.Strings(enabled)
+	return enabled, nil
 }
@@ -0,0 +1,271 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+func TestDiagnosticsSettings_HasDiagnostics(t *testing.T) {
+	throttled := &azcore.ResponseError{StatusCode: http.StatusTooManyRequests}
+	forbidden := &azcore.ResponseError{StatusCode: http.StatusForbidden}
+
+	type want struct {
+		hasDiagnostics bool
+		calls          int
+		err            bool
+		retryable      bool
+	}
+	tests := []struct {
+		name   string
+		errors []error
+		want   want
+	}{
+		{
+			name:   "transient error then success",
+			errors: []error{throttled, nil},
+			want: want{
+				hasDiagnostics: true,
+				calls:          2,
+			},
+		},
+		{
+			name:   "transient errors exhaust the attempts",
+			errors: []error{throttled, throttled, throttled, nil},
+			want: want{
+				calls:     DiagnosticsMaxAttempts,
+				err:       true,
+				retryable: true,
+			},
+		},
+		{
+			name:   "permanent error is not retried",
+			errors: []error{forbidden, nil},
+			want: want{
+				calls: 1,
+				err:   true,
+			},
+		},
+		{
+			name:   "transient then permanent error",
+			errors: []error{throttled, errors.New("invalid resource id"), nil},
+			want: want{
+				calls: 2,
+				err:   true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			s := &DiagnosticsSettings{
+				RetryDelay: time.Millisecond,
+				HasDiagnosticsFunc: func(resourceId string) (bool, error) {
+					err := tt.errors[calls]
+					calls++
+					return err == nil, err
+				},
+			}
+
+			got, err := s.HasDiagnostics("id")
+			if got != tt.want.hasDiagnostics {
+				t.Errorf("HasDiagnostics() = %v, want %v", got, tt.want.hasDiagnostics)
+			}
+			if calls != tt.want.calls {
+				t.Errorf("HasDiagnostics() calls = %d, want %d", calls, tt.want.calls)
+			}
+			if (err != nil) != tt.want.err {
+				t.Fatalf("HasDiagnostics() error = %v, want error %v", err, tt.want.err)
+			}
+			if err == nil {
+				return
+			}
+
+			var diagnosticsErr *DiagnosticsError
+			if !errors.As(err, &diagnosticsErr) {
+				t.Fatalf("HasDiagnostics() error = %T, want *DiagnosticsError", err)
+			}
+			if diagnosticsErr.Attempts != tt.want.calls {
+				t.Errorf("DiagnosticsError.Attempts = %d, want %d", diagnosticsErr.Attempts, tt.want.calls)
+			}
+			if IsRetryableError(err) != tt.want.retryable {
+				t.Errorf("IsRetryableError() = %v, want %v", IsRetryableError(err), tt.want.retryable)
+			}
+		})
+	}
+}
+
+func TestDiagnosticsSettings_HasDiagnostics_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	s := &DiagnosticsSettings{
+		config:     &ScannerConfig{Ctx: ctx},
+		RetryDelay: time.Hour,
+		HasDiagnosticsFunc: func(resourceId string) (bool, error) {
+			calls++
+			cancel()
+			return false, &azcore.ResponseError{StatusCode: http.StatusTooManyRequests}
+		},
+	}
+
+	_, err := s.HasDiagnostics("id")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("HasDiagnostics() error = %v, want %v", err, context.Canceled)
+	}
+	if calls != 1 {
+		t.Errorf("HasDiagnostics() calls = %d, want 1", calls)
+	}
+}
+
+func TestDiagnosticsSettings_EvalDiagnostics(t *testing.T) {
+	s := &DiagnosticsSettings{
+		HasDiagnosticsFunc: func(resourceId string) (bool, error) {
+			return false, &azcore.ResponseError{StatusCode: http.StatusNotFound}
+		},
+	}
+
+	scanContext := &ScanContext{}
+	broken, result := s.EvalDiagnostics("st-001", "id", scanContext)
+	if broken || result != "Unknown" {
+		t.Errorf("EvalDiagnostics() = %v, %s, want false, Unknown", broken, result)
+	}
+	if errors := scanContext.RuleErrors(); len(errors) != 0 {
+		t.Errorf("EvalDiagnostics() recorded %v, non retryable errors are only logged", errors)
+	}
+}
+
+func TestDiagnosticsSettings_EvalDiagnostics_Retryable(t *testing.T) {
+	s := &DiagnosticsSettings{
+		HasDiagnosticsFunc: func(resourceId string) (bool, error) {
+			return false, &DiagnosticsError{ResourceID: resourceId, Attempts: 3, Retryable: true, Err: &azcore.ResponseError{StatusCode: http.StatusTooManyRequests}}
+		},
+	}
+
+	resourceID := "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/st"
+	scanContext := &ScanContext{}
+	broken, result := s.EvalDiagnostics("st-001", resourceID, scanContext)
+	if broken || result != "Unknown" {
+		t.Errorf("EvalDiagnostics() = %v, %s, want false, Unknown", broken, result)
+	}
+
+	errors := scanContext.RuleErrors()
+	if len(errors) != 1 {
+		t.Fatalf("EvalDiagnostics() recorded %d errors, want 1", len(errors))
+	}
+	e := errors[0]
+	if e.Rule != "st-001" || e.ResourceID != resourceID || e.SubscriptionID != "sub" || e.ResourceGroup != "rg" || e.Error == "" {
+		t.Errorf("EvalDiagnostics() recorded %+v", e)
+	}
+}
+
+func TestDiagnosticsSettings_ListDiagnosticSettings(t *testing.T) {
+	throttled := &azcore.ResponseError{StatusCode: http.StatusTooManyRequests}
+	calls := 0
+	s := &DiagnosticsSettings{
+		RetryDelay: time.Millisecond,
+		cache:      newDiagnosticsCache(),
+		ListDiagnosticSettingsFunc: func(resourceId string) ([]*armmonitor.DiagnosticSettingsResource, error) {
+			calls++
+			if calls == 1 {
+				return nil, throttled
+			}
+			return []*armmonitor.DiagnosticSettingsResource{
+				{
+					Properties: &armmonitor.DiagnosticSettings{
+						StorageAccountID: to.StringPtr("st"),
+						Logs: []*armmonitor.LogSettings{
+							{Enabled: to.BoolPtr(true), RetentionPolicy: &armmonitor.RetentionPolicy{Enabled: to.BoolPtr(true), Days: to.Int32Ptr(90)}},
+							{Enabled: to.BoolPtr(true), RetentionPolicy: &armmonitor.RetentionPolicy{Enabled: to.BoolPtr(true), Days: to.Int32Ptr(7)}},
+							{Enabled: to.BoolPtr(false), RetentionPolicy: &armmonitor.RetentionPolicy{Enabled: to.BoolPtr(true), Days: to.Int32Ptr(1)}},
+						},
+					},
+				},
+				{
+					Properties: &armmonitor.DiagnosticSettings{
+						WorkspaceID: to.StringPtr("log"),
+					},
+				},
+			}, nil
+		},
+	}
+
+	hasDiagnostics, err := s.HasDiagnostics("id")
+	if err != nil || !hasDiagnostics {
+		t.Fatalf("HasDiagnostics() = %v, %v, want true", hasDiagnostics, err)
+	}
+
+	days, found, err := s.GetLogRetentionDays("id")
+	if err != nil || !found || days != 7 {
+		t.Errorf("GetLogRetentionDays() = %v, %v, %v, want 7, true", days, found, err)
+	}
+
+	destinations, err := s.GetDestinationTypes("id")
+	if err != nil || !reflect.DeepEqual(destinations, []string{DestinationLogAnalytics, DestinationStorage}) {
+		t.Errorf("GetDestinationTypes() = %v, %v", destinations, err)
+	}
+
+	if calls != 2 {
+		t.Errorf("ListDiagnosticSettingsFunc called %d times, want 2 (one retry, then cached)", calls)
+	}
+
+	if _, err := s.GetDestinationTypes("other"); err != nil || calls != 3 {
+		t.Errorf("GetDestinationTypes() of another resource: %v, calls %d, want 3", err, calls)
+	}
+}
+
+func TestDiagnosticsSettings_ListDiagnosticSettingsConcurrently(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+	s := &DiagnosticsSettings{
+		cache: newDiagnosticsCache(),
+		ListDiagnosticSettingsFunc: func(resourceId string) ([]*armmonitor.DiagnosticSettingsResource, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls[resourceId]++
+			return []*armmonitor.DiagnosticSettingsResource{}, nil
+		},
+	}
+
+	// Resources evaluated at the same time, as when Resource Groups are scanned concurrently, keep their settings
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		id := fmt.Sprintf("id-%d", i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 3; j++ {
+				if _, err := s.ListDiagnosticSettings(id); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for id, n := range calls {
+		if n != 1 {
+			t.Errorf("ListDiagnosticSettingsFunc called %d times for %s, want 1", n, id)
+		}
+	}
+
+	for i := 0; i < diagnosticsCacheSize; i++ {
+		if _, err := s.ListDiagnosticSettings(fmt.Sprintf("other-%d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, ok := s.cache.get("id-0"); ok {
+		t.Errorf("diagnostic settings of id-0 should be evicted after %d other resources", diagnosticsCacheSize)
+	}
+}
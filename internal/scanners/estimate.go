@@ -0,0 +1,72 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"fmt"
+)
+
+// callsPerSubscription - Calls made once per subscription: resource groups, private endpoints, Defender and Advisor
+const callsPerSubscription = 4
+
+// CallEstimate - Approximate number of ARM calls a scan will make
+type CallEstimate struct {
+	Subscriptions  int
+	ResourceGroups int
+	Resources      int
+	Calls          int
+}
+
+// EstimateCalls - Uses Resource Graph counts to estimate the ARM calls of a scan: the calls made once per subscription,
+// one list call per scanner and resource group and one diagnostic settings call per resource.
+// Calls made by rules reading sub-resources (e.g. consumer groups) are not counted, so the estimate is a lower bound.
+func (g *ResourceGraph) EstimateCalls(subscriptions []string, resourceGroup string, serviceScanners []IAzureScanner) (CallEstimate, error) {
+	estimate := CallEstimate{
+		Subscriptions: len(subscriptions),
+	}
+
+	resourceGroups, err := g.countResourceGroups(subscriptions, resourceGroup)
+	if err != nil {
+		return estimate, err
+	}
+	estimate.ResourceGroups = resourceGroups
+
+	types := []string{}
+	for _, s := range serviceScanners {
+		types = append(types, s.GetResourceTypes()...)
+	}
+	counts, err := g.countResourcesByType(subscriptions, resourceGroup, types)
+	if err != nil {
+		return estimate, err
+	}
+	for _, c := range counts {
+		estimate.Resources += c
+	}
+
+	estimate.Calls = estimate.Subscriptions*callsPerSubscription +
+		estimate.ResourceGroups*len(serviceScanners) +
+		estimate.Resources
+	return estimate, nil
+}
+
+func (g *ResourceGraph) countResourceGroups(subscriptions []string, resourceGroup string) (int, error) {
+	filter := ""
+	if resourceGroup != "" {
+		filter = fmt.Sprintf(" and name =~ '%s'", EscapeKQL(resourceGroup))
+	}
+	query := fmt.Sprintf("resourcecontainers | where type =~ 'microsoft.resources/subscriptions/resourcegroups'%s | summarize count=count()", filter)
+
+	rows, err := g.Query(query, subscriptions)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, row := range rows {
+		if c, ok := row["count"].(float64); ok {
+			count += int(c)
+		}
+	}
+	return count, nil
+}
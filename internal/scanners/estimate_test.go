@@ -0,0 +1,44 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEstimateCalls(t *testing.T) {
+	aks := &fakeTypedScanner{types: []string{"Microsoft.ContainerService/managedClusters"}}
+	st := &fakeTypedScanner{types: []string{"Microsoft.Storage/storageAccounts"}}
+
+	graph := ResourceGraph{
+		queryFunc: func(query string, subscriptions []string) ([]map[string]interface{}, error) {
+			if strings.HasPrefix(query, "resourcecontainers") {
+				return []map[string]interface{}{
+					{"count": float64(3)},
+				}, nil
+			}
+			return []map[string]interface{}{
+				{"type": "microsoft.containerservice/managedclusters", "count": float64(2)},
+				{"type": "microsoft.storage/storageaccounts", "count": float64(5)},
+			}, nil
+		},
+	}
+
+	got, err := graph.EstimateCalls([]string{"sub"}, "", []IAzureScanner{aks, st})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 4 subscription calls + 3 resource groups * 2 scanners + 7 resources
+	want := CallEstimate{
+		Subscriptions:  1,
+		ResourceGroups: 3,
+		Resources:      7,
+		Calls:          17,
+	}
+	if got != want {
+		t.Errorf("EstimateCalls() = %v, want %v", got, want)
+	}
+}
@@ -42,7 +42,7 @@ func (a *EventGridScanner) Scan(resourceGroupName string, scanContext *scanners.
 	if err != nil {
 		return nil, err
 	}
-	engine := scanners.RuleEngine{}
+	engine := scanners.RuleEngine{Cache: a.config.RuleCache}
 	rules := a.GetRules()
 	results := []scanners.AzureServiceResult{}
 
@@ -52,9 +52,12 @@ func (a *EventGridScanner) Scan(resourceGroupName string, scanContext *scanners.
 		results = append(results, scanners.AzureServiceResult{
 			SubscriptionID: a.config.SubscriptionID,
 			ResourceGroup:  resourceGroupName,
+			ResourceID:     *d.ID,
 			ServiceName:    *d.Name,
 			Type:           *d.Type,
 			Location:       *d.Location,
+			Tags:           d.Tags,
+			CreatedAt:      scanners.GetCreatedAt(d),
 			Rules:          rr,
 		})
 	}
@@ -72,9 +75,17 @@ func (a *EventGridScanner) listDomain(resourceGroupName string) ([]*armeventgrid
 				return nil, err
 			}
 			domains = append(domains, resp.Value...)
+			if limited, ok := scanners.LimitResources(a.config, domains); ok {
+				return limited, nil
+			}
 		}
 		return domains, nil
 	}
 
 	return a.listDomainFunc(resourceGroupName)
 }
+
+// GetResourceTypes - Returns the resource types scanned by the EventGridScanner
+func (a *EventGridScanner) GetResourceTypes() []string {
+	return []string{"Microsoft.EventGrid/domains"}
+}
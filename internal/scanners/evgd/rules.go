@@ -4,9 +4,6 @@
 package evgd
 
 import (
-	"log"
-	"strings"
-
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/eventgrid/armeventgrid"
 	"github.com/cmendible/azqr/internal/scanners"
 )
@@ -22,12 +19,7 @@ func (a *EventGridScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Medium",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				service := target.(*armeventgrid.Domain)
-				hasDiagnostics, err := a.diagnosticsSettings.HasDiagnostics(*service.ID)
-				if err != nil {
-					log.Fatalf("Error checking diagnostic settings for service %s: %s", *service.Name, err)
-				}
-
-				return !hasDiagnostics, ""
+				return a.diagnosticsSettings.EvalDiagnostics("evgd-001", *service.ID, scanContext)
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/event-grid/diagnostic-logs",
 		},
@@ -74,7 +66,7 @@ func (a *EventGridScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Low",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armeventgrid.Domain)
-				caf := strings.HasPrefix(*c.Name, "evgd")
+				caf := scanners.HasCAFPrefix(*c.Name, "evgd", scanContext)
 				return !caf, ""
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
@@ -5,27 +5,61 @@ package evh
 
 import (
 	"log"
+	"strings"
+	"sync"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/eventhub/armeventhub"
 	"github.com/cmendible/azqr/internal/scanners"
 )
 
+// DefaultConsumerGroupsThreshold - Default number of consumer groups per event hub considered too high
+const DefaultConsumerGroupsThreshold = 10
+
 // EventHubScanner - Scanner for Event Hubs
 type EventHubScanner struct {
-	config              *scanners.ScannerConfig
-	diagnosticsSettings scanners.DiagnosticsSettings
-	client              *armeventhub.NamespacesClient
-	listEventHubsFunc   func(resourceGroupName string) ([]*armeventhub.EHNamespace, error)
+	config                  *scanners.ScannerConfig
+	diagnosticsSettings     scanners.DiagnosticsSettings
+	client                  *armeventhub.NamespacesClient
+	eventHubsClient         *armeventhub.EventHubsClient
+	consumerGroupsClient    *armeventhub.ConsumerGroupsClient
+	drConfigsClient         *armeventhub.DisasterRecoveryConfigsClient
+	consumerGroupsThreshold int
+	// hubs - Event hubs of the scanned namespaces, by lower case namespace id, listed once per namespace and shared by the rules.
+	// Guarded by mu since Resource Groups may be scanned concurrently.
+	mu                      sync.RWMutex
+	hubs                    map[string][]*armeventhub.Eventhub
+	listEventHubsFunc       func(resourceGroupName string) ([]*armeventhub.EHNamespace, error)
+	listHubsFunc            func(resourceGroupName string, namespaceName string) ([]*armeventhub.Eventhub, error)
+	countConsumerGroupsFunc func(resourceGroupName string, namespaceName string, eventHubName string) (int, error)
+	listIPRulesFunc         func(resourceGroupName string, namespaceName string) ([]*armeventhub.NWRuleSetIPRules, error)
+	listDRConfigsFunc       func(resourceGroupName string, namespaceName string) ([]*armeventhub.ArmDisasterRecovery, error)
+	getLocationFunc         func(namespaceID string) (string, error)
 }
 
 // Init - Initializes the EventHubScanner
 func (a *EventHubScanner) Init(config *scanners.ScannerConfig) error {
 	a.config = config
+	// The event hubs listed by Scan belong to the subscription of the previous config
+	a.hubs = nil
 	var err error
 	a.client, err = armeventhub.NewNamespacesClient(config.SubscriptionID, config.Cred, config.ClientOptions)
 	if err != nil {
 		return err
 	}
+	a.eventHubsClient, err = armeventhub.NewEventHubsClient(config.SubscriptionID, config.Cred, config.ClientOptions)
+	if err != nil {
+		return err
+	}
+	a.consumerGroupsClient, err = armeventhub.NewConsumerGroupsClient(config.SubscriptionID, config.Cred, config.ClientOptions)
+	if err != nil {
+		return err
+	}
+	a.drConfigsClient, err = armeventhub.NewDisasterRecoveryConfigsClient(config.SubscriptionID, config.Cred, config.ClientOptions)
+	if err != nil {
+		return err
+	}
+	a.consumerGroupsThreshold = config.ConsumerGroupsThreshold
 	a.diagnosticsSettings = scanners.DiagnosticsSettings{}
 	err = a.diagnosticsSettings.Init(config)
 	if err != nil {
@@ -42,19 +76,35 @@ func (c *EventHubScanner) Scan(resourceGroupName string, scanContext *scanners.S
 	if err != nil {
 		return nil, err
 	}
-	engine := scanners.RuleEngine{}
+	engine := scanners.RuleEngine{Cache: c.config.RuleCache}
 	rules := c.GetRules()
 	results := []scanners.AzureServiceResult{}
 
+	for _, eventHub := range eventHubs {
+		hubs, err := c.listHubs(resourceGroupName, *eventHub.Name)
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		if c.hubs == nil {
+			c.hubs = map[string][]*armeventhub.Eventhub{}
+		}
+		c.hubs[strings.ToLower(*eventHub.ID)] = hubs
+		c.mu.Unlock()
+	}
+
 	for _, eventHub := range eventHubs {
 		rr := engine.EvaluateRules(rules, eventHub, scanContext)
 
 		results = append(results, scanners.AzureServiceResult{
 			SubscriptionID: c.config.SubscriptionID,
 			ResourceGroup:  resourceGroupName,
+			ResourceID:     *eventHub.ID,
 			ServiceName:    *eventHub.Name,
 			Type:           *eventHub.Type,
 			Location:       *eventHub.Location,
+			Tags:           eventHub.Tags,
+			CreatedAt:      scanners.GetCreatedAt(eventHub),
 			Rules:          rr,
 		})
 	}
@@ -72,9 +122,126 @@ func (c *EventHubScanner) listEventHubs(resourceGroupName string) ([]*armeventhu
 				return nil, err
 			}
 			namespaces = append(namespaces, resp.Value...)
+			if limited, ok := scanners.LimitResources(c.config, namespaces); ok {
+				return limited, nil
+			}
 		}
 		return namespaces, nil
 	}
 
 	return c.listEventHubsFunc(resourceGroupName)
 }
+
+// listHubs - Returns the event hubs of the namespace
+func (c *EventHubScanner) listHubs(resourceGroupName string, namespaceName string) ([]*armeventhub.Eventhub, error) {
+	if c.listHubsFunc == nil {
+		hubs := []*armeventhub.Eventhub{}
+		pager := c.eventHubsClient.NewListByNamespacePager(resourceGroupName, namespaceName, nil)
+		for pager.More() {
+			resp, err := pager.NextPage(c.config.Ctx)
+			if err != nil {
+				return nil, err
+			}
+			hubs = append(hubs, resp.Value...)
+		}
+		return hubs, nil
+	}
+
+	return c.listHubsFunc(resourceGroupName, namespaceName)
+}
+
+// countConsumerGroups - Returns the number of consumer groups of an event hub
+func (c *EventHubScanner) countConsumerGroups(resourceGroupName string, namespaceName string, eventHubName string) (int, error) {
+	if c.countConsumerGroupsFunc == nil {
+		count := 0
+		pager := c.consumerGroupsClient.NewListByEventHubPager(resourceGroupName, namespaceName, eventHubName, nil)
+		for pager.More() {
+			resp, err := pager.NextPage(c.config.Ctx)
+			if err != nil {
+				return 0, err
+			}
+			count += len(resp.Value)
+		}
+		return count, nil
+	}
+
+	return c.countConsumerGroupsFunc(resourceGroupName, namespaceName, eventHubName)
+}
+
+// getConsumerGroupsThreshold - Returns the number of consumer groups per event hub considered too high
+func (c *EventHubScanner) getConsumerGroupsThreshold() int {
+	if c.consumerGroupsThreshold <= 0 {
+		return DefaultConsumerGroupsThreshold
+	}
+	return c.consumerGroupsThreshold
+}
+
+// getHubs - Returns the event hubs of the namespace listed by Scan
+func (c *EventHubScanner) getHubs(namespaceID string) []*armeventhub.Eventhub {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.hubs[strings.ToLower(namespaceID)]
+}
+
+// listIPRules - Returns the IP rules of the network rule set of the namespace
+func (c *EventHubScanner) listIPRules(resourceGroupName string, namespaceName string) ([]*armeventhub.NWRuleSetIPRules, error) {
+	if c.listIPRulesFunc == nil {
+		resp, err := c.client.GetNetworkRuleSet(c.config.Ctx, resourceGroupName, namespaceName, nil)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Properties == nil {
+			return []*armeventhub.NWRuleSetIPRules{}, nil
+		}
+		return resp.Properties.IPRules, nil
+	}
+
+	return c.listIPRulesFunc(resourceGroupName, namespaceName)
+}
+
+// listDRConfigs - Returns the Geo-disaster recovery configurations of the namespace
+func (c *EventHubScanner) listDRConfigs(resourceGroupName string, namespaceName string) ([]*armeventhub.ArmDisasterRecovery, error) {
+	if c.listDRConfigsFunc == nil {
+		pager := c.drConfigsClient.NewListPager(resourceGroupName, namespaceName, nil)
+		results := []*armeventhub.ArmDisasterRecovery{}
+		for pager.More() {
+			resp, err := pager.NextPage(c.config.Ctx)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, resp.Value...)
+		}
+		return results, nil
+	}
+
+	return c.listDRConfigsFunc(resourceGroupName, namespaceName)
+}
+
+// getLocation - Returns the location of the namespace with the given id, which can be in another subscription
+func (c *EventHubScanner) getLocation(namespaceID string) (string, error) {
+	if c.getLocationFunc == nil {
+		resource, err := arm.ParseResourceID(namespaceID)
+		if err != nil {
+			return "", err
+		}
+		client, err := armeventhub.NewNamespacesClient(resource.SubscriptionID, c.config.Cred, c.config.ClientOptions)
+		if err != nil {
+			return "", err
+		}
+		resp, err := client.Get(c.config.Ctx, resource.ResourceGroupName, resource.Name, nil)
+		if err != nil {
+			return "", err
+		}
+		if resp.Location == nil {
+			return "", nil
+		}
+		return *resp.Location, nil
+	}
+
+	return c.getLocationFunc(namespaceID)
+}
+
+// GetResourceTypes - Returns the resource types scanned by the EventHubScanner
+func (c *EventHubScanner) GetResourceTypes() []string {
+	return []string{"Microsoft.EventHub/namespaces"}
+}
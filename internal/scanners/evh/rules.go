@@ -4,13 +4,18 @@
 package evh
 
 import (
-	"log"
+	"fmt"
+	"net"
 	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/eventhub/armeventhub"
 	"github.com/cmendible/azqr/internal/scanners"
 )
 
+// broadIPRangePrefixLength - IP rules with this prefix length or shorter (e.g. 0.0.0.0/0 or 10.0.0.0/8) are considered overly broad
+const broadIPRangePrefixLength = 8
+
 // GetRules - Returns the rules for the EventHubScanner
 func (a *EventHubScanner) GetRules() map[string]scanners.AzureRule {
 	return map[string]scanners.AzureRule{
@@ -22,12 +27,7 @@ func (a *EventHubScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Medium",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				service := target.(*armeventhub.EHNamespace)
-				hasDiagnostics, err := a.diagnosticsSettings.HasDiagnostics(*service.ID)
-				if err != nil {
-					log.Fatalf("Error checking diagnostic settings for service %s: %s", *service.Name, err)
-				}
-
-				return !hasDiagnostics, ""
+				return a.diagnosticsSettings.EvalDiagnostics("evh-001", *service.ID, scanContext)
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/event-hubs/monitor-event-hubs#collection-and-routing",
 		},
@@ -94,7 +94,7 @@ func (a *EventHubScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Low",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armeventhub.EHNamespace)
-				caf := strings.HasPrefix(*c.Name, "evh")
+				caf := scanners.HasCAFPrefix(*c.Name, "evh", scanContext)
 				return !caf, ""
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
@@ -123,5 +123,234 @@ func (a *EventHubScanner) GetRules() map[string]scanners.AzureRule {
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/event-hubs/authorize-access-event-hubs#shared-access-signatures",
 		},
+		"evh-016": {
+			Id:          "evh-016",
+			Category:    "Security",
+			Subcategory: "Encryption",
+			Description: "Event Hub Premium or Dedicated Namespace should use customer-managed keys",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armeventhub.EHNamespace)
+				premium := c.SKU != nil && c.SKU.Name != nil && *c.SKU.Name == armeventhub.SKUNamePremium
+				dedicated := c.Properties != nil && c.Properties.ClusterArmID != nil && *c.Properties.ClusterArmID != ""
+				cmk := c.Properties != nil && c.Properties.Encryption != nil && c.Properties.Encryption.KeySource != nil && *c.Properties.Encryption.KeySource == "Microsoft.KeyVault"
+
+				keys := "Microsoft-managed"
+				if cmk {
+					keys = "Customer-managed"
+				}
+				return (premium || dedicated) && !cmk, keys
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/event-hubs/configure-customer-managed-key",
+		},
+		"evh-017": {
+			Id:          "evh-017",
+			Category:    "High Availability and Resiliency",
+			Subcategory: "Availability Zones",
+			Description: "Event Hub Premium Namespace should be zone redundant",
+			Severity:    "High",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armeventhub.EHNamespace)
+				sku := ""
+				if c.SKU != nil && c.SKU.Name != nil {
+					sku = string(*c.SKU.Name)
+				}
+				if sku != string(armeventhub.SKUNamePremium) {
+					return false, sku + ", Zone Redundancy not supported"
+				}
+
+				zoneRedundant := c.Properties != nil && c.Properties.ZoneRedundant != nil && *c.Properties.ZoneRedundant
+				if !zoneRedundant {
+					return true, sku + ", Not Zone Redundant"
+				}
+				return false, sku + ", Zone Redundant"
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/event-hubs/event-hubs-premium-overview#high-availability-with-availability-zones",
+		},
+		"evh-018": {
+			Id:          "evh-018",
+			Category:    "Operations",
+			Subcategory: "Best Practices",
+			Description: "Event Hub should not have an unusually high number of consumer groups",
+			Severity:    "Low",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armeventhub.EHNamespace)
+				resource, err := arm.ParseResourceID(*c.ID)
+				if err != nil {
+					return scanContext.RuleError("evh-018", *c.ID, err)
+				}
+				offending := []string{}
+				for _, hub := range a.getHubs(*c.ID) {
+					count, err := a.countConsumerGroups(resource.ResourceGroupName, *c.Name, *hub.Name)
+					if err != nil {
+						return scanContext.RuleError("evh-018", *c.ID, fmt.Errorf("listing consumer groups: %w", err))
+					}
+					if count > a.getConsumerGroupsThreshold() {
+						offending = append(offending, fmt.Sprintf("%s: %d", *hub.Name, count))
+					}
+				}
+
+				return len(offending) > 0, strings.Join(offending, ", ")
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/event-hubs/event-hubs-features#consumer-groups",
+		},
+		"evh-019": {
+			Id:          "evh-019",
+			Category:    "Security",
+			Subcategory: "Identity and Access Control",
+			Description: "Event Hub capture should use a managed identity to access the destination storage",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armeventhub.EHNamespace)
+				broken := false
+				modes := []string{}
+				for _, hub := range a.getHubs(*c.ID) {
+					if hub.Properties == nil || hub.Properties.CaptureDescription == nil ||
+						hub.Properties.CaptureDescription.Enabled == nil || !*hub.Properties.CaptureDescription.Enabled {
+						continue
+					}
+					mode := "Access Key"
+					destination := hub.Properties.CaptureDescription.Destination
+					if destination != nil && destination.Identity != nil && destination.Identity.Type != nil {
+						mode = string(*destination.Identity.Type)
+					} else {
+						broken = true
+					}
+					modes = append(modes, fmt.Sprintf("%s: %s", *hub.Name, mode))
+				}
+				return broken, strings.Join(modes, ", ")
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/event-hubs/event-hubs-capture-managed-identity",
+		},
+		"evh-020": {
+			Id:          "evh-020",
+			Category:    "High Availability and Resiliency",
+			Subcategory: "Auto-Inflate",
+			Description: "Event Hub Standard Namespace with auto-inflate should allow scaling above the current throughput units",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armeventhub.EHNamespace)
+				standard := c.SKU != nil && c.SKU.Name != nil && *c.SKU.Name == armeventhub.SKUNameStandard
+				autoInflate := c.Properties != nil && c.Properties.IsAutoInflateEnabled != nil && *c.Properties.IsAutoInflateEnabled
+				if !standard || !autoInflate {
+					return false, ""
+				}
+
+				if c.Properties.MaximumThroughputUnits == nil {
+					return true, "Maximum Throughput Units not set"
+				}
+				maximum := *c.Properties.MaximumThroughputUnits
+				current := int32(1)
+				if c.SKU.Capacity != nil {
+					current = *c.SKU.Capacity
+				}
+				return maximum <= current, fmt.Sprintf("%d Maximum Throughput Units", maximum)
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/event-hubs/event-hubs-auto-inflate",
+		},
+		"evh-021": {
+			Id:          "evh-021",
+			Category:    "Governance",
+			Subcategory: "Cost Optimization",
+			Description: "Event Hub Namespace dedicated cluster usage should be reviewed for cost and isolation",
+			Severity:    "Low",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armeventhub.EHNamespace)
+				if c.Properties == nil || c.Properties.ClusterArmID == nil || *c.Properties.ClusterArmID == "" {
+					return false, "Not in a dedicated cluster"
+				}
+
+				cluster := *c.Properties.ClusterArmID
+				if id, err := arm.ParseResourceID(cluster); err == nil {
+					cluster = id.Name
+				}
+				return false, fmt.Sprintf("Dedicated cluster %s", cluster)
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/event-hubs/event-hubs-dedicated-overview",
+		},
+		"evh-022": {
+			Id:          "evh-022",
+			Category:    "Security",
+			Subcategory: "Networking",
+			Description: "Event Hub Namespace network rule set should not allow overly broad IP ranges",
+			Severity:    "High",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armeventhub.EHNamespace)
+				resource, err := arm.ParseResourceID(*c.ID)
+				if err != nil {
+					return scanContext.RuleError("evh-022", *c.ID, err)
+				}
+				rules, err := a.listIPRules(resource.ResourceGroupName, *c.Name)
+				if err != nil {
+					return scanContext.RuleError("evh-022", *c.ID, fmt.Errorf("getting network rule set: %w", err))
+				}
+
+				broad := []string{}
+				for _, r := range rules {
+					if r.IPMask == nil || (r.Action != nil && *r.Action != armeventhub.NetworkRuleIPActionAllow) {
+						continue
+					}
+					mask := *r.IPMask
+					if !strings.Contains(mask, "/") {
+						continue
+					}
+					_, ipNet, err := net.ParseCIDR(mask)
+					if err != nil {
+						continue
+					}
+					if ones, _ := ipNet.Mask.Size(); ones <= broadIPRangePrefixLength {
+						broad = append(broad, mask)
+					}
+				}
+				return len(broad) > 0, strings.Join(broad, ", ")
+			},
+			CacheKey: func(target interface{}, scanContext *scanners.ScanContext) interface{} {
+				return target.(*armeventhub.EHNamespace).ID
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/event-hubs/network-security",
+		},
+		"evh-023": {
+			Id:          "evh-023",
+			Category:    "High Availability and Resiliency",
+			Subcategory: "Reliability",
+			Description: "Event Hub Geo-disaster recovery should pair the namespace with a namespace in its Azure paired region",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armeventhub.EHNamespace)
+				resource, err := arm.ParseResourceID(*c.ID)
+				if err != nil {
+					return scanContext.RuleError("evh-023", *c.ID, err)
+				}
+				configs, err := a.listDRConfigs(resource.ResourceGroupName, *c.Name)
+				if err != nil {
+					return scanContext.RuleError("evh-023", *c.ID, fmt.Errorf("listing disaster recovery configurations: %w", err))
+				}
+
+				broken := false
+				partners := []string{}
+				for _, config := range configs {
+					if config.Properties == nil || config.Properties.PartnerNamespace == nil || *config.Properties.PartnerNamespace == "" {
+						continue
+					}
+					location, err := a.getLocation(*config.Properties.PartnerNamespace)
+					if err != nil {
+						return scanContext.RuleError("evh-023", *c.ID, fmt.Errorf("getting partner namespace %s: %w", *config.Properties.PartnerNamespace, err))
+					}
+					pair := "standard pair"
+					if !scanners.IsPairedRegion(*c.Location, location) {
+						broken = true
+						pair = "not a standard pair"
+					}
+					partners = append(partners, fmt.Sprintf("%s (%s)", scanners.NormalizeLocation(location), pair))
+				}
+				return broken, strings.Join(partners, ", ")
+			},
+			CacheKey: func(target interface{}, scanContext *scanners.ScanContext) interface{} {
+				c := target.(*armeventhub.EHNamespace)
+				return []interface{}{c.ID, c.Location}
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/event-hubs/event-hubs-geo-dr",
+		},
+		"evh-024": scanners.NewPublicNetworkAccessRule("evh-024", "Event Hub Namespace", "https://learn.microsoft.com/en-us/azure/event-hubs/network-security"),
 	}
 }
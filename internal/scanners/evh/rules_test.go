@@ -168,6 +168,267 @@ func TestEventHubScanner_Rules(t *testing.T) {
 				result: "",
 			},
 		},
+		{
+			name: "EventHubScanner public network access enabled",
+			fields: fields{
+				rule: "evh-024",
+				target: &armeventhub.EHNamespace{
+					Properties: &armeventhub.EHNamespaceProperties{
+						PublicNetworkAccess: getPublicNetworkAccess(armeventhub.PublicNetworkAccessEnabled),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "Enabled",
+			},
+		},
+		{
+			name: "EventHubScanner public network access disabled",
+			fields: fields{
+				rule: "evh-024",
+				target: &armeventhub.EHNamespace{
+					Properties: &armeventhub.EHNamespaceProperties{
+						PublicNetworkAccess: getPublicNetworkAccess(armeventhub.PublicNetworkAccessDisabled),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "Disabled",
+			},
+		},
+		{
+			name: "EventHubScanner Premium with CMK",
+			fields: fields{
+				rule: "evh-016",
+				target: &armeventhub.EHNamespace{
+					SKU: &armeventhub.SKU{
+						Name: getSKUNamePremium(),
+					},
+					Properties: &armeventhub.EHNamespaceProperties{
+						Encryption: &armeventhub.Encryption{
+							KeySource: to.StringPtr("Microsoft.KeyVault"),
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "Customer-managed",
+			},
+		},
+		{
+			name: "EventHubScanner Premium with Microsoft-managed keys",
+			fields: fields{
+				rule: "evh-016",
+				target: &armeventhub.EHNamespace{
+					SKU: &armeventhub.SKU{
+						Name: getSKUNamePremium(),
+					},
+					Properties: &armeventhub.EHNamespaceProperties{
+						ZoneRedundant: to.BoolPtr(true),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "Microsoft-managed",
+			},
+		},
+		{
+			name: "EventHubScanner Standard with Microsoft-managed keys",
+			fields: fields{
+				rule: "evh-016",
+				target: &armeventhub.EHNamespace{
+					SKU: &armeventhub.SKU{
+						Name: getSKUNameStandard(),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "Microsoft-managed",
+			},
+		},
+		{
+			name: "EventHubScanner Premium not zone redundant",
+			fields: fields{
+				rule: "evh-017",
+				target: &armeventhub.EHNamespace{
+					SKU: &armeventhub.SKU{
+						Name: getSKUNamePremium(),
+					},
+					Properties: &armeventhub.EHNamespaceProperties{
+						ZoneRedundant: to.BoolPtr(false),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "Premium, Not Zone Redundant",
+			},
+		},
+		{
+			name: "EventHubScanner Premium zone redundant",
+			fields: fields{
+				rule: "evh-017",
+				target: &armeventhub.EHNamespace{
+					SKU: &armeventhub.SKU{
+						Name: getSKUNamePremium(),
+					},
+					Properties: &armeventhub.EHNamespaceProperties{
+						ZoneRedundant: to.BoolPtr(true),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "Premium, Zone Redundant",
+			},
+		},
+		{
+			name: "EventHubScanner Basic zone redundancy not supported",
+			fields: fields{
+				rule: "evh-017",
+				target: &armeventhub.EHNamespace{
+					SKU: &armeventhub.SKU{
+						Name: getSKUNameBasic(),
+					},
+					Properties: &armeventhub.EHNamespaceProperties{
+						ZoneRedundant: to.BoolPtr(false),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "Basic, Zone Redundancy not supported",
+			},
+		},
+		{
+			name: "EventHubScanner Standard auto-inflate with low cap",
+			fields: fields{
+				rule: "evh-020",
+				target: &armeventhub.EHNamespace{
+					SKU: &armeventhub.SKU{
+						Name:     getSKUNameStandard(),
+						Capacity: to.Int32Ptr(2),
+					},
+					Properties: &armeventhub.EHNamespaceProperties{
+						IsAutoInflateEnabled:   to.BoolPtr(true),
+						MaximumThroughputUnits: to.Int32Ptr(2),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "2 Maximum Throughput Units",
+			},
+		},
+		{
+			name: "EventHubScanner Standard auto-inflate with high cap",
+			fields: fields{
+				rule: "evh-020",
+				target: &armeventhub.EHNamespace{
+					SKU: &armeventhub.SKU{
+						Name:     getSKUNameStandard(),
+						Capacity: to.Int32Ptr(2),
+					},
+					Properties: &armeventhub.EHNamespaceProperties{
+						IsAutoInflateEnabled:   to.BoolPtr(true),
+						MaximumThroughputUnits: to.Int32Ptr(20),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "20 Maximum Throughput Units",
+			},
+		},
+		{
+			name: "EventHubScanner Standard without auto-inflate",
+			fields: fields{
+				rule: "evh-020",
+				target: &armeventhub.EHNamespace{
+					SKU: &armeventhub.SKU{
+						Name: getSKUNameStandard(),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "EventHubScanner namespace in a dedicated cluster",
+			fields: fields{
+				rule: "evh-021",
+				target: &armeventhub.EHNamespace{
+					Properties: &armeventhub.EHNamespaceProperties{
+						ClusterArmID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.EventHub/clusters/evhc-prod"),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "Dedicated cluster evhc-prod",
+			},
+		},
+		{
+			name: "EventHubScanner namespace not in a dedicated cluster",
+			fields: fields{
+				rule: "evh-021",
+				target: &armeventhub.EHNamespace{
+					Properties: &armeventhub.EHNamespaceProperties{
+						ZoneRedundant: to.BoolPtr(true),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "Not in a dedicated cluster",
+			},
+		},
+		{
+			name: "EventHubScanner namespace without properties",
+			fields: fields{
+				rule:                "evh-021",
+				target:              &armeventhub.EHNamespace{},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "Not in a dedicated cluster",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -196,3 +457,351 @@ func getSKUNamePremium() *armeventhub.SKUName {
 	s := armeventhub.SKUNamePremium
 	return &s
 }
+
+func getSKUNameBasic() *armeventhub.SKUName {
+	s := armeventhub.SKUNameBasic
+	return &s
+}
+
+func TestEventHubScanner_ConsumerGroupsRule(t *testing.T) {
+	type want struct {
+		broken bool
+		result string
+	}
+	tests := []struct {
+		name   string
+		counts map[string]int
+		want   want
+	}{
+		{
+			name:   "EventHubScanner consumer groups below threshold",
+			counts: map[string]int{"orders": 3, "payments": 10},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name:   "EventHubScanner consumer groups above threshold",
+			counts: map[string]int{"orders": 15, "payments": 10},
+			want: want{
+				broken: true,
+				result: "orders: 15",
+			},
+		},
+		{
+			name:   "EventHubScanner consumer groups above threshold in several event hubs",
+			counts: map[string]int{"orders": 15, "payments": 12},
+			want: want{
+				broken: true,
+				result: "orders: 15, payments: 12",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &EventHubScanner{
+				consumerGroupsThreshold: 10,
+				hubs: map[string][]*armeventhub.Eventhub{
+					"/subscriptions/sub/resourcegroups/rg/providers/microsoft.eventhub/namespaces/evh-test": {
+						{Name: to.StringPtr("orders")},
+						{Name: to.StringPtr("payments")},
+					},
+				},
+				countConsumerGroupsFunc: func(resourceGroupName string, namespaceName string, eventHubName string) (int, error) {
+					if resourceGroupName != "rg" || namespaceName != "evh-test" {
+						t.Errorf("unexpected namespace %s/%s", resourceGroupName, namespaceName)
+					}
+					return tt.counts[eventHubName], nil
+				},
+			}
+			rules := s.GetRules()
+			b, w := rules["evh-018"].Eval(&armeventhub.EHNamespace{
+				ID:   to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.EventHub/namespaces/evh-test"),
+				Name: to.StringPtr("evh-test"),
+			}, &scanners.ScanContext{})
+			got := want{
+				broken: b,
+				result: w,
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EventHubScanner Rule.Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventHubScanner_CaptureIdentityRule(t *testing.T) {
+	type want struct {
+		broken bool
+		result string
+	}
+	tests := []struct {
+		name        string
+		destination *armeventhub.Destination
+		want        want
+	}{
+		{
+			name: "EventHubScanner capture with managed identity",
+			destination: &armeventhub.Destination{
+				Identity: &armeventhub.CaptureIdentity{
+					Type: getCaptureIdentityType(armeventhub.CaptureIdentityTypeSystemAssigned),
+				},
+			},
+			want: want{
+				broken: false,
+				result: "orders: SystemAssigned",
+			},
+		},
+		{
+			name:        "EventHubScanner capture with access key",
+			destination: &armeventhub.Destination{},
+			want: want{
+				broken: true,
+				result: "orders: Access Key",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &EventHubScanner{
+				hubs: map[string][]*armeventhub.Eventhub{
+					"/subscriptions/sub/resourcegroups/rg/providers/microsoft.eventhub/namespaces/evh-test": {
+						{
+							Name: to.StringPtr("audit"),
+						},
+						{
+							Name: to.StringPtr("orders"),
+							Properties: &armeventhub.Properties{
+								CaptureDescription: &armeventhub.CaptureDescription{
+									Enabled:     to.BoolPtr(true),
+									Destination: tt.destination,
+								},
+							},
+						},
+					},
+				},
+			}
+			rules := s.GetRules()
+			b, w := rules["evh-019"].Eval(&armeventhub.EHNamespace{
+				ID:   to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.EventHub/namespaces/evh-test"),
+				Name: to.StringPtr("evh-test"),
+			}, &scanners.ScanContext{})
+			got := want{
+				broken: b,
+				result: w,
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EventHubScanner Rule.Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventHubScanner_ScanListsHubsOnce(t *testing.T) {
+	calls := 0
+	s := &EventHubScanner{
+		config: &scanners.ScannerConfig{SubscriptionID: "sub"},
+		diagnosticsSettings: scanners.DiagnosticsSettings{
+			HasDiagnosticsFunc: func(resourceId string) (bool, error) {
+				return true, nil
+			},
+		},
+		listEventHubsFunc: func(resourceGroupName string) ([]*armeventhub.EHNamespace, error) {
+			return []*armeventhub.EHNamespace{
+				{
+					ID:       to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.EventHub/namespaces/evh-test"),
+					Name:     to.StringPtr("evh-test"),
+					Type:     to.StringPtr("Microsoft.EventHub/namespaces"),
+					Location: to.StringPtr("westeurope"),
+					SKU: &armeventhub.SKU{
+						Name:     getSKUNameStandard(),
+						Capacity: to.Int32Ptr(1),
+					},
+					Properties: &armeventhub.EHNamespaceProperties{
+						ZoneRedundant: to.BoolPtr(true),
+					},
+				},
+			}, nil
+		},
+		listHubsFunc: func(resourceGroupName string, namespaceName string) ([]*armeventhub.Eventhub, error) {
+			calls++
+			return []*armeventhub.Eventhub{{Name: to.StringPtr("orders")}}, nil
+		},
+		countConsumerGroupsFunc: func(resourceGroupName string, namespaceName string, eventHubName string) (int, error) {
+			return 1, nil
+		},
+		listIPRulesFunc: func(resourceGroupName string, namespaceName string) ([]*armeventhub.NWRuleSetIPRules, error) {
+			return nil, nil
+		},
+		listDRConfigsFunc: func(resourceGroupName string, namespaceName string) ([]*armeventhub.ArmDisasterRecovery, error) {
+			return nil, nil
+		},
+	}
+
+	scanContext := &scanners.ScanContext{}
+	_, err := s.Scan("rg", scanContext)
+	if err != nil {
+		t.Fatalf("EventHubScanner.Scan() error = %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("EventHubScanner.Scan() listed event hubs %d times, want 1", calls)
+	}
+	if len(s.hubs) != 1 {
+		t.Errorf("EventHubScanner.Scan() shared %d namespaces, want 1", len(s.hubs))
+	}
+}
+
+func getCaptureIdentityType(t armeventhub.CaptureIdentityType) *armeventhub.CaptureIdentityType {
+	return &t
+}
+
+func TestEventHubScanner_IPRulesRule(t *testing.T) {
+	type want struct {
+		broken bool
+		result string
+	}
+	tests := []struct {
+		name  string
+		rules []*armeventhub.NWRuleSetIPRules
+		want  want
+	}{
+		{
+			name: "EventHubScanner single IP allowed",
+			rules: []*armeventhub.NWRuleSetIPRules{
+				{IPMask: to.StringPtr("203.0.113.10/32"), Action: getNetworkRuleIPAction(armeventhub.NetworkRuleIPActionAllow)},
+				{IPMask: to.StringPtr("203.0.113.11")},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "EventHubScanner any IP allowed",
+			rules: []*armeventhub.NWRuleSetIPRules{
+				{IPMask: to.StringPtr("203.0.113.10/32"), Action: getNetworkRuleIPAction(armeventhub.NetworkRuleIPActionAllow)},
+				{IPMask: to.StringPtr("0.0.0.0/0"), Action: getNetworkRuleIPAction(armeventhub.NetworkRuleIPActionAllow)},
+			},
+			want: want{
+				broken: true,
+				result: "0.0.0.0/0",
+			},
+		},
+		{
+			name:  "EventHubScanner without IP rules",
+			rules: []*armeventhub.NWRuleSetIPRules{},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &EventHubScanner{
+				listIPRulesFunc: func(resourceGroupName string, namespaceName string) ([]*armeventhub.NWRuleSetIPRules, error) {
+					return tt.rules, nil
+				},
+			}
+			rules := s.GetRules()
+			b, w := rules["evh-022"].Eval(&armeventhub.EHNamespace{
+				ID:   to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.EventHub/namespaces/evh-test"),
+				Name: to.StringPtr("evh-test"),
+			}, &scanners.ScanContext{})
+			got := want{
+				broken: b,
+				result: w,
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EventHubScanner Rule.Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventHubScanner_GeoPairRule(t *testing.T) {
+	partnerID := "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.EventHub/namespaces/evh-partner"
+	type want struct {
+		broken bool
+		result string
+	}
+	tests := []struct {
+		name     string
+		configs  []*armeventhub.ArmDisasterRecovery
+		location string
+		want     want
+	}{
+		{
+			name: "EventHubScanner paired with its paired region",
+			configs: []*armeventhub.ArmDisasterRecovery{
+				{
+					Properties: &armeventhub.ArmDisasterRecoveryProperties{
+						PartnerNamespace: to.StringPtr(partnerID),
+					},
+				},
+			},
+			location: "North Europe",
+			want: want{
+				broken: false,
+				result: "northeurope (standard pair)",
+			},
+		},
+		{
+			name: "EventHubScanner paired with a non paired region",
+			configs: []*armeventhub.ArmDisasterRecovery{
+				{
+					Properties: &armeventhub.ArmDisasterRecoveryProperties{
+						PartnerNamespace: to.StringPtr(partnerID),
+					},
+				},
+			},
+			location: "eastus",
+			want: want{
+				broken: true,
+				result: "eastus (not a standard pair)",
+			},
+		},
+		{
+			name:    "EventHubScanner without Geo-disaster recovery",
+			configs: []*armeventhub.ArmDisasterRecovery{},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &EventHubScanner{
+				listDRConfigsFunc: func(resourceGroupName string, namespaceName string) ([]*armeventhub.ArmDisasterRecovery, error) {
+					return tt.configs, nil
+				},
+				getLocationFunc: func(namespaceID string) (string, error) {
+					return tt.location, nil
+				},
+			}
+			rules := s.GetRules()
+			b, w := rules["evh-023"].Eval(&armeventhub.EHNamespace{
+				ID:       to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.EventHub/namespaces/evh-test"),
+				Name:     to.StringPtr("evh-test"),
+				Location: to.StringPtr("westeurope"),
+			}, &scanners.ScanContext{})
+			got := want{
+				broken: b,
+				result: w,
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EventHubScanner Rule.Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func getNetworkRuleIPAction(a armeventhub.NetworkRuleIPAction) *armeventhub.NetworkRuleIPAction {
+	return &a
+}
+
+func getPublicNetworkAccess(access armeventhub.PublicNetworkAccess) *armeventhub.PublicNetworkAccess {
+	return &access
+}
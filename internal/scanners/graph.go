@@ -0,0 +1,242 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcegraph/armresourcegraph"
+)
+
+// ResourceGraph - Azure Resource Graph client used for fast inventory queries
+type ResourceGraph struct {
+	config    *ScannerConfig
+	client    *armresourcegraph.Client
+	queryFunc func(query string, subscriptions []string) ([]map[string]interface{}, error)
+}
+
+// Init - Initializes the ResourceGraph client
+func (g *ResourceGraph) Init(config *ScannerConfig) error {
+	g.config = config
+	var err error
+	g.client, err = armresourcegraph.NewClient(config.Cred, config.ClientOptions)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Query - Runs a Resource Graph query against the given subscriptions and returns all rows
+func (g *ResourceGraph) Query(query string, subscriptions []string) ([]map[string]interface{}, error) {
+	if g.queryFunc == nil {
+		subs := make([]*string, 0, len(subscriptions))
+		for _, s := range subscriptions {
+			subs = append(subs, to.Ptr(s))
+		}
+
+		format := armresourcegraph.ResultFormatObjectArray
+		request := armresourcegraph.QueryRequest{
+			Query:         to.Ptr(query),
+			Subscriptions: subs,
+			Options: &armresourcegraph.QueryRequestOptions{
+				ResultFormat: &format,
+			},
+		}
+
+		rows := []map[string]interface{}{}
+		for {
+			resp, err := g.client.Resources(g.config.Ctx, request, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			data, ok := resp.Data.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("unexpected resource graph response format")
+			}
+			for _, d := range data {
+				if row, ok := d.(map[string]interface{}); ok {
+					rows = append(rows, row)
+				}
+			}
+
+			if resp.SkipToken == nil || *resp.SkipToken == "" {
+				break
+			}
+			request.Options.SkipToken = resp.SkipToken
+		}
+		return rows, nil
+	}
+
+	return g.queryFunc(query, subscriptions)
+}
+
+// CountResourcesByType - Returns the number of resources of each type. Types are returned in lower case.
+func (g *ResourceGraph) CountResourcesByType(subscriptions []string, resourceTypes []string) (map[string]int, error) {
+	return g.countResourcesByType(subscriptions, "", resourceTypes)
+}
+
+// countResourcesByType - Returns the number of resources of each type, only in the resource group if one is given
+func (g *ResourceGraph) countResourcesByType(subscriptions []string, resourceGroup string, resourceTypes []string) (map[string]int, error) {
+	types := make([]string, 0, len(resourceTypes))
+	for _, t := range resourceTypes {
+		types = append(types, fmt.Sprintf("'%s'", strings.ToLower(t)))
+	}
+
+	filter := ""
+	if resourceGroup != "" {
+		filter = fmt.Sprintf(" and resourceGroup =~ '%s'", EscapeKQL(resourceGroup))
+	}
+	query := fmt.Sprintf("resources | where type in~ (%s)%s | summarize count=count() by type=tolower(type)", strings.Join(types, ","), filter)
+
+	rows, err := g.Query(query, subscriptions)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{}
+	for _, row := range rows {
+		t, ok := row["type"].(string)
+		if !ok {
+			continue
+		}
+		c, ok := row["count"].(float64)
+		if !ok {
+			continue
+		}
+		counts[strings.ToLower(t)] += int(c)
+	}
+	return counts, nil
+}
+
+// FilterScannersWithResources - Returns only the scanners with at least one resource in the counts returned by CountResourcesByType
+func FilterScannersWithResources(serviceScanners []IAzureScanner, counts map[string]int) []IAzureScanner {
+	filtered := []IAzureScanner{}
+	for _, s := range serviceScanners {
+		for _, t := range s.GetResourceTypes() {
+			if counts[strings.ToLower(t)] > 0 {
+				filtered = append(filtered, s)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// TaggedResource - A resource returned by ListResourcesByTag
+type TaggedResource struct {
+	ID             string
+	Type           string
+	ResourceGroup  string
+	SubscriptionID string
+}
+
+// ListResourcesByTag - Returns the resources, of any type, with the given tag. An empty value matches any value.
+func (g *ResourceGraph) ListResourcesByTag(subscriptions []string, tagName, tagValue string) ([]TaggedResource, error) {
+	filter := fmt.Sprintf("isnotempty(tags['%s'])", EscapeKQL(tagName))
+	if tagValue != "" {
+		filter = fmt.Sprintf("tags['%s'] =~ '%s'", EscapeKQL(tagName), EscapeKQL(tagValue))
+	}
+	query := fmt.Sprintf("resources | where %s | project id, type, resourceGroup, subscriptionId", filter)
+
+	rows, err := g.Query(query, subscriptions)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := []TaggedResource{}
+	for _, row := range rows {
+		r := TaggedResource{}
+		r.ID, _ = row["id"].(string)
+		r.Type, _ = row["type"].(string)
+		r.ResourceGroup, _ = row["resourceGroup"].(string)
+		r.SubscriptionID, _ = row["subscriptionId"].(string)
+		if r.ID == "" {
+			continue
+		}
+		resources = append(resources, r)
+	}
+	return resources, nil
+}
+
+// EscapeKQL - Escapes the value to be used in a single quoted KQL string literal
+func EscapeKQL(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "'", "\\'").Replace(s)
+}
+
+// TagSelection - Tagged resources dispatched to the scanners handling their type, grouped by resource group
+type TagSelection struct {
+	resourceGroups []string
+	scanners       map[string][]IAzureScanner
+	resourceIDs    map[string]bool
+}
+
+// NewTagSelection - Dispatches each resource to the scanner handling its type. Resources without a scanner are ignored.
+func NewTagSelection(serviceScanners []IAzureScanner, resources []TaggedResource) *TagSelection {
+	t := &TagSelection{
+		resourceGroups: []string{},
+		scanners:       map[string][]IAzureScanner{},
+		resourceIDs:    map[string]bool{},
+	}
+
+	for _, r := range resources {
+		for _, s := range serviceScanners {
+			if !handlesType(s, r.Type) {
+				continue
+			}
+
+			rg := strings.ToLower(r.ResourceGroup)
+			if _, ok := t.scanners[rg]; !ok {
+				t.resourceGroups = append(t.resourceGroups, r.ResourceGroup)
+			}
+			if !containsScanner(t.scanners[rg], s) {
+				t.scanners[rg] = append(t.scanners[rg], s)
+			}
+			t.resourceIDs[strings.ToLower(r.ID)] = true
+			break
+		}
+	}
+	return t
+}
+
+// ResourceGroups - Returns the resource groups with at least one selected resource
+func (t *TagSelection) ResourceGroups() []string {
+	return t.resourceGroups
+}
+
+// GetScanners - Returns the scanners for the selected resources in the resource group
+func (t *TagSelection) GetScanners(resourceGroup string) []IAzureScanner {
+	return t.scanners[strings.ToLower(resourceGroup)]
+}
+
+// FilterResults - Removes the results of resources that were not selected
+func (t *TagSelection) FilterResults(results []AzureServiceResult) []AzureServiceResult {
+	filtered := []AzureServiceResult{}
+	for _, r := range results {
+		if t.resourceIDs[strings.ToLower(r.ResourceID)] {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+func handlesType(s IAzureScanner, resourceType string) bool {
+	for _, t := range s.GetResourceTypes() {
+		if strings.EqualFold(t, resourceType) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsScanner(list []IAzureScanner, s IAzureScanner) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"reflect"
+	"testing"
+)
+
+type fakeTypedScanner struct {
+	types []string
+}
+
+func (f *fakeTypedScanner) Init(config *ScannerConfig) error {
+	return nil
+}
+
+func (f *fakeTypedScanner) GetRules() map[string]AzureRule {
+	return map[string]AzureRule{}
+}
+
+func (f *fakeTypedScanner) GetResourceTypes() []string {
+	return f.types
+}
+
+func (f *fakeTypedScanner) Scan(resourceGroupName string, scanContext *ScanContext) ([]AzureServiceResult, error) {
+	return []AzureServiceResult{}, nil
+}
+
+func TestFilterScannersWithResources(t *testing.T) {
+	aks := &fakeTypedScanner{types: []string{"Microsoft.ContainerService/managedClusters"}}
+	st := &fakeTypedScanner{types: []string{"Microsoft.Storage/storageAccounts"}}
+
+	graph := ResourceGraph{
+		queryFunc: func(query string, subscriptions []string) ([]map[string]interface{}, error) {
+			return []map[string]interface{}{
+				{"type": "microsoft.containerservice/managedclusters", "count": float64(2)},
+			}, nil
+		},
+	}
+
+	counts, err := graph.CountResourcesByType([]string{"sub"}, []string{"Microsoft.ContainerService/managedClusters", "Microsoft.Storage/storageAccounts"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := FilterScannersWithResources([]IAzureScanner{aks, st}, counts)
+	want := []IAzureScanner{aks}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FilterScannersWithResources() = %v, want %v", got, want)
+	}
+}
+
+func TestTagSelection(t *testing.T) {
+	aks := &fakeTypedScanner{types: []string{"Microsoft.ContainerService/managedClusters"}}
+	st := &fakeTypedScanner{types: []string{"Microsoft.Storage/storageAccounts"}}
+	kv := &fakeTypedScanner{types: []string{"Microsoft.KeyVault/vaults"}}
+
+	aksID := "/subscriptions/sub/resourceGroups/rg-aks/providers/Microsoft.ContainerService/managedClusters/aks"
+	stID := "/subscriptions/sub/resourceGroups/rg-data/providers/Microsoft.Storage/storageAccounts/st"
+
+	graph := ResourceGraph{
+		queryFunc: func(query string, subscriptions []string) ([]map[string]interface{}, error) {
+			return []map[string]interface{}{
+				{"id": aksID, "type": "microsoft.containerservice/managedclusters", "resourceGroup": "rg-aks", "subscriptionId": "sub"},
+				{"id": stID, "type": "microsoft.storage/storageaccounts", "resourceGroup": "rg-data", "subscriptionId": "sub"},
+				{"id": "/subscriptions/sub/resourceGroups/rg-data/providers/Microsoft.Web/sites/app", "type": "microsoft.web/sites", "resourceGroup": "rg-data", "subscriptionId": "sub"},
+			}, nil
+		},
+	}
+
+	resources, err := graph.ListResourcesByTag([]string{"sub"}, "workload", "payments")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	selection := NewTagSelection([]IAzureScanner{aks, st, kv}, resources)
+
+	if got, want := selection.ResourceGroups(), []string{"rg-aks", "rg-data"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TagSelection.ResourceGroups() = %v, want %v", got, want)
+	}
+	if got, want := selection.GetScanners("RG-AKS"), []IAzureScanner{aks}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TagSelection.GetScanners() = %v, want %v", got, want)
+	}
+	if got, want := selection.GetScanners("rg-data"), []IAzureScanner{st}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TagSelection.GetScanners() = %v, want %v", got, want)
+	}
+
+	results := selection.FilterResults([]AzureServiceResult{
+		{ResourceID: aksID},
+		{ResourceID: "/subscriptions/sub/resourceGroups/rg-aks/providers/Microsoft.ContainerService/managedClusters/untagged"},
+	})
+	if len(results) != 1 || results[0].ResourceID != aksID {
+		t.Errorf("TagSelection.FilterResults() = %v, want only %s", results, aksID)
+	}
+}
+
+func TestEscapeKQL(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "plain", value: "stfunc", want: "stfunc"},
+		{name: "quote", value: "st' or 1==1 or name=='", want: "st\\' or 1==1 or name==\\'"},
+		{name: "backslash", value: "st\\", want: "st\\\\"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EscapeKQL(tt.value); got != tt.want {
+				t.Errorf("EscapeKQL() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -42,7 +42,7 @@ func (c *KeyVaultScanner) Scan(resourceGroupName string, scanContext *scanners.S
 	if err != nil {
 		return nil, err
 	}
-	engine := scanners.RuleEngine{}
+	engine := scanners.RuleEngine{Cache: c.config.RuleCache}
 	rules := c.GetRules()
 	results := []scanners.AzureServiceResult{}
 
@@ -52,9 +52,12 @@ func (c *KeyVaultScanner) Scan(resourceGroupName string, scanContext *scanners.S
 		results = append(results, scanners.AzureServiceResult{
 			SubscriptionID: c.config.SubscriptionID,
 			ResourceGroup:  resourceGroupName,
+			ResourceID:     *vault.ID,
 			ServiceName:    *vault.Name,
 			Type:           *vault.Type,
 			Location:       *vault.Location,
+			Tags:           vault.Tags,
+			CreatedAt:      scanners.GetCreatedAt(vault),
 			Rules:          rr,
 		})
 	}
@@ -72,9 +75,17 @@ func (c *KeyVaultScanner) listVaults(resourceGroupName string) ([]*armkeyvault.V
 				return nil, err
 			}
 			vaults = append(vaults, resp.Value...)
+			if limited, ok := scanners.LimitResources(c.config, vaults); ok {
+				return limited, nil
+			}
 		}
 		return vaults, nil
 	}
 
 	return c.listVaultsFunc(resourceGroupName)
 }
+
+// GetResourceTypes - Returns the resource types scanned by the KeyVaultScanner
+func (c *KeyVaultScanner) GetResourceTypes() []string {
+	return []string{"Microsoft.KeyVault/vaults"}
+}
@@ -4,7 +4,7 @@
 package kv
 
 import (
-	"log"
+	"fmt"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/keyvault/armkeyvault"
@@ -23,12 +23,7 @@ func (a *KeyVaultScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Medium",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				service := target.(*armkeyvault.Vault)
-				hasDiagnostics, err := a.diagnosticsSettings.HasDiagnostics(*service.ID)
-				if err != nil {
-					log.Fatalf("Error checking diagnostic settings for service %s: %s", *service.Name, err)
-				}
-
-				return !hasDiagnostics, ""
+				return a.diagnosticsSettings.EvalDiagnostics("kv-001", *service.ID, scanContext)
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/key-vault/general/monitor-key-vault",
 		},
@@ -76,7 +71,7 @@ func (a *KeyVaultScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Low",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armkeyvault.Vault)
-				caf := strings.HasPrefix(*c.Name, "kv")
+				caf := scanners.HasCAFPrefix(*c.Name, "kv", scanContext)
 				return !caf, ""
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
@@ -93,18 +88,20 @@ func (a *KeyVaultScanner) GetRules() map[string]scanners.AzureRule {
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
-		"kv-008": {
-			Id:          "kv-008",
-			Category:    "High Availability and Resiliency",
-			Subcategory: "Reliability",
-			Description: "Key Vault should have soft delete enabled",
-			Severity:    "Medium",
-			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+		"kv-008": scanners.NewSoftDeleteRule("kv-008", "Key Vault", "https://learn.microsoft.com/en-us/azure/key-vault/general/soft-delete-overview",
+			func(target interface{}, scanContext *scanners.ScanContext) (scanners.SoftDeleteStatus, bool) {
 				c := target.(*armkeyvault.Vault)
-				return c.Properties.EnableSoftDelete == nil || c.Properties.EnableSoftDelete == to.BoolPtr(false), ""
-			},
-			Url: "https://learn.microsoft.com/en-us/azure/key-vault/general/soft-delete-overview",
-		},
+				if c.Properties == nil {
+					return scanners.SoftDeleteStatus{}, false
+				}
+				// Retention defaults to 90 days
+				days := 90
+				if c.Properties.SoftDeleteRetentionInDays != nil {
+					days = int(*c.Properties.SoftDeleteRetentionInDays)
+				}
+				enabled := c.Properties.EnableSoftDelete != nil && *c.Properties.EnableSoftDelete
+				return scanners.SoftDeleteStatus{Enabled: enabled, RetentionDays: days}, true
+			}),
 		"kv-009": {
 			Id:          "kv-009",
 			Category:    "High Availability and Resiliency",
@@ -117,5 +114,48 @@ func (a *KeyVaultScanner) GetRules() map[string]scanners.AzureRule {
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/key-vault/general/soft-delete-overview#purge-protection",
 		},
+		"kv-010": {
+			Id:          "kv-010",
+			Category:    "Monitoring and Logging",
+			Subcategory: "Diagnostic Logs",
+			Description: "Key Vault diagnostic settings sending logs to a storage account should meet the minimum retention period",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				service := target.(*armkeyvault.Vault)
+				days, ok, err := a.diagnosticsSettings.GetLogRetentionDays(*service.ID)
+				if err != nil {
+					return scanContext.RuleError("kv-010", *service.ID, fmt.Errorf("checking diagnostic settings retention: %w", err))
+				}
+				if !ok {
+					return false, ""
+				}
+
+				return days < a.diagnosticsSettings.GetMinRetentionDays(), fmt.Sprintf("%d days", days)
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/azure-monitor/essentials/migrate-to-azure-storage-lifecycle-policy",
+		},
+		"kv-011": {
+			Id:          "kv-011",
+			Category:    "Monitoring and Logging",
+			Subcategory: "Diagnostic Logs",
+			Description: "Key Vault diagnostic settings should send logs to Log Analytics, not only to a storage account",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				service := target.(*armkeyvault.Vault)
+				destinations, err := a.diagnosticsSettings.GetDestinationTypes(*service.ID)
+				if err != nil {
+					return scanContext.RuleError("kv-011", *service.ID, fmt.Errorf("checking diagnostic settings destinations: %w", err))
+				}
+
+				storage := false
+				logAnalytics := false
+				for _, d := range destinations {
+					storage = storage || d == scanners.DestinationStorage
+					logAnalytics = logAnalytics || d == scanners.DestinationLogAnalytics
+				}
+				return storage && !logAnalytics, strings.Join(destinations, ", ")
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/azure-monitor/essentials/diagnostic-settings",
+		},
 	}
 }
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/keyvault/armkeyvault"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/cmendible/azqr/internal/scanners"
 )
@@ -129,7 +130,25 @@ func TestKeyVaultScanner_Rules(t *testing.T) {
 			},
 			want: want{
 				broken: false,
-				result: "",
+				result: "Enabled, 90 days",
+			},
+		},
+		{
+			name: "KeyVaultScanner soft delete disabled",
+			fields: fields{
+				rule: "kv-008",
+				target: &armkeyvault.Vault{
+					Properties: &armkeyvault.VaultProperties{
+						EnableSoftDelete:          to.BoolPtr(false),
+						SoftDeleteRetentionInDays: to.Int32Ptr(7),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "Disabled",
 			},
 		},
 		{
@@ -149,6 +168,103 @@ func TestKeyVaultScanner_Rules(t *testing.T) {
 				result: "",
 			},
 		},
+		{
+			name: "KeyVaultScanner diagnostic settings retention below minimum",
+			fields: fields{
+				rule: "kv-010",
+				target: &armkeyvault.Vault{
+					ID: to.StringPtr("test"),
+				},
+				scanContext: &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{
+					MinRetentionDays: 30,
+					ListDiagnosticSettingsFunc: func(resourceId string) ([]*armmonitor.DiagnosticSettingsResource, error) {
+						return []*armmonitor.DiagnosticSettingsResource{getStorageSetting(7)}, nil
+					},
+				},
+			},
+			want: want{
+				broken: true,
+				result: "7 days",
+			},
+		},
+		{
+			name: "KeyVaultScanner diagnostic settings retention above minimum",
+			fields: fields{
+				rule: "kv-010",
+				target: &armkeyvault.Vault{
+					ID: to.StringPtr("test"),
+				},
+				scanContext: &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{
+					MinRetentionDays: 30,
+					ListDiagnosticSettingsFunc: func(resourceId string) ([]*armmonitor.DiagnosticSettingsResource, error) {
+						return []*armmonitor.DiagnosticSettingsResource{getStorageSetting(90)}, nil
+					},
+				},
+			},
+			want: want{
+				broken: false,
+				result: "90 days",
+			},
+		},
+		{
+			name: "KeyVaultScanner diagnostic settings destinations Log Analytics only",
+			fields: fields{
+				rule: "kv-011",
+				target: &armkeyvault.Vault{
+					ID: to.StringPtr("test"),
+				},
+				scanContext: &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{
+					ListDiagnosticSettingsFunc: func(resourceId string) ([]*armmonitor.DiagnosticSettingsResource, error) {
+						return []*armmonitor.DiagnosticSettingsResource{getWorkspaceSetting()}, nil
+					},
+				},
+			},
+			want: want{
+				broken: false,
+				result: "Log Analytics",
+			},
+		},
+		{
+			name: "KeyVaultScanner diagnostic settings destinations Storage only",
+			fields: fields{
+				rule: "kv-011",
+				target: &armkeyvault.Vault{
+					ID: to.StringPtr("test"),
+				},
+				scanContext: &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{
+					ListDiagnosticSettingsFunc: func(resourceId string) ([]*armmonitor.DiagnosticSettingsResource, error) {
+						return []*armmonitor.DiagnosticSettingsResource{getStorageSetting(30)}, nil
+					},
+				},
+			},
+			want: want{
+				broken: true,
+				result: "Storage",
+			},
+		},
+		{
+			name: "KeyVaultScanner diagnostic settings destinations Log Analytics and Storage",
+			fields: fields{
+				rule: "kv-011",
+				target: &armkeyvault.Vault{
+					ID: to.StringPtr("test"),
+				},
+				scanContext: &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{
+					ListDiagnosticSettingsFunc: func(resourceId string) ([]*armmonitor.DiagnosticSettingsResource, error) {
+						return []*armmonitor.DiagnosticSettingsResource{getWorkspaceSetting(), getStorageSetting(30)}, nil
+					},
+				},
+			},
+			want: want{
+				broken: false,
+				result: "Log Analytics, Storage",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -172,3 +288,52 @@ func getSKUName() *armkeyvault.SKUName {
 	s := armkeyvault.SKUNameStandard
 	return &s
 }
+
+func TestKeyVaultScanner_DiagnosticSettingsListedOnce(t *testing.T) {
+	calls := 0
+	s := &KeyVaultScanner{
+		diagnosticsSettings: scanners.DiagnosticsSettings{
+			ListDiagnosticSettingsFunc: func(resourceId string) ([]*armmonitor.DiagnosticSettingsResource, error) {
+				calls++
+				return []*armmonitor.DiagnosticSettingsResource{getStorageSetting(7)}, nil
+			},
+		},
+	}
+	if err := s.diagnosticsSettings.Init(&scanners.ScannerConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	rules := s.GetRules()
+	vault := &armkeyvault.Vault{ID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.KeyVault/vaults/kv")}
+	for _, id := range []string{"DiagnosticSettings", "kv-010", "kv-011"} {
+		rules[id].Eval(vault, &scanners.ScanContext{})
+	}
+
+	if calls != 1 {
+		t.Errorf("KeyVaultScanner diagnostic settings listed %d times, want 1", calls)
+	}
+}
+
+func getStorageSetting(days int32) *armmonitor.DiagnosticSettingsResource {
+	return &armmonitor.DiagnosticSettingsResource{
+		Properties: &armmonitor.DiagnosticSettings{
+			StorageAccountID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/st"),
+			Logs: []*armmonitor.LogSettings{
+				{
+					Enabled: to.BoolPtr(true),
+					RetentionPolicy: &armmonitor.RetentionPolicy{
+						Enabled: to.BoolPtr(true),
+						Days:    &days,
+					},
+				},
+			},
+		},
+	}
+}
+
+func getWorkspaceSetting() *armmonitor.DiagnosticSettingsResource {
+	return &armmonitor.DiagnosticSettingsResource{
+		Properties: &armmonitor.DiagnosticSettings{
+			WorkspaceID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.OperationalInsights/workspaces/log"),
+		},
+	}
+}
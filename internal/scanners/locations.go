@@ -0,0 +1,48 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+// AllowedLocationsRuleID - Id of the rule added by AddAllowedLocationsRule
+const AllowedLocationsRuleID = "AllowedLocations"
+
+// NewAllowedLocationsRule - Returns a rule flagging results whose Location is not in the allowed list. Global resources are not flagged.
+func NewAllowedLocationsRule(allowed []string) AzureRule {
+	locations := map[string]bool{}
+	for _, l := range allowed {
+		locations[parseLocation(l)] = true
+	}
+
+	return AzureRule{
+		Id:          AllowedLocationsRuleID,
+		Category:    "Governance",
+		Subcategory: "Data Residency",
+		Description: "Resources should be deployed in an allowed location",
+		Severity:    "High",
+		Eval: func(target interface{}, scanContext *ScanContext) (bool, string) {
+			r := target.(AzureServiceResult)
+			location := parseLocation(r.Location)
+			if location == "" || location == "global" {
+				return false, location
+			}
+			return !locations[location], location
+		},
+		Url: "https://learn.microsoft.com/en-us/azure/governance/policy/samples/built-in-policies#general",
+	}
+}
+
+// AddAllowedLocationsRule - Evaluates the allowed locations rule against every result. Does nothing when no location is allowed.
+func AddAllowedLocationsRule(results []AzureServiceResult, allowed []string) {
+	if len(allowed) == 0 {
+		return
+	}
+
+	engine := RuleEngine{}
+	rule := NewAllowedLocationsRule(allowed)
+	for i := range results {
+		if results[i].Rules == nil {
+			results[i].Rules = map[string]AzureRuleResult{}
+		}
+		results[i].Rules[AllowedLocationsRuleID] = engine.EvaluateRule(rule, results[i], nil)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"testing"
+)
+
+func TestAddAllowedLocationsRule(t *testing.T) {
+	tests := []struct {
+		name     string
+		location string
+		want     bool
+	}{
+		{
+			name:     "in region",
+			location: "West Europe",
+			want:     false,
+		},
+		{
+			name:     "out of region",
+			location: "eastus",
+			want:     true,
+		},
+		{
+			name:     "global",
+			location: "global",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := []AzureServiceResult{
+				{
+					Location: tt.location,
+					Rules:    map[string]AzureRuleResult{},
+				},
+			}
+			AddAllowedLocationsRule(results, []string{"westeurope", "northeurope"})
+			got, ok := results[0].Rules[AllowedLocationsRuleID]
+			if !ok {
+				t.Fatalf("AddAllowedLocationsRule() did not add the %s rule", AllowedLocationsRuleID)
+			}
+			if got.IsBroken != tt.want {
+				t.Errorf("AddAllowedLocationsRule() broken = %v, want %v", got.IsBroken, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package plan
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+)
+
+// eolStacks - End of support dates of the runtime stack versions, keyed by stack name and version.
+// A version reaches end of life on its date, versions not listed are considered supported.
+// https://learn.microsoft.com/en-us/azure/app-service/language-support-policy
+var eolStacks = map[string]map[string]time.Time{
+	"DOTNETCORE": {
+		"1.0":  eolDate(2019, time.June, 27),
+		"1.1":  eolDate(2019, time.June, 27),
+		"2.0":  eolDate(2018, time.October, 1),
+		"2.1":  eolDate(2021, time.August, 21),
+		"2.2":  eolDate(2019, time.December, 23),
+		"3.0":  eolDate(2020, time.March, 3),
+		"3.1":  eolDate(2022, time.December, 13),
+		"5.0":  eolDate(2022, time.May, 10),
+		"6.0":  eolDate(2024, time.November, 12),
+		"7.0":  eolDate(2024, time.May, 14),
+		"8.0":  eolDate(2026, time.November, 10),
+		"9.0":  eolDate(2026, time.November, 10),
+		"10.0": eolDate(2028, time.November, 14),
+	},
+	"NODE": {
+		"4":  eolDate(2018, time.April, 30),
+		"6":  eolDate(2019, time.April, 30),
+		"8":  eolDate(2019, time.December, 31),
+		"10": eolDate(2021, time.April, 30),
+		"12": eolDate(2022, time.April, 30),
+		"14": eolDate(2023, time.April, 30),
+		"16": eolDate(2023, time.September, 11),
+		"18": eolDate(2025, time.April, 30),
+		"20": eolDate(2026, time.April, 30),
+		"22": eolDate(2027, time.April, 30),
+		"24": eolDate(2028, time.April, 30),
+	},
+	"PHP": {
+		"5.6": eolDate(2018, time.December, 31),
+		"7.0": eolDate(2019, time.January, 10),
+		"7.1": eolDate(2019, time.December, 1),
+		"7.2": eolDate(2020, time.November, 30),
+		"7.3": eolDate(2021, time.December, 6),
+		"7.4": eolDate(2022, time.November, 28),
+		"8.0": eolDate(2023, time.November, 26),
+		"8.1": eolDate(2025, time.December, 31),
+		"8.2": eolDate(2026, time.December, 31),
+		"8.3": eolDate(2027, time.December, 31),
+		"8.4": eolDate(2028, time.December, 31),
+	},
+	"PYTHON": {
+		"2.7":  eolDate(2020, time.January, 1),
+		"3.6":  eolDate(2021, time.December, 23),
+		"3.7":  eolDate(2023, time.June, 27),
+		"3.8":  eolDate(2024, time.October, 7),
+		"3.9":  eolDate(2025, time.October, 31),
+		"3.10": eolDate(2026, time.October, 31),
+		"3.11": eolDate(2027, time.October, 31),
+		"3.12": eolDate(2028, time.October, 31),
+		"3.13": eolDate(2029, time.October, 31),
+	},
+	"RUBY": {
+		"2.3": eolDate(2019, time.March, 31),
+		"2.4": eolDate(2020, time.March, 31),
+		"2.5": eolDate(2021, time.March, 31),
+		"2.6": eolDate(2022, time.April, 12),
+		"2.7": eolDate(2023, time.March, 31),
+	},
+}
+
+// eolDate - Returns the start of the day, in UTC, a runtime stack version reaches end of life
+func eolDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// getRuntimeStack - Returns the runtime stack (e.g. NODE|18-lts) configured for the site
+func getRuntimeStack(site *armappservice.Site) string {
+	if site.Properties == nil || site.Properties.SiteConfig == nil {
+		return ""
+	}
+	config := site.Properties.SiteConfig
+	if config.LinuxFxVersion != nil && *config.LinuxFxVersion != "" {
+		return *config.LinuxFxVersion
+	}
+	if config.WindowsFxVersion != nil && *config.WindowsFxVersion != "" {
+		return *config.WindowsFxVersion
+	}
+	return ""
+}
+
+// isEOLStack - Checks if the runtime stack (e.g. NODE|14-lts) reached end of life at "now"
+func isEOLStack(stack string, now time.Time) bool {
+	name, version, found := strings.Cut(stack, "|")
+	if !found {
+		return false
+	}
+	version, _, _ = strings.Cut(strings.ToLower(version), "-")
+	for v, eol := range eolStacks[strings.ToUpper(name)] {
+		if version == v || strings.HasPrefix(version, v+".") {
+			return !now.Before(eol)
+		}
+	}
+	return false
+}
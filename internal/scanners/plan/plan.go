@@ -4,10 +4,17 @@
 package plan
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
 	"github.com/cmendible/azqr/internal/scanners"
 )
 
@@ -17,13 +24,33 @@ type AppServiceScanner struct {
 	diagnosticsSettings scanners.DiagnosticsSettings
 	plansClient         *armappservice.PlansClient
 	sitesClient         *armappservice.WebAppsClient
-	listPlansFunc       func(resourceGroupName string) ([]*armappservice.Plan, error)
-	listSitesFunc       func(resourceGroupName string, planName string) ([]*armappservice.Site, error)
+	autoscaleClient     *armmonitor.AutoscaleSettingsClient
+	metricsClient       *armmonitor.MetricsClient
+	graph               scanners.ResourceGraph
+	// mu - Guards the settings loaded by Scan, read by the rules while other Resource Groups are scanned
+	mu                      sync.RWMutex
+	planTiers               map[string]string
+	functionStorageAccounts map[string]string
+	functionAppInsights     map[string]bool
+	publicStorageAccounts   map[string]bool
+	listPlansFunc           func(resourceGroupName string) ([]*armappservice.Plan, error)
+	listSitesFunc           func(resourceGroupName string, planName string) ([]*armappservice.Site, error)
+	listAutoscaleFunc       func() ([]*armmonitor.AutoscaleSettingResource, error)
+	listAppSettingsFunc     func(resourceGroupName string, siteName string) (map[string]*string, error)
+	getSiteConfigFunc       func(resourceGroupName string, siteName string) (*armappservice.SiteConfig, error)
+	listPublicStorageFunc   func(accounts []string) ([]string, error)
+	listSlotsFunc           func(resourceGroupName string, siteName string) ([]*armappservice.Site, error)
+	listMetricsFunc         func(resourceID string) ([]*armmonitor.Metric, error)
 }
 
 // Init - Initializes the AppServiceScanner
 func (a *AppServiceScanner) Init(config *scanners.ScannerConfig) error {
 	a.config = config
+	// The settings loaded by Scan belong to the subscription of the previous config
+	a.planTiers = nil
+	a.functionStorageAccounts = nil
+	a.functionAppInsights = nil
+	a.publicStorageAccounts = nil
 	var err error
 	a.plansClient, err = armappservice.NewPlansClient(config.SubscriptionID, config.Cred, config.ClientOptions)
 	if err != nil {
@@ -33,6 +60,19 @@ func (a *AppServiceScanner) Init(config *scanners.ScannerConfig) error {
 	if err != nil {
 		return err
 	}
+	a.autoscaleClient, err = armmonitor.NewAutoscaleSettingsClient(config.SubscriptionID, config.Cred, config.ClientOptions)
+	if err != nil {
+		return err
+	}
+	a.metricsClient, err = armmonitor.NewMetricsClient(config.Cred, config.ClientOptions)
+	if err != nil {
+		return err
+	}
+	a.graph = scanners.ResourceGraph{}
+	err = a.graph.Init(config)
+	if err != nil {
+		return err
+	}
 	a.diagnosticsSettings = scanners.DiagnosticsSettings{}
 	err = a.diagnosticsSettings.Init(config)
 	if err != nil {
@@ -49,21 +89,33 @@ func (a *AppServiceScanner) Scan(resourceGroupName string, scanContext *scanners
 	if err != nil {
 		return nil, err
 	}
-	engine := scanners.RuleEngine{}
+	engine := scanners.RuleEngine{Cache: a.config.RuleCache}
 	rules := a.GetRules()
 	appRules := a.GetAppRules()
 	functionRules := a.GetFunctionRules()
 	results := []scanners.AzureServiceResult{}
 
 	for _, p := range plan {
+		if p.SKU != nil && p.SKU.Tier != nil {
+			a.mu.Lock()
+			if a.planTiers == nil {
+				a.planTiers = map[string]string{}
+			}
+			a.planTiers[strings.ToLower(*p.ID)] = *p.SKU.Tier
+			a.mu.Unlock()
+		}
+
 		rr := engine.EvaluateRules(rules, p, scanContext)
 
 		results = append(results, scanners.AzureServiceResult{
 			SubscriptionID: a.config.SubscriptionID,
 			ResourceGroup:  resourceGroupName,
+			ResourceID:     *p.ID,
 			ServiceName:    *p.Name,
 			Type:           *p.Type,
 			Location:       *p.Location,
+			Tags:           p.Tags,
+			CreatedAt:      scanners.GetCreatedAt(p),
 			Rules:          rr,
 		})
 
@@ -72,6 +124,14 @@ func (a *AppServiceScanner) Scan(resourceGroupName string, scanContext *scanners
 			return nil, err
 		}
 
+		if err := a.loadFunctionSettings(resourceGroupName, sites); err != nil {
+			return nil, err
+		}
+
+		if err := a.loadSiteConfig(resourceGroupName, sites); err != nil {
+			return nil, err
+		}
+
 		for _, s := range sites {
 			var result scanners.AzureServiceResult
 			// https://learn.microsoft.com/en-us/azure/azure-functions/functions-app-settings
@@ -82,9 +142,12 @@ func (a *AppServiceScanner) Scan(resourceGroupName string, scanContext *scanners
 				result = scanners.AzureServiceResult{
 					SubscriptionID: a.config.SubscriptionID,
 					ResourceGroup:  resourceGroupName,
+					ResourceID:     *s.ID,
 					ServiceName:    *s.Name,
 					Type:           *s.Type,
 					Location:       *p.Location,
+					Tags:           s.Tags,
+					CreatedAt:      scanners.GetCreatedAt(s),
 					Rules:          rr,
 				}
 
@@ -126,9 +189,12 @@ func (a *AppServiceScanner) Scan(resourceGroupName string, scanContext *scanners
 				result = scanners.AzureServiceResult{
 					SubscriptionID: a.config.SubscriptionID,
 					ResourceGroup:  resourceGroupName,
+					ResourceID:     *s.ID,
 					ServiceName:    *s.Name,
 					Type:           *s.Type,
 					Location:       *p.Location,
+					Tags:           s.Tags,
+					CreatedAt:      scanners.GetCreatedAt(s),
 					Rules:          rr,
 				}
 			}
@@ -150,6 +216,9 @@ func (a *AppServiceScanner) listPlans(resourceGroupName string) ([]*armappservic
 				return nil, err
 			}
 			results = append(results, resp.Value...)
+			if limited, ok := scanners.LimitResources(a.config, results); ok {
+				return limited, nil
+			}
 		}
 
 		return results, nil
@@ -174,3 +243,304 @@ func (a *AppServiceScanner) listSites(resourceGroupName string, plan string) ([]
 
 	return a.listSitesFunc(resourceGroupName, plan)
 }
+
+// LoadScanContext - Loads the resources targeted by an enabled autoscale setting into the ScanContext.
+// Autoscale settings can live in any resource group, so they are listed once for the whole subscription.
+func (a *AppServiceScanner) LoadScanContext(resourceGroups []string, scanContext *scanners.ScanContext) error {
+	autoscaleSettings, err := a.listAutoscale()
+	if err != nil {
+		return err
+	}
+	targets := map[string]bool{}
+	for _, s := range autoscaleSettings {
+		if s.Properties == nil || s.Properties.TargetResourceURI == nil {
+			continue
+		}
+		if s.Properties.Enabled == nil || !*s.Properties.Enabled {
+			continue
+		}
+		targets[strings.ToLower(*s.Properties.TargetResourceURI)] = true
+	}
+	scanContext.AutoscaleTargets = targets
+	return nil
+}
+
+// getPlanTier - Returns the SKU tier of the plan loaded by Scan
+func (a *AppServiceScanner) getPlanTier(planID string) string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.planTiers[strings.ToLower(planID)]
+}
+
+// listAutoscale - Returns the autoscale settings in the subscription
+func (a *AppServiceScanner) listAutoscale() ([]*armmonitor.AutoscaleSettingResource, error) {
+	if a.listAutoscaleFunc == nil {
+		pager := a.autoscaleClient.NewListBySubscriptionPager(nil)
+		results := []*armmonitor.AutoscaleSettingResource{}
+		for pager.More() {
+			resp, err := pager.NextPage(a.config.Ctx)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, resp.Value...)
+		}
+		return results, nil
+	}
+
+	return a.listAutoscaleFunc()
+}
+
+// loadFunctionSettings - Reads the app settings of the function apps to load the storage account they use,
+// whether it is publicly accessible and whether Application Insights is configured
+func (a *AppServiceScanner) loadFunctionSettings(resourceGroupName string, sites []*armappservice.Site) error {
+	storageAccounts := map[string]string{}
+	appInsights := map[string]bool{}
+	accounts := []string{}
+	for _, s := range sites {
+		if s.Kind == nil || !strings.Contains(strings.ToLower(*s.Kind), "functionapp") {
+			continue
+		}
+		settings, err := a.listAppSettings(resourceGroupName, *s.Name)
+		if err != nil {
+			// Listing app settings requires more than read access, don't fail the scan
+			var responseErr *azcore.ResponseError
+			if errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusForbidden {
+				log.Printf("WARNING: Unable to read app settings of function %s: %s", *s.Name, responseErr.ErrorCode)
+				continue
+			}
+			return err
+		}
+		appInsights[strings.ToLower(*s.ID)] = hasAppInsights(settings)
+
+		account := getStorageAccountName(settings)
+		if account == "" {
+			continue
+		}
+		storageAccounts[strings.ToLower(*s.ID)] = account
+		accounts = append(accounts, account)
+	}
+
+	public, err := a.listPublicStorage(accounts)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.functionStorageAccounts == nil {
+		a.functionStorageAccounts = map[string]string{}
+		a.functionAppInsights = map[string]bool{}
+		a.publicStorageAccounts = map[string]bool{}
+	}
+	for id, account := range storageAccounts {
+		a.functionStorageAccounts[id] = account
+	}
+	for id, configured := range appInsights {
+		a.functionAppInsights[id] = configured
+	}
+	for _, account := range public {
+		a.publicStorageAccounts[strings.ToLower(account)] = true
+	}
+	return nil
+}
+
+// getFunctionStorage - Returns the storage account used by the function app and whether it is publicly accessible,
+// false if the storage account is unknown
+func (a *AppServiceScanner) getFunctionStorage(siteID string) (string, bool, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	account, ok := a.functionStorageAccounts[strings.ToLower(siteID)]
+	if !ok {
+		return "", false, false
+	}
+	return account, a.publicStorageAccounts[strings.ToLower(account)], true
+}
+
+// getFunctionAppInsights - Returns true if the function app has Application Insights configured,
+// false if its app settings couldn't be read
+func (a *AppServiceScanner) getFunctionAppInsights(siteID string) (bool, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	configured, ok := a.functionAppInsights[strings.ToLower(siteID)]
+	return configured, ok
+}
+
+// getStorageAccountName - Returns the name of the storage account a function app uses for its content,
+// falling back to the AzureWebJobsStorage connection string or identity based connection
+func getStorageAccountName(settings map[string]*string) string {
+	for _, key := range []string{"WEBSITE_CONTENTAZUREFILECONNECTIONSTRING", "AzureWebJobsStorage"} {
+		for k, v := range settings {
+			if !strings.EqualFold(k, key) || v == nil {
+				continue
+			}
+			for _, part := range strings.Split(*v, ";") {
+				name, value, found := strings.Cut(strings.TrimSpace(part), "=")
+				if found && strings.EqualFold(name, "AccountName") && value != "" {
+					return value
+				}
+			}
+		}
+	}
+	for k, v := range settings {
+		if strings.EqualFold(k, "AzureWebJobsStorage__accountName") && v != nil {
+			return *v
+		}
+	}
+	return ""
+}
+
+// hasAppInsights - Returns true if the Application Insights instrumentation key or connection string is set
+func hasAppInsights(settings map[string]*string) bool {
+	for k, v := range settings {
+		if (strings.EqualFold(k, "APPINSIGHTS_INSTRUMENTATIONKEY") || strings.EqualFold(k, "APPLICATIONINSIGHTS_CONNECTION_STRING")) &&
+			v != nil && *v != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// loadSiteConfig - Replaces the SiteConfig of the sites with their configuration.
+// The SiteConfig returned when listing sites can't be trusted since values are nil or empty.
+func (a *AppServiceScanner) loadSiteConfig(resourceGroupName string, sites []*armappservice.Site) error {
+	for _, s := range sites {
+		config, err := a.getSiteConfig(resourceGroupName, *s.Name)
+		if err != nil {
+			var responseErr *azcore.ResponseError
+			if errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusForbidden {
+				log.Printf("WARNING: Unable to read configuration of site %s: %s", *s.Name, responseErr.ErrorCode)
+				continue
+			}
+			return err
+		}
+		if s.Properties == nil {
+			s.Properties = &armappservice.SiteProperties{}
+		}
+		s.Properties.SiteConfig = config
+	}
+	return nil
+}
+
+func (a *AppServiceScanner) getSiteConfig(resourceGroupName string, siteName string) (*armappservice.SiteConfig, error) {
+	if a.getSiteConfigFunc == nil {
+		resp, err := a.sitesClient.GetConfiguration(a.config.Ctx, resourceGroupName, siteName, nil)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Properties, nil
+	}
+
+	return a.getSiteConfigFunc(resourceGroupName, siteName)
+}
+
+func (a *AppServiceScanner) listAppSettings(resourceGroupName string, siteName string) (map[string]*string, error) {
+	if a.listAppSettingsFunc == nil {
+		resp, err := a.sitesClient.ListApplicationSettings(a.config.Ctx, resourceGroupName, siteName, nil)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Properties, nil
+	}
+
+	return a.listAppSettingsFunc(resourceGroupName, siteName)
+}
+
+// listPublicStorage - Returns the storage accounts, by name, that allow public network access from all networks
+func (a *AppServiceScanner) listPublicStorage(accounts []string) ([]string, error) {
+	if a.listPublicStorageFunc == nil {
+		if len(accounts) == 0 {
+			return []string{}, nil
+		}
+		names := make([]string, 0, len(accounts))
+		for _, account := range accounts {
+			names = append(names, fmt.Sprintf("'%s'", scanners.EscapeKQL(account)))
+		}
+		query := fmt.Sprintf("resources | where type =~ 'microsoft.storage/storageaccounts' and name in~ (%s) and tostring(properties.publicNetworkAccess) !~ 'Disabled' and tostring(properties.networkAcls.defaultAction) !~ 'Deny' | project name", strings.Join(names, ","))
+		rows, err := a.graph.Query(query, []string{a.config.SubscriptionID})
+		if err != nil {
+			return nil, err
+		}
+		public := []string{}
+		for _, row := range rows {
+			if name, ok := row["name"].(string); ok {
+				public = append(public, name)
+			}
+		}
+		return public, nil
+	}
+
+	return a.listPublicStorageFunc(accounts)
+}
+
+// listSlots - Returns the deployment slots of the site
+func (a *AppServiceScanner) listSlots(resourceGroupName string, siteName string) ([]*armappservice.Site, error) {
+	if a.listSlotsFunc == nil {
+		pager := a.sitesClient.NewListSlotsPager(resourceGroupName, siteName, nil)
+		results := []*armappservice.Site{}
+		for pager.More() {
+			resp, err := pager.NextPage(a.config.Ctx)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, resp.Value...)
+		}
+		return results, nil
+	}
+
+	return a.listSlotsFunc(resourceGroupName, siteName)
+}
+
+// listMetrics - Returns the hourly average CPU and memory percentage of the plan over the last utilizationDays
+func (a *AppServiceScanner) listMetrics(resourceID string) ([]*armmonitor.Metric, error) {
+	if a.listMetricsFunc == nil {
+		end := time.Now().UTC()
+		start := end.AddDate(0, 0, -utilizationDays)
+		timespan := fmt.Sprintf("%s/%s", start.Format(time.RFC3339), end.Format(time.RFC3339))
+		metricNames := cpuPercentageMetric + "," + memoryPercentageMetric
+		aggregation := "Average"
+		interval := "PT1H"
+		resp, err := a.metricsClient.List(a.config.Ctx, resourceID, &armmonitor.MetricsClientListOptions{
+			Timespan:    &timespan,
+			Interval:    &interval,
+			Metricnames: &metricNames,
+			Aggregation: &aggregation,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return resp.Value, nil
+	}
+
+	return a.listMetricsFunc(resourceID)
+}
+
+// averageMetric - Returns the average of the data points of the metric, false if there are none
+func averageMetric(metrics []*armmonitor.Metric, name string) (float64, bool) {
+	total := 0.0
+	points := 0
+	for _, m := range metrics {
+		if m == nil || m.Name == nil || m.Name.Value == nil || !strings.EqualFold(*m.Name.Value, name) {
+			continue
+		}
+		for _, ts := range m.Timeseries {
+			for _, d := range ts.Data {
+				if d.Average != nil {
+					total += *d.Average
+					points++
+				}
+			}
+		}
+	}
+	if points == 0 {
+		return 0, false
+	}
+	return total / float64(points), true
+}
+
+// GetResourceTypes - Returns the resource types scanned by the AppServiceScanner
+func (a *AppServiceScanner) GetResourceTypes() []string {
+	return []string{
+		"Microsoft.Web/serverFarms",
+		"Microsoft.Web/sites",
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package plan
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+func TestAppServiceScanner_LoadFunctionSettings(t *testing.T) {
+	a := &AppServiceScanner{
+		listAppSettingsFunc: func(resourceGroupName string, siteName string) (map[string]*string, error) {
+			switch siteName {
+			case "func-content":
+				return map[string]*string{
+					"WEBSITE_CONTENTAZUREFILECONNECTIONSTRING": to.StringPtr("DefaultEndpointsProtocol=https;AccountName=stcontent;AccountKey=key;EndpointSuffix=core.windows.net"),
+					"AzureWebJobsStorage":                      to.StringPtr("DefaultEndpointsProtocol=https;AccountName=stjobs;AccountKey=key"),
+				}, nil
+			case "func-forbidden":
+				return nil, &azcore.ResponseError{StatusCode: http.StatusForbidden, ErrorCode: "AuthorizationFailed"}
+			case "func-identity":
+				return map[string]*string{
+					"AzureWebJobsStorage__accountName":      to.StringPtr("stidentity"),
+					"APPLICATIONINSIGHTS_CONNECTION_STRING": to.StringPtr("InstrumentationKey=00000000-0000-0000-0000-000000000000"),
+				}, nil
+			}
+			return map[string]*string{}, nil
+		},
+		listPublicStorageFunc: func(accounts []string) ([]string, error) {
+			return []string{"stidentity"}, nil
+		},
+	}
+	sites := []*armappservice.Site{
+		{ID: to.StringPtr("id/func-content"), Name: to.StringPtr("func-content"), Kind: to.StringPtr("functionapp")},
+		{ID: to.StringPtr("id/func-identity"), Name: to.StringPtr("func-identity"), Kind: to.StringPtr("functionapp,linux")},
+		{ID: to.StringPtr("id/func-forbidden"), Name: to.StringPtr("func-forbidden"), Kind: to.StringPtr("functionapp")},
+		{ID: to.StringPtr("id/func-none"), Name: to.StringPtr("func-none"), Kind: to.StringPtr("functionapp")},
+		{ID: to.StringPtr("id/app"), Name: to.StringPtr("app"), Kind: to.StringPtr("app")},
+	}
+
+	if err := a.loadFunctionSettings("rg", sites); err != nil {
+		t.Fatal(err)
+	}
+
+	wantAccounts := map[string]string{
+		"id/func-content":  "stcontent",
+		"id/func-identity": "stidentity",
+	}
+	if !reflect.DeepEqual(a.functionStorageAccounts, wantAccounts) {
+		t.Errorf("loadFunctionSettings() accounts = %v, want %v", a.functionStorageAccounts, wantAccounts)
+	}
+	wantPublic := map[string]bool{"stidentity": true}
+	if !reflect.DeepEqual(a.publicStorageAccounts, wantPublic) {
+		t.Errorf("loadFunctionSettings() public = %v, want %v", a.publicStorageAccounts, wantPublic)
+	}
+	wantAppInsights := map[string]bool{
+		"id/func-content":  false,
+		"id/func-identity": true,
+		"id/func-none":     false,
+	}
+	if !reflect.DeepEqual(a.functionAppInsights, wantAppInsights) {
+		t.Errorf("loadFunctionSettings() app insights = %v, want %v", a.functionAppInsights, wantAppInsights)
+	}
+}
+
+func TestAppServiceScanner_LoadSiteConfig(t *testing.T) {
+	a := &AppServiceScanner{
+		getSiteConfigFunc: func(resourceGroupName string, siteName string) (*armappservice.SiteConfig, error) {
+			if siteName == "forbidden" {
+				return nil, &azcore.ResponseError{StatusCode: http.StatusForbidden, ErrorCode: "AuthorizationFailed"}
+			}
+			return &armappservice.SiteConfig{
+				Use32BitWorkerProcess:                  to.BoolPtr(false),
+				FunctionsRuntimeScaleMonitoringEnabled: to.BoolPtr(true),
+			}, nil
+		},
+	}
+	listed := &armappservice.SiteConfig{}
+	sites := []*armappservice.Site{
+		{Name: to.StringPtr("func"), Properties: &armappservice.SiteProperties{SiteConfig: listed}},
+		{Name: to.StringPtr("app")},
+		{Name: to.StringPtr("forbidden"), Properties: &armappservice.SiteProperties{SiteConfig: listed}},
+	}
+
+	if err := a.loadSiteConfig("rg", sites); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range sites[:2] {
+		c := s.Properties.SiteConfig
+		if c == nil || c.FunctionsRuntimeScaleMonitoringEnabled == nil || !*c.FunctionsRuntimeScaleMonitoringEnabled || c.Use32BitWorkerProcess == nil {
+			t.Errorf("loadSiteConfig() %s config = %v, want the site configuration", *s.Name, c)
+		}
+	}
+	if sites[2].Properties.SiteConfig != listed {
+		t.Errorf("loadSiteConfig() should keep the listed config when the configuration can't be read")
+	}
+}
+
+func TestAppServiceScanner_LoadScanContext(t *testing.T) {
+	calls := 0
+	a := &AppServiceScanner{
+		listAutoscaleFunc: func() ([]*armmonitor.AutoscaleSettingResource, error) {
+			calls++
+			return []*armmonitor.AutoscaleSettingResource{
+				{
+					Properties: &armmonitor.AutoscaleSetting{
+						Enabled:           to.BoolPtr(true),
+						TargetResourceURI: to.StringPtr("/subscriptions/sub/resourceGroups/rg-autoscale/providers/Microsoft.Web/serverfarms/Enabled"),
+					},
+				},
+				{
+					Properties: &armmonitor.AutoscaleSetting{
+						Enabled:           to.BoolPtr(false),
+						TargetResourceURI: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/serverfarms/disabled"),
+					},
+				},
+				{
+					Properties: &armmonitor.AutoscaleSetting{
+						TargetResourceURI: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/serverfarms/default"),
+					},
+				},
+			}, nil
+		},
+	}
+
+	scanContext := &scanners.ScanContext{}
+	if err := a.LoadScanContext([]string{"rg"}, scanContext); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]bool{
+		"/subscriptions/sub/resourcegroups/rg-autoscale/providers/microsoft.web/serverfarms/enabled": true,
+	}
+	if !reflect.DeepEqual(scanContext.AutoscaleTargets, want) {
+		t.Errorf("LoadScanContext() AutoscaleTargets = %v, want %v", scanContext.AutoscaleTargets, want)
+	}
+	if calls != 1 {
+		t.Errorf("LoadScanContext() listed autoscale settings %d times, want 1", calls)
+	}
+}
+
+func TestIsEOLStack(t *testing.T) {
+	now := time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		stack string
+		now   time.Time
+		want  bool
+	}{
+		{name: "day before end of life", stack: "NODE|18-lts", now: time.Date(2025, time.April, 29, 23, 59, 59, 0, time.UTC), want: false},
+		{name: "day of end of life", stack: "NODE|18-lts", now: time.Date(2025, time.April, 30, 0, 0, 0, 0, time.UTC), want: true},
+		{name: "dotnet 6", stack: "DOTNETCORE|6.0", now: now, want: true},
+		{name: "python 3.8", stack: "PYTHON|3.8", now: now, want: true},
+		{name: "php 8.1", stack: "PHP|8.1", now: now, want: true},
+		{name: "patch version", stack: "php|8.1.2", now: now, want: true},
+		{name: "python 3.10 before end of life", stack: "PYTHON|3.10", now: now, want: false},
+		{name: "supported version", stack: "DOTNETCORE|8.0", now: now, want: false},
+		{name: "unknown version", stack: "NODE|26-lts", now: now, want: false},
+		{name: "no version", stack: "DOCKER", now: now, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isEOLStack(tt.stack, tt.now); got != tt.want {
+				t.Errorf("isEOLStack(%s, %s) = %v, want %v", tt.stack, tt.now, got, tt.want)
+			}
+		})
+	}
+}
@@ -4,13 +4,43 @@
 package plan
 
 import (
-	"log"
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
 	"github.com/cmendible/azqr/internal/scanners"
 )
 
+// zoneCount - Number of availability zones a zone redundant plan spreads its workers across
+const zoneCount = 3
+
+// reservedInstanceTiers - Plan tiers, in lower case, that can be covered by App Service reserved instances
+var reservedInstanceTiers = map[string]bool{
+	"premiumv3":  true,
+	"premiummv3": true,
+	"isolatedv2": true,
+}
+
+const (
+	// utilizationDays - Days of Azure Monitor metrics averaged to find over-sized plans
+	utilizationDays        = 7
+	cpuPercentageMetric    = "CpuPercentage"
+	memoryPercentageMetric = "MemoryPercentage"
+	// lowCPUPercentage and lowMemoryPercentage - Average usage below which a plan is a downgrade candidate
+	lowCPUPercentage    = 20.0
+	lowMemoryPercentage = 40.0
+)
+
+// sharedTiers - Plan tiers, in lower case, without dedicated workers to downgrade
+var sharedTiers = map[string]bool{
+	"free":    true,
+	"shared":  true,
+	"dynamic": true,
+}
+
 // GetRules - Returns the rules for the AppServiceScanner
 func (a *AppServiceScanner) GetRules() map[string]scanners.AzureRule {
 	return map[string]scanners.AzureRule{
@@ -22,12 +52,7 @@ func (a *AppServiceScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Medium",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				service := target.(*armappservice.Plan)
-				hasDiagnostics, err := a.diagnosticsSettings.HasDiagnostics(*service.ID)
-				if err != nil {
-					log.Fatalf("Error checking diagnostic settings for service %s: %s", *service.Name, err)
-				}
-
-				return !hasDiagnostics, ""
+				return a.diagnosticsSettings.EvalDiagnostics("plan-001", *service.ID, scanContext)
 			},
 		},
 		"AvailabilityZones": {
@@ -53,7 +78,15 @@ func (a *AppServiceScanner) GetRules() map[string]scanners.AzureRule {
 				i := target.(*armappservice.Plan)
 				sku := string(*i.SKU.Tier)
 				sla := "None"
-				if sku != "Free" && sku != "Shared" {
+				switch sku {
+				case "Free", "Shared":
+				case "Isolated", "IsolatedV2":
+					// App Service Environments deployed with zone redundancy have a higher SLA
+					sla = "99.95%"
+					if i.Properties != nil && i.Properties.ZoneRedundant != nil && *i.Properties.ZoneRedundant {
+						sla = "99.99%"
+					}
+				default:
 					sla = "99.95%"
 				}
 				return sla == "None", sla
@@ -80,7 +113,7 @@ func (a *AppServiceScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Low",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armappservice.Plan)
-				caf := strings.HasPrefix(*c.Name, "asp")
+				caf := scanners.HasCAFPrefix(*c.Name, "asp", scanContext)
 				return !caf, ""
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
@@ -97,6 +130,144 @@ func (a *AppServiceScanner) GetRules() map[string]scanners.AzureRule {
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
+		"plan-011": {
+			Id:          "plan-011",
+			Category:    "Governance",
+			Subcategory: "Cost Optimization",
+			Description: "Plan should host at least one site",
+			Severity:    "Low",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armappservice.Plan)
+				if c.Properties == nil || c.Properties.NumberOfSites == nil {
+					return false, ""
+				}
+				sites := *c.Properties.NumberOfSites
+				return sites == 0, strconv.Itoa(int(sites))
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/app-service/overview-manage-costs",
+		},
+		"plan-012": {
+			Id:          "plan-012",
+			Category:    "High Availability and Resiliency",
+			Subcategory: "Scalability",
+			Description: "Production Plan should have enabled autoscale settings",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armappservice.Plan)
+				if !scanners.IsProduction(c.Tags) {
+					return false, ""
+				}
+				// Consumption and Elastic Premium plans scale automatically
+				if c.SKU != nil && c.SKU.Tier != nil && (*c.SKU.Tier == "Dynamic" || *c.SKU.Tier == "ElasticPremium") {
+					return false, ""
+				}
+				if scanContext.AutoscaleTargets[strings.ToLower(*c.ID)] {
+					return false, "Autoscale Configured"
+				}
+				return true, "No Autoscale"
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/app-service/manage-scale-up",
+		},
+		"plan-013": {
+			Id:          "plan-013",
+			Category:    "High Availability and Resiliency",
+			Subcategory: "Availability Zones",
+			Description: "Zone redundant Plan should have at least one worker per availability zone",
+			Severity:    "High",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armappservice.Plan)
+				if c.Properties == nil || c.Properties.ZoneRedundant == nil || !*c.Properties.ZoneRedundant {
+					return false, ""
+				}
+
+				var workers int32
+				if c.Properties.NumberOfWorkers != nil {
+					workers = *c.Properties.NumberOfWorkers
+				} else if c.SKU != nil && c.SKU.Capacity != nil {
+					workers = *c.SKU.Capacity
+				}
+				return workers < zoneCount, fmt.Sprintf("%d workers", workers)
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/reliability/reliability-app-service#availability-zone-support",
+		},
+		"plan-014": {
+			Id:          "plan-014",
+			Category:    "Monitoring and Logging",
+			Subcategory: "Diagnostic Logs",
+			Description: "Plan diagnostic settings should include the AllMetrics category",
+			Severity:    "Low",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				service := target.(*armappservice.Plan)
+				categories, err := a.diagnosticsSettings.GetMetricCategories(*service.ID)
+				if err != nil {
+					return scanContext.RuleError("plan-014", *service.ID, fmt.Errorf("checking diagnostic settings metrics: %w", err))
+				}
+
+				for _, c := range categories {
+					if strings.EqualFold(c, scanners.AllMetricsCategory) {
+						return false, ""
+					}
+				}
+				return true, ""
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/azure-monitor/essentials/diagnostic-settings",
+		},
+		"plan-015": {
+			Id:          "plan-015",
+			Category:    "Governance",
+			Subcategory: "Cost Optimization",
+			Description: "Plan SKU eligibility for reserved instances (long-running plans on pay-as-you-go pricing can reduce costs)",
+			Severity:    "Low",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armappservice.Plan)
+				if c.SKU == nil || c.SKU.Tier == nil {
+					return false, ""
+				}
+
+				sku := *c.SKU.Tier
+				if c.SKU.Name != nil {
+					sku = fmt.Sprintf("%s (%s)", *c.SKU.Tier, *c.SKU.Name)
+				}
+				if reservedInstanceTiers[strings.ToLower(*c.SKU.Tier)] {
+					return false, fmt.Sprintf("%s: reserved instance candidate", sku)
+				}
+				return false, fmt.Sprintf("%s: not eligible", sku)
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/cost-management-billing/reservations/prepay-app-service",
+		},
+		"plan-016": {
+			Id:          "plan-016",
+			Category:    "Governance",
+			Subcategory: "Cost Optimization",
+			Description: "Plan with low CPU and memory usage should be downgraded to a smaller SKU (requires --with-metrics)",
+			Severity:    "Low",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armappservice.Plan)
+				if !a.config.WithMetrics {
+					return false, ""
+				}
+				if c.SKU != nil && c.SKU.Tier != nil && sharedTiers[strings.ToLower(*c.SKU.Tier)] {
+					return false, ""
+				}
+
+				metrics, err := a.listMetrics(*c.ID)
+				if err != nil {
+					return scanContext.RuleError("plan-016", *c.ID, fmt.Errorf("listing metrics: %w", err))
+				}
+				cpu, okCPU := averageMetric(metrics, cpuPercentageMetric)
+				memory, okMemory := averageMetric(metrics, memoryPercentageMetric)
+				if !okCPU || !okMemory {
+					return false, "No metrics"
+				}
+				return cpu < lowCPUPercentage && memory < lowMemoryPercentage,
+					fmt.Sprintf("CPU %.1f%%, Memory %.1f%% (%d days average)", cpu, memory, utilizationDays)
+			},
+			CacheKey: func(target interface{}, scanContext *scanners.ScanContext) interface{} {
+				c := target.(*armappservice.Plan)
+				return []interface{}{c.ID, c.SKU, a.config.WithMetrics}
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/app-service/manage-scale-up",
+		},
 	}
 }
 
@@ -111,12 +282,7 @@ func (a *AppServiceScanner) GetAppRules() map[string]scanners.AzureRule {
 			Severity:    "Medium",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				service := target.(*armappservice.Site)
-				hasDiagnostics, err := a.diagnosticsSettings.HasDiagnostics(*service.ID)
-				if err != nil {
-					log.Fatalf("Error checking diagnostic settings for service %s: %s", *service.Name, err)
-				}
-
-				return !hasDiagnostics, ""
+				return a.diagnosticsSettings.EvalDiagnostics("app-001", *service.ID, scanContext)
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/app-service/troubleshoot-diagnostic-logs#send-logs-to-azure-monitor",
 		},
@@ -141,7 +307,7 @@ func (a *AppServiceScanner) GetAppRules() map[string]scanners.AzureRule {
 			Severity:    "Low",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armappservice.Site)
-				caf := strings.HasPrefix(*c.Name, "app")
+				caf := scanners.HasCAFPrefix(*c.Name, "app", scanContext)
 				return !caf, ""
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
@@ -171,6 +337,91 @@ func (a *AppServiceScanner) GetAppRules() map[string]scanners.AzureRule {
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
+		"app-015": {
+			Id:          "app-015",
+			Category:    "Security",
+			Subcategory: "Networking",
+			Description: "App Service should have VNET integration enabled",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armappservice.Site)
+				vnet := c.Properties != nil && c.Properties.VirtualNetworkSubnetID != nil && *c.Properties.VirtualNetworkSubnetID != ""
+				return !vnet, ""
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/app-service/overview-vnet-integration",
+		},
+		"app-016": {
+			Id:          "app-016",
+			Category:    "Security",
+			Subcategory: "Identity and Access Control",
+			Description: "App Service should use Managed Identities",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armappservice.Site)
+				broken := c.Identity == nil || c.Identity.Type == nil || *c.Identity.Type == armappservice.ManagedServiceIdentityTypeNone
+				return broken, ""
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/app-service/overview-managed-identity",
+		},
+		"app-017": {
+			Id:          "app-017",
+			Category:    "Security",
+			Subcategory: "Runtime",
+			Description: "App Service should not use an end-of-life runtime stack",
+			Severity:    "High",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armappservice.Site)
+				stack := getRuntimeStack(c)
+				return isEOLStack(stack, time.Now()), stack
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/app-service/language-support-policy",
+		},
+		"app-018": {
+			Id:          "app-018",
+			Category:    "High Availability and Resiliency",
+			Subcategory: "Reliability",
+			Description: "App Service should use a 64-bit worker process to use more than 2 GB of memory",
+			Severity:    "Low",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armappservice.Site)
+				if c.Properties == nil || c.Properties.SiteConfig == nil || c.Properties.SiteConfig.Use32BitWorkerProcess == nil {
+					return false, ""
+				}
+				if *c.Properties.SiteConfig.Use32BitWorkerProcess {
+					return true, "32-bit"
+				}
+				return false, "64-bit"
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/app-service/configure-common#configure-general-settings",
+		},
+		"app-019": {
+			Id:          "app-019",
+			Category:    "High Availability and Resiliency",
+			Subcategory: "Reliability",
+			Description: "App Service should use deployment slots for zero-downtime deployments of production apps",
+			Severity:    "Low",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armappservice.Site)
+				// Only production apps need slots, don't list the slots of the others
+				if !scanners.IsProduction(c.Tags) {
+					return false, ""
+				}
+				resource, err := arm.ParseResourceID(*c.ID)
+				if err != nil {
+					return scanContext.RuleError("app-019", *c.ID, err)
+				}
+				slots, err := a.listSlots(resource.ResourceGroupName, *c.Name)
+				if err != nil {
+					return scanContext.RuleError("app-019", *c.ID, fmt.Errorf("listing deployment slots: %w", err))
+				}
+				return len(slots) == 0, fmt.Sprintf("%d slots", len(slots))
+			},
+			CacheKey: func(target interface{}, scanContext *scanners.ScanContext) interface{} {
+				c := target.(*armappservice.Site)
+				return []interface{}{c.ID, scanners.IsProduction(c.Tags)}
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/app-service/deploy-staging-slots",
+		},
 	}
 }
 
@@ -185,12 +436,7 @@ func (a *AppServiceScanner) GetFunctionRules() map[string]scanners.AzureRule {
 			Severity:    "Medium",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				service := target.(*armappservice.Site)
-				hasDiagnostics, err := a.diagnosticsSettings.HasDiagnostics(*service.ID)
-				if err != nil {
-					log.Fatalf("Error checking diagnostic settings for service %s: %s", *service.Name, err)
-				}
-
-				return !hasDiagnostics, ""
+				return a.diagnosticsSettings.EvalDiagnostics("func-001", *service.ID, scanContext)
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/azure-functions/functions-monitor-log-analytics?tabs=csharp",
 		},
@@ -215,7 +461,7 @@ func (a *AppServiceScanner) GetFunctionRules() map[string]scanners.AzureRule {
 			Severity:    "Low",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armappservice.Site)
-				caf := strings.HasPrefix(*c.Name, "func")
+				caf := scanners.HasCAFPrefix(*c.Name, "func", scanContext)
 				return !caf, ""
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
@@ -245,5 +491,65 @@ func (a *AppServiceScanner) GetFunctionRules() map[string]scanners.AzureRule {
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
+		"func-010": {
+			Id:          "func-010",
+			Category:    "Operations",
+			Subcategory: "Scalability",
+			Description: "Premium Function with VNET integration should have runtime scale monitoring enabled",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armappservice.Site)
+				if c.Properties == nil || c.Properties.ServerFarmID == nil {
+					return false, ""
+				}
+				if a.getPlanTier(*c.Properties.ServerFarmID) != "ElasticPremium" {
+					return false, ""
+				}
+				if c.Properties.VirtualNetworkSubnetID == nil || *c.Properties.VirtualNetworkSubnetID == "" {
+					return false, ""
+				}
+
+				if c.Properties.SiteConfig == nil || c.Properties.SiteConfig.FunctionsRuntimeScaleMonitoringEnabled == nil {
+					return false, ""
+				}
+				return !*c.Properties.SiteConfig.FunctionsRuntimeScaleMonitoringEnabled, ""
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/azure-functions/functions-networking-options#premium-plan-with-virtual-network-triggers",
+		},
+		"func-011": {
+			Id:          "func-011",
+			Category:    "Security",
+			Subcategory: "Networking",
+			Description: "Function should use a storage account that isn't publicly accessible",
+			Severity:    "High",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armappservice.Site)
+				account, public, ok := a.getFunctionStorage(*c.ID)
+				if !ok {
+					return false, ""
+				}
+				return public, account
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/azure-functions/configure-networking-how-to#restrict-your-storage-account-to-a-virtual-network",
+		},
+		"func-012": {
+			Id:          "func-012",
+			Category:    "Monitoring and Logging",
+			Subcategory: "Monitoring",
+			Description: "Function should have Application Insights configured",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armappservice.Site)
+				configured, ok := a.getFunctionAppInsights(*c.ID)
+				if !ok {
+					return false, ""
+				}
+				if configured {
+					return false, "Application Insights configured"
+				}
+				return true, "Application Insights not configured"
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/azure-functions/configure-monitoring#enable-application-insights-integration",
+		},
 	}
 }
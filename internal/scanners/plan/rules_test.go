@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/cmendible/azqr/internal/scanners"
 )
@@ -98,6 +99,60 @@ func TestAppServiceScanner_Rules(t *testing.T) {
 				result: "99.95%",
 			},
 		},
+		{
+			name: "AppServiceScanner SLA PremiumV3",
+			fields: fields{
+				rule: "SLA",
+				target: &armappservice.Plan{
+					SKU: &armappservice.SKUDescription{
+						Tier: to.StringPtr("PremiumV3"),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "99.95%",
+			},
+		},
+		{
+			name: "AppServiceScanner SLA Isolated",
+			fields: fields{
+				rule: "SLA",
+				target: &armappservice.Plan{
+					SKU: &armappservice.SKUDescription{
+						Tier: to.StringPtr("Isolated"),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "99.95%",
+			},
+		},
+		{
+			name: "AppServiceScanner SLA IsolatedV2 zone redundant",
+			fields: fields{
+				rule: "SLA",
+				target: &armappservice.Plan{
+					SKU: &armappservice.SKUDescription{
+						Tier: to.StringPtr("IsolatedV2"),
+					},
+					Properties: &armappservice.PlanProperties{
+						ZoneRedundant: to.BoolPtr(true),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "99.99%",
+			},
+		},
 		{
 			name: "AppServiceScanner SKU",
 			fields: fields{
@@ -130,6 +185,207 @@ func TestAppServiceScanner_Rules(t *testing.T) {
 				result: "",
 			},
 		},
+		{
+			name: "AppServiceScanner Plan without sites",
+			fields: fields{
+				rule: "plan-011",
+				target: &armappservice.Plan{
+					Properties: &armappservice.PlanProperties{
+						NumberOfSites: to.Int32Ptr(0),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "0",
+			},
+		},
+		{
+			name: "AppServiceScanner Plan with sites",
+			fields: fields{
+				rule: "plan-011",
+				target: &armappservice.Plan{
+					Properties: &armappservice.PlanProperties{
+						NumberOfSites: to.Int32Ptr(3),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "3",
+			},
+		},
+		{
+			name: "AppServiceScanner production Plan with autoscale",
+			fields: fields{
+				rule: "plan-012",
+				target: &armappservice.Plan{
+					ID:   to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/serverfarms/plan"),
+					Tags: map[string]*string{"env": to.StringPtr("prod")},
+				},
+				scanContext: &scanners.ScanContext{
+					AutoscaleTargets: map[string]bool{
+						"/subscriptions/sub/resourcegroups/rg/providers/microsoft.web/serverfarms/plan": true,
+					},
+				},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "Autoscale Configured",
+			},
+		},
+		{
+			name: "AppServiceScanner production Plan without autoscale",
+			fields: fields{
+				rule: "plan-012",
+				target: &armappservice.Plan{
+					ID:   to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/serverfarms/plan"),
+					Tags: map[string]*string{"env": to.StringPtr("prod")},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "No Autoscale",
+			},
+		},
+		{
+			name: "AppServiceScanner zone redundant Plan with 1 worker",
+			fields: fields{
+				rule: "plan-013",
+				target: &armappservice.Plan{
+					Properties: &armappservice.PlanProperties{
+						ZoneRedundant:   to.BoolPtr(true),
+						NumberOfWorkers: to.Int32Ptr(1),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "1 workers",
+			},
+		},
+		{
+			name: "AppServiceScanner zone redundant Plan with 3 workers",
+			fields: fields{
+				rule: "plan-013",
+				target: &armappservice.Plan{
+					SKU: &armappservice.SKUDescription{
+						Capacity: to.Int32Ptr(3),
+					},
+					Properties: &armappservice.PlanProperties{
+						ZoneRedundant: to.BoolPtr(true),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "3 workers",
+			},
+		},
+		{
+			name: "AppServiceScanner diagnostic settings with metrics",
+			fields: fields{
+				rule: "plan-014",
+				target: &armappservice.Plan{
+					ID: to.StringPtr("test"),
+				},
+				scanContext: &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{
+					ListDiagnosticSettingsFunc: func(resourceId string) ([]*armmonitor.DiagnosticSettingsResource, error) {
+						return []*armmonitor.DiagnosticSettingsResource{
+							{
+								Properties: &armmonitor.DiagnosticSettings{
+									Metrics: []*armmonitor.MetricSettings{
+										{Category: to.StringPtr("AllMetrics"), Enabled: to.BoolPtr(true)},
+									},
+								},
+							},
+						}, nil
+					},
+				},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "AppServiceScanner diagnostic settings with logs only",
+			fields: fields{
+				rule: "plan-014",
+				target: &armappservice.Plan{
+					ID: to.StringPtr("test"),
+				},
+				scanContext: &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{
+					ListDiagnosticSettingsFunc: func(resourceId string) ([]*armmonitor.DiagnosticSettingsResource, error) {
+						return []*armmonitor.DiagnosticSettingsResource{
+							{
+								Properties: &armmonitor.DiagnosticSettings{
+									Logs: []*armmonitor.LogSettings{
+										{CategoryGroup: to.StringPtr("allLogs"), Enabled: to.BoolPtr(true)},
+									},
+									Metrics: []*armmonitor.MetricSettings{
+										{Category: to.StringPtr("AllMetrics"), Enabled: to.BoolPtr(false)},
+									},
+								},
+							},
+						}, nil
+					},
+				},
+			},
+			want: want{
+				broken: true,
+				result: "",
+			},
+		},
+		{
+			name: "AppServiceScanner PremiumV3 plan reserved instance candidate",
+			fields: fields{
+				rule: "plan-015",
+				target: &armappservice.Plan{
+					SKU: &armappservice.SKUDescription{
+						Name: to.StringPtr("P1v3"),
+						Tier: to.StringPtr("PremiumV3"),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "PremiumV3 (P1v3): reserved instance candidate",
+			},
+		},
+		{
+			name: "AppServiceScanner Free plan not eligible for reserved instances",
+			fields: fields{
+				rule: "plan-015",
+				target: &armappservice.Plan{
+					SKU: &armappservice.SKUDescription{
+						Name: to.StringPtr("F1"),
+						Tier: to.StringPtr("Free"),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "Free (F1): not eligible",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -235,54 +491,17 @@ func TestAppServiceScanner_AppRules(t *testing.T) {
 				result: "",
 			},
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			s := &AppServiceScanner{
-				diagnosticsSettings: tt.fields.diagnosticsSettings,
-			}
-			rules := s.GetAppRules()
-			b, w := rules[tt.fields.rule].Eval(tt.fields.target, tt.fields.scanContext)
-			got := want{
-				broken: b,
-				result: w,
-			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("AppServiceScanner Rule.Eval() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
-func TestAppServiceScanner_FunctionRules(t *testing.T) {
-	type fields struct {
-		rule                string
-		target              interface{}
-		scanContext         *scanners.ScanContext
-		diagnosticsSettings scanners.DiagnosticsSettings
-	}
-	type want struct {
-		broken bool
-		result string
-	}
-	tests := []struct {
-		name   string
-		fields fields
-		want   want
-	}{
 		{
-			name: "AppServiceScanner DiagnosticSettings",
+			name: "AppServiceScanner VNET integration",
 			fields: fields{
-				rule: "DiagnosticSettings",
+				rule: "app-015",
 				target: &armappservice.Site{
-					ID: to.StringPtr("test"),
-				},
-				scanContext: &scanners.ScanContext{},
-				diagnosticsSettings: scanners.DiagnosticsSettings{
-					HasDiagnosticsFunc: func(resourceId string) (bool, error) {
-						return true, nil
+					Properties: &armappservice.SiteProperties{
+						VirtualNetworkSubnetID: to.StringPtr("subnetId"),
 					},
 				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
 			},
 			want: want{
 				broken: false,
@@ -290,30 +509,28 @@ func TestAppServiceScanner_FunctionRules(t *testing.T) {
 			},
 		},
 		{
-			name: "AppServiceScanner Private Endpoint",
+			name: "AppServiceScanner without VNET integration",
 			fields: fields{
-				rule: "Private",
+				rule: "app-015",
 				target: &armappservice.Site{
-					ID: to.StringPtr("test"),
-				},
-				scanContext: &scanners.ScanContext{
-					PrivateEndpoints: map[string]bool{
-						"test": true,
-					},
+					Properties: &armappservice.SiteProperties{},
 				},
+				scanContext:         &scanners.ScanContext{},
 				diagnosticsSettings: scanners.DiagnosticsSettings{},
 			},
 			want: want{
-				broken: false,
+				broken: true,
 				result: "",
 			},
 		},
 		{
-			name: "AppServiceScanner CAF",
+			name: "AppServiceScanner Managed Identity",
 			fields: fields{
-				rule: "CAF",
+				rule: "app-016",
 				target: &armappservice.Site{
-					Name: to.StringPtr("func-test"),
+					Identity: &armappservice.ManagedServiceIdentity{
+						Type: getManagedServiceIdentityType(armappservice.ManagedServiceIdentityTypeSystemAssigned),
+					},
 				},
 				scanContext:         &scanners.ScanContext{},
 				diagnosticsSettings: scanners.DiagnosticsSettings{},
@@ -324,28 +541,453 @@ func TestAppServiceScanner_FunctionRules(t *testing.T) {
 			},
 		},
 		{
-			name: "AppServiceScanner HTTPS only",
+			name: "AppServiceScanner Managed Identity None",
 			fields: fields{
-				rule: "func-007",
+				rule: "app-016",
 				target: &armappservice.Site{
-					Properties: &armappservice.SiteProperties{
-						HTTPSOnly: to.BoolPtr(true),
+					Identity: &armappservice.ManagedServiceIdentity{
+						Type: getManagedServiceIdentityType(armappservice.ManagedServiceIdentityTypeNone),
 					},
 				},
 				scanContext:         &scanners.ScanContext{},
 				diagnosticsSettings: scanners.DiagnosticsSettings{},
 			},
 			want: want{
-				broken: false,
+				broken: true,
 				result: "",
 			},
 		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			s := &AppServiceScanner{
-				diagnosticsSettings: tt.fields.diagnosticsSettings,
-			}
+		{
+			name: "AppServiceScanner without Managed Identity",
+			fields: fields{
+				rule:                "app-016",
+				target:              &armappservice.Site{},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "",
+			},
+		},
+		{
+			name: "AppServiceScanner EOL runtime stack",
+			fields: fields{
+				rule: "app-017",
+				target: &armappservice.Site{
+					Properties: &armappservice.SiteProperties{
+						SiteConfig: &armappservice.SiteConfig{
+							LinuxFxVersion: to.StringPtr("NODE|14-lts"),
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "NODE|14-lts",
+			},
+		},
+		{
+			name: "AppServiceScanner supported runtime stack",
+			fields: fields{
+				rule: "app-017",
+				target: &armappservice.Site{
+					Properties: &armappservice.SiteProperties{
+						SiteConfig: &armappservice.SiteConfig{
+							LinuxFxVersion: to.StringPtr("DOTNETCORE|10.0"),
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "DOTNETCORE|10.0",
+			},
+		},
+		{
+			name: "AppServiceScanner without runtime stack",
+			fields: fields{
+				rule: "app-017",
+				target: &armappservice.Site{
+					Properties: &armappservice.SiteProperties{},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "AppServiceScanner 32-bit worker process",
+			fields: fields{
+				rule: "app-018",
+				target: &armappservice.Site{
+					Properties: &armappservice.SiteProperties{
+						SiteConfig: &armappservice.SiteConfig{
+							Use32BitWorkerProcess: to.BoolPtr(true),
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "32-bit",
+			},
+		},
+		{
+			name: "AppServiceScanner 64-bit worker process",
+			fields: fields{
+				rule: "app-018",
+				target: &armappservice.Site{
+					Properties: &armappservice.SiteProperties{
+						SiteConfig: &armappservice.SiteConfig{
+							Use32BitWorkerProcess: to.BoolPtr(false),
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "64-bit",
+			},
+		},
+		{
+			name: "AppServiceScanner without site config",
+			fields: fields{
+				rule: "app-018",
+				target: &armappservice.Site{
+					Properties: &armappservice.SiteProperties{},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &AppServiceScanner{
+				diagnosticsSettings: tt.fields.diagnosticsSettings,
+			}
+			rules := s.GetAppRules()
+			b, w := rules[tt.fields.rule].Eval(tt.fields.target, tt.fields.scanContext)
+			got := want{
+				broken: b,
+				result: w,
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AppServiceScanner Rule.Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppServiceScanner_FunctionRules(t *testing.T) {
+	type fields struct {
+		rule                    string
+		target                  interface{}
+		scanContext             *scanners.ScanContext
+		diagnosticsSettings     scanners.DiagnosticsSettings
+		planTiers               map[string]string
+		functionStorageAccounts map[string]string
+		publicStorageAccounts   map[string]bool
+		functionAppInsights     map[string]bool
+	}
+	type want struct {
+		broken bool
+		result string
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   want
+	}{
+		{
+			name: "AppServiceScanner DiagnosticSettings",
+			fields: fields{
+				rule: "DiagnosticSettings",
+				target: &armappservice.Site{
+					ID: to.StringPtr("test"),
+				},
+				scanContext: &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{
+					HasDiagnosticsFunc: func(resourceId string) (bool, error) {
+						return true, nil
+					},
+				},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "AppServiceScanner Private Endpoint",
+			fields: fields{
+				rule: "Private",
+				target: &armappservice.Site{
+					ID: to.StringPtr("test"),
+				},
+				scanContext: &scanners.ScanContext{
+					PrivateEndpoints: map[string]bool{
+						"test": true,
+					},
+				},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "AppServiceScanner CAF",
+			fields: fields{
+				rule: "CAF",
+				target: &armappservice.Site{
+					Name: to.StringPtr("func-test"),
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "AppServiceScanner HTTPS only",
+			fields: fields{
+				rule: "func-007",
+				target: &armappservice.Site{
+					Properties: &armappservice.SiteProperties{
+						HTTPSOnly: to.BoolPtr(true),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "AppServiceScanner runtime scale monitoring enabled",
+			fields: fields{
+				rule: "func-010",
+				target: &armappservice.Site{
+					Properties: &armappservice.SiteProperties{
+						ServerFarmID:           to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/serverfarms/plan"),
+						VirtualNetworkSubnetID: to.StringPtr("subnetId"),
+						SiteConfig: &armappservice.SiteConfig{
+							FunctionsRuntimeScaleMonitoringEnabled: to.BoolPtr(true),
+						},
+					},
+				},
+				scanContext: &scanners.ScanContext{},
+				planTiers: map[string]string{
+					"/subscriptions/sub/resourcegroups/rg/providers/microsoft.web/serverfarms/plan": "ElasticPremium",
+				},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "AppServiceScanner runtime scale monitoring disabled",
+			fields: fields{
+				rule: "func-010",
+				target: &armappservice.Site{
+					Properties: &armappservice.SiteProperties{
+						ServerFarmID:           to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/serverfarms/plan"),
+						VirtualNetworkSubnetID: to.StringPtr("subnetId"),
+						SiteConfig: &armappservice.SiteConfig{
+							FunctionsRuntimeScaleMonitoringEnabled: to.BoolPtr(false),
+						},
+					},
+				},
+				scanContext: &scanners.ScanContext{},
+				planTiers: map[string]string{
+					"/subscriptions/sub/resourcegroups/rg/providers/microsoft.web/serverfarms/plan": "ElasticPremium",
+				},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "",
+			},
+		},
+		{
+			name: "AppServiceScanner runtime scale monitoring unknown",
+			fields: fields{
+				rule: "func-010",
+				target: &armappservice.Site{
+					Properties: &armappservice.SiteProperties{
+						ServerFarmID:           to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/serverfarms/plan"),
+						VirtualNetworkSubnetID: to.StringPtr("subnetId"),
+						SiteConfig:             &armappservice.SiteConfig{},
+					},
+				},
+				scanContext: &scanners.ScanContext{},
+				planTiers: map[string]string{
+					"/subscriptions/sub/resourcegroups/rg/providers/microsoft.web/serverfarms/plan": "ElasticPremium",
+				},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "AppServiceScanner runtime scale monitoring without site config",
+			fields: fields{
+				rule: "func-010",
+				target: &armappservice.Site{
+					Properties: &armappservice.SiteProperties{
+						ServerFarmID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/serverfarms/plan"),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "AppServiceScanner function with private storage",
+			fields: fields{
+				rule: "func-011",
+				target: &armappservice.Site{
+					ID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/sites/func"),
+				},
+				scanContext: &scanners.ScanContext{},
+				functionStorageAccounts: map[string]string{
+					"/subscriptions/sub/resourcegroups/rg/providers/microsoft.web/sites/func": "stfunc",
+				},
+				publicStorageAccounts: map[string]bool{},
+				diagnosticsSettings:   scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "stfunc",
+			},
+		},
+		{
+			name: "AppServiceScanner function with public storage",
+			fields: fields{
+				rule: "func-011",
+				target: &armappservice.Site{
+					ID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/sites/func"),
+				},
+				scanContext: &scanners.ScanContext{},
+				functionStorageAccounts: map[string]string{
+					"/subscriptions/sub/resourcegroups/rg/providers/microsoft.web/sites/func": "stFunc",
+				},
+				publicStorageAccounts: map[string]bool{
+					"stfunc": true,
+				},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "stFunc",
+			},
+		},
+		{
+			name: "AppServiceScanner function without storage settings",
+			fields: fields{
+				rule: "func-011",
+				target: &armappservice.Site{
+					ID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/sites/func"),
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "AppServiceScanner function with Application Insights",
+			fields: fields{
+				rule: "func-012",
+				target: &armappservice.Site{
+					ID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/sites/func"),
+				},
+				scanContext: &scanners.ScanContext{},
+				functionAppInsights: map[string]bool{
+					"/subscriptions/sub/resourcegroups/rg/providers/microsoft.web/sites/func": true,
+				},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "Application Insights configured",
+			},
+		},
+		{
+			name: "AppServiceScanner function without Application Insights",
+			fields: fields{
+				rule: "func-012",
+				target: &armappservice.Site{
+					ID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/sites/func"),
+				},
+				scanContext: &scanners.ScanContext{},
+				functionAppInsights: map[string]bool{
+					"/subscriptions/sub/resourcegroups/rg/providers/microsoft.web/sites/func": false,
+				},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "Application Insights not configured",
+			},
+		},
+		{
+			name: "AppServiceScanner function with unreadable app settings",
+			fields: fields{
+				rule: "func-012",
+				target: &armappservice.Site{
+					ID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/sites/func"),
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &AppServiceScanner{
+				diagnosticsSettings:     tt.fields.diagnosticsSettings,
+				planTiers:               tt.fields.planTiers,
+				functionStorageAccounts: tt.fields.functionStorageAccounts,
+				publicStorageAccounts:   tt.fields.publicStorageAccounts,
+				functionAppInsights:     tt.fields.functionAppInsights,
+			}
 			rules := s.GetFunctionRules()
 			b, w := rules[tt.fields.rule].Eval(tt.fields.target, tt.fields.scanContext)
 			got := want{
@@ -358,3 +1000,181 @@ func TestAppServiceScanner_FunctionRules(t *testing.T) {
 		})
 	}
 }
+
+func TestAppServiceScanner_SlotsRule(t *testing.T) {
+	type want struct {
+		broken bool
+		result string
+	}
+	tests := []struct {
+		name  string
+		slots []*armappservice.Site
+		tags  map[string]*string
+		want  want
+	}{
+		{
+			name: "AppServiceScanner production app with slots",
+			slots: []*armappservice.Site{
+				{Name: to.StringPtr("app-test/staging")},
+				{Name: to.StringPtr("app-test/canary")},
+			},
+			tags: map[string]*string{"env": to.StringPtr("prod")},
+			want: want{
+				broken: false,
+				result: "2 slots",
+			},
+		},
+		{
+			name:  "AppServiceScanner production app without slots",
+			slots: []*armappservice.Site{},
+			tags:  map[string]*string{"env": to.StringPtr("prod")},
+			want: want{
+				broken: true,
+				result: "0 slots",
+			},
+		},
+		{
+			name: "AppServiceScanner non production app",
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &AppServiceScanner{
+				listSlotsFunc: func(resourceGroupName string, siteName string) ([]*armappservice.Site, error) {
+					if tt.slots == nil {
+						t.Fatalf("listSlots called for a non production app")
+					}
+					return tt.slots, nil
+				},
+			}
+			rules := s.GetAppRules()
+			b, w := rules["app-019"].Eval(&armappservice.Site{
+				ID:   to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/sites/app-test"),
+				Name: to.StringPtr("app-test"),
+				Tags: tt.tags,
+			}, &scanners.ScanContext{})
+			got := want{
+				broken: b,
+				result: w,
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AppServiceScanner Rule.Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// getMetric - Returns a metric with a data point for each average
+func getMetric(name string, averages ...float64) *armmonitor.Metric {
+	data := []*armmonitor.MetricValue{}
+	for _, average := range averages {
+		data = append(data, &armmonitor.MetricValue{Average: to.Float64Ptr(average)})
+	}
+	return &armmonitor.Metric{
+		Name:       &armmonitor.LocalizableString{Value: to.StringPtr(name)},
+		Timeseries: []*armmonitor.TimeSeriesElement{{Data: data}},
+	}
+}
+
+func TestAppServiceScanner_MetricsRule(t *testing.T) {
+	type want struct {
+		broken bool
+		result string
+	}
+	tests := []struct {
+		name        string
+		withMetrics bool
+		tier        string
+		metrics     []*armmonitor.Metric
+		want        want
+	}{
+		{
+			name:        "AppServiceScanner plan with low usage",
+			withMetrics: true,
+			tier:        "PremiumV3",
+			metrics: []*armmonitor.Metric{
+				getMetric("CpuPercentage", 4, 6, 8),
+				getMetric("MemoryPercentage", 30, 35),
+			},
+			want: want{
+				broken: true,
+				result: "CPU 6.0%, Memory 32.5% (7 days average)",
+			},
+		},
+		{
+			name:        "AppServiceScanner plan with high CPU usage",
+			withMetrics: true,
+			tier:        "PremiumV3",
+			metrics: []*armmonitor.Metric{
+				getMetric("CpuPercentage", 70, 80),
+				getMetric("MemoryPercentage", 30),
+			},
+			want: want{
+				broken: false,
+				result: "CPU 75.0%, Memory 30.0% (7 days average)",
+			},
+		},
+		{
+			name:        "AppServiceScanner plan without metrics",
+			withMetrics: true,
+			tier:        "Standard",
+			metrics:     []*armmonitor.Metric{},
+			want: want{
+				broken: false,
+				result: "No metrics",
+			},
+		},
+		{
+			name:        "AppServiceScanner consumption plan",
+			withMetrics: true,
+			tier:        "Dynamic",
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name:        "AppServiceScanner plan without --with-metrics",
+			withMetrics: false,
+			tier:        "PremiumV3",
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &AppServiceScanner{
+				config: &scanners.ScannerConfig{WithMetrics: tt.withMetrics},
+				listMetricsFunc: func(resourceID string) ([]*armmonitor.Metric, error) {
+					if tt.metrics == nil {
+						t.Errorf("metrics of %s should not be listed", resourceID)
+					}
+					return tt.metrics, nil
+				},
+			}
+			rules := s.GetRules()
+			b, w := rules["plan-016"].Eval(&armappservice.Plan{
+				ID:   to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/serverfarms/plan"),
+				Name: to.StringPtr("plan"),
+				SKU:  &armappservice.SKUDescription{Tier: to.StringPtr(tt.tier)},
+			}, &scanners.ScanContext{})
+			got := want{
+				broken: b,
+				result: w,
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AppServiceScanner Rule.Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func getManagedServiceIdentityType(t armappservice.ManagedServiceIdentityType) *armappservice.ManagedServiceIdentityType {
+	return &t
+}
@@ -42,7 +42,7 @@ func (c *PostgreScanner) Scan(resourceGroupName string, scanContext *scanners.Sc
 	if err != nil {
 		return nil, err
 	}
-	engine := scanners.RuleEngine{}
+	engine := scanners.RuleEngine{Cache: c.config.RuleCache}
 	rules := c.GetRules()
 	results := []scanners.AzureServiceResult{}
 
@@ -52,9 +52,12 @@ func (c *PostgreScanner) Scan(resourceGroupName string, scanContext *scanners.Sc
 		results = append(results, scanners.AzureServiceResult{
 			SubscriptionID: c.config.SubscriptionID,
 			ResourceGroup:  resourceGroupName,
+			ResourceID:     *postgre.ID,
 			ServiceName:    *postgre.Name,
 			Type:           *postgre.Type,
 			Location:       *postgre.Location,
+			Tags:           postgre.Tags,
+			CreatedAt:      scanners.GetCreatedAt(postgre),
 			Rules:          rr,
 		})
 	}
@@ -73,9 +76,17 @@ func (c *PostgreScanner) listPostgre(resourceGroupName string) ([]*armpostgresql
 				return nil, err
 			}
 			servers = append(servers, resp.Value...)
+			if limited, ok := scanners.LimitResources(c.config, servers); ok {
+				return limited, nil
+			}
 		}
 		return servers, nil
 	}
 
 	return c.listPostgreFunc(resourceGroupName)
 }
+
+// GetResourceTypes - Returns the resource types scanned by the PostgreScanner
+func (c *PostgreScanner) GetResourceTypes() []string {
+	return []string{"Microsoft.DBforPostgreSQL/servers"}
+}
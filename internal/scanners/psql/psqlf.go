@@ -42,7 +42,7 @@ func (c *PostgreFlexibleScanner) Scan(resourceGroupName string, scanContext *sca
 	if err != nil {
 		return nil, err
 	}
-	engine := scanners.RuleEngine{}
+	engine := scanners.RuleEngine{Cache: c.config.RuleCache}
 	rules := c.GetRules()
 	results := []scanners.AzureServiceResult{}
 
@@ -52,9 +52,12 @@ func (c *PostgreFlexibleScanner) Scan(resourceGroupName string, scanContext *sca
 		results = append(results, scanners.AzureServiceResult{
 			SubscriptionID: c.config.SubscriptionID,
 			ResourceGroup:  resourceGroupName,
+			ResourceID:     *postgre.ID,
 			ServiceName:    *postgre.Name,
 			Type:           *postgre.Type,
 			Location:       *postgre.Location,
+			Tags:           postgre.Tags,
+			CreatedAt:      scanners.GetCreatedAt(postgre),
 			Rules:          rr,
 		})
 	}
@@ -72,9 +75,17 @@ func (c *PostgreFlexibleScanner) listFlexiblePostgre(resourceGroupName string) (
 				return nil, err
 			}
 			servers = append(servers, resp.Value...)
+			if limited, ok := scanners.LimitResources(c.config, servers); ok {
+				return limited, nil
+			}
 		}
 		return servers, nil
 	}
 
 	return c.listFlexibleFunc(resourceGroupName)
 }
+
+// GetResourceTypes - Returns the resource types scanned by the PostgreFlexibleScanner
+func (c *PostgreFlexibleScanner) GetResourceTypes() []string {
+	return []string{"Microsoft.DBforPostgreSQL/flexibleServers"}
+}
@@ -0,0 +1,59 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"reflect"
+	"strings"
+)
+
+// GetPublicNetworkAccess - Returns the value of target.Properties.PublicNetworkAccess, if the resource has one
+func GetPublicNetworkAccess(target interface{}) (string, bool) {
+	v := reflect.ValueOf(target)
+	for _, field := range []string{"Properties", "PublicNetworkAccess"} {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return "", false
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return "", false
+		}
+		v = v.FieldByName(field)
+		if !v.IsValid() {
+			return "", false
+		}
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.String {
+		return "", false
+	}
+	return v.String(), true
+}
+
+// NewPublicNetworkAccessRule - Returns a rule flagging resources with Properties.PublicNetworkAccess set to Enabled
+func NewPublicNetworkAccessRule(id, service, url string) AzureRule {
+	return AzureRule{
+		Id:          id,
+		Category:    "Security",
+		Subcategory: "Networking",
+		Description: service + " should have public network access disabled",
+		Severity:    "High",
+		Eval: func(target interface{}, scanContext *ScanContext) (bool, string) {
+			access, ok := GetPublicNetworkAccess(target)
+			if !ok {
+				return false, ""
+			}
+			return strings.EqualFold(access, "Enabled"), access
+		},
+		Url: url,
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/cosmos/armcosmos"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/signalr/armsignalr"
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+func TestNewPublicNetworkAccessRule(t *testing.T) {
+	type want struct {
+		broken bool
+		result string
+	}
+	enabled := armcosmos.PublicNetworkAccessEnabled
+	disabled := armcosmos.PublicNetworkAccessDisabled
+	tests := []struct {
+		name   string
+		target interface{}
+		want   want
+	}{
+		{
+			name: "Cosmos DB public network access enabled",
+			target: &armcosmos.DatabaseAccountGetResults{
+				Properties: &armcosmos.DatabaseAccountGetProperties{
+					PublicNetworkAccess: &enabled,
+				},
+			},
+			want: want{
+				broken: true,
+				result: "Enabled",
+			},
+		},
+		{
+			name: "Cosmos DB public network access disabled",
+			target: &armcosmos.DatabaseAccountGetResults{
+				Properties: &armcosmos.DatabaseAccountGetProperties{
+					PublicNetworkAccess: &disabled,
+				},
+			},
+			want: want{
+				broken: false,
+				result: "Disabled",
+			},
+		},
+		{
+			name: "SignalR public network access enabled",
+			target: &armsignalr.ResourceInfo{
+				Properties: &armsignalr.Properties{
+					PublicNetworkAccess: to.StringPtr("Enabled"),
+				},
+			},
+			want: want{
+				broken: true,
+				result: "Enabled",
+			},
+		},
+		{
+			name:   "SignalR without properties",
+			target: &armsignalr.ResourceInfo{},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name:   "Resource without public network access",
+			target: &struct{ Name *string }{},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := NewPublicNetworkAccessRule("test-001", "Test", "")
+			b, w := rule.Eval(tt.target, &ScanContext{})
+			got := want{
+				broken: b,
+				result: w,
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewPublicNetworkAccessRule().Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
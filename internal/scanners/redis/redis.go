@@ -42,7 +42,7 @@ func (c *RedisScanner) Scan(resourceGroupName string, scanContext *scanners.Scan
 	if err != nil {
 		return nil, err
 	}
-	engine := scanners.RuleEngine{}
+	engine := scanners.RuleEngine{Cache: c.config.RuleCache}
 	rules := c.GetRules()
 	results := []scanners.AzureServiceResult{}
 
@@ -52,9 +52,12 @@ func (c *RedisScanner) Scan(resourceGroupName string, scanContext *scanners.Scan
 		results = append(results, scanners.AzureServiceResult{
 			SubscriptionID: c.config.SubscriptionID,
 			ResourceGroup:  resourceGroupName,
+			ResourceID:     *redis.ID,
 			ServiceName:    *redis.Name,
 			Type:           *redis.Type,
 			Location:       *redis.Location,
+			Tags:           redis.Tags,
+			CreatedAt:      scanners.GetCreatedAt(redis),
 			Rules:          rr,
 		})
 	}
@@ -72,9 +75,17 @@ func (c *RedisScanner) listRedis(resourceGroupName string) ([]*armredis.Resource
 				return nil, err
 			}
 			redis = append(redis, resp.Value...)
+			if limited, ok := scanners.LimitResources(c.config, redis); ok {
+				return limited, nil
+			}
 		}
 		return redis, nil
 	}
 
 	return c.listRedisFunc(resourceGroupName)
 }
+
+// GetResourceTypes - Returns the resource types scanned by the RedisScanner
+func (c *RedisScanner) GetResourceTypes() []string {
+	return []string{"Microsoft.Cache/Redis"}
+}
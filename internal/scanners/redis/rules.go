@@ -4,7 +4,6 @@
 package redis
 
 import (
-	"log"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/redis/armredis"
@@ -22,12 +21,7 @@ func (a *RedisScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Medium",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				service := target.(*armredis.ResourceInfo)
-				hasDiagnostics, err := a.diagnosticsSettings.HasDiagnostics(*service.ID)
-				if err != nil {
-					log.Fatalf("Error checking diagnostic settings for service %s: %s", *service.Name, err)
-				}
-
-				return !hasDiagnostics, ""
+				return a.diagnosticsSettings.EvalDiagnostics("redis-001", *service.ID, scanContext)
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/azure-cache-for-redis/cache-monitor-diagnostic-settings",
 		},
@@ -88,7 +82,7 @@ func (a *RedisScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Low",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armredis.ResourceInfo)
-				caf := strings.HasPrefix(*c.Name, "redis")
+				caf := scanners.HasCAFPrefix(*c.Name, "redis", scanContext)
 				return !caf, ""
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
@@ -129,5 +123,51 @@ func (a *RedisScanner) GetRules() map[string]scanners.AzureRule {
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/azure-cache-for-redis/cache-remove-tls-10-11",
 		},
+		"redis-010": {
+			Id:          "redis-010",
+			Category:    "High Availability and Resiliency",
+			Subcategory: "Persistence",
+			Description: "Redis Premium should have data persistence (RDB or AOF) enabled",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armredis.ResourceInfo)
+				if c.Properties.SKU == nil || c.Properties.SKU.Name == nil || *c.Properties.SKU.Name != armredis.SKUNamePremium {
+					return false, ""
+				}
+
+				persistence := []string{}
+				if config := c.Properties.RedisConfiguration; config != nil {
+					if config.RdbBackupEnabled != nil && strings.EqualFold(*config.RdbBackupEnabled, "true") {
+						persistence = append(persistence, "RDB")
+					}
+					if aof, ok := config.AdditionalProperties["aof-backup-enabled"].(string); ok && strings.EqualFold(aof, "true") {
+						persistence = append(persistence, "AOF")
+					}
+				}
+
+				if len(persistence) == 0 {
+					return true, "None"
+				}
+				return false, strings.Join(persistence, ", ")
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/azure-cache-for-redis/cache-how-to-premium-persistence",
+		},
+		"redis-011": {
+			Id:          "redis-011",
+			Category:    "High Availability and Resiliency",
+			Subcategory: "Memory Management",
+			Description: "Redis should use an eviction policy other than noeviction",
+			Severity:    "Low",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armredis.ResourceInfo)
+				// Azure Cache for Redis uses volatile-lru when no policy is configured
+				policy := "volatile-lru"
+				if c.Properties.RedisConfiguration != nil && c.Properties.RedisConfiguration.MaxmemoryPolicy != nil && *c.Properties.RedisConfiguration.MaxmemoryPolicy != "" {
+					policy = *c.Properties.RedisConfiguration.MaxmemoryPolicy
+				}
+				return strings.EqualFold(policy, "noeviction"), policy
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/azure-cache-for-redis/cache-best-practices-memory-management#eviction-policy",
+		},
 	}
 }
@@ -164,6 +164,129 @@ func TestRedisScanner_Rules(t *testing.T) {
 				result: "",
 			},
 		},
+		{
+			name: "RedisScanner Premium with RDB persistence",
+			fields: fields{
+				rule: "redis-010",
+				target: &armredis.ResourceInfo{
+					Properties: &armredis.Properties{
+						SKU: &armredis.SKU{
+							Name: getSKUNamePremium(),
+						},
+						RedisConfiguration: &armredis.CommonPropertiesRedisConfiguration{
+							RdbBackupEnabled: to.StringPtr("true"),
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "RDB",
+			},
+		},
+		{
+			name: "RedisScanner Premium with AOF persistence",
+			fields: fields{
+				rule: "redis-010",
+				target: &armredis.ResourceInfo{
+					Properties: &armredis.Properties{
+						SKU: &armredis.SKU{
+							Name: getSKUNamePremium(),
+						},
+						RedisConfiguration: &armredis.CommonPropertiesRedisConfiguration{
+							AdditionalProperties: map[string]interface{}{
+								"aof-backup-enabled": "true",
+							},
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "AOF",
+			},
+		},
+		{
+			name: "RedisScanner Premium without persistence",
+			fields: fields{
+				rule: "redis-010",
+				target: &armredis.ResourceInfo{
+					Properties: &armredis.Properties{
+						SKU: &armredis.SKU{
+							Name: getSKUNamePremium(),
+						},
+						RedisConfiguration: &armredis.CommonPropertiesRedisConfiguration{
+							RdbBackupEnabled: to.StringPtr("false"),
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "None",
+			},
+		},
+		{
+			name: "RedisScanner maxmemory-policy allkeys-lru",
+			fields: fields{
+				rule: "redis-011",
+				target: &armredis.ResourceInfo{
+					Properties: &armredis.Properties{
+						RedisConfiguration: &armredis.CommonPropertiesRedisConfiguration{
+							MaxmemoryPolicy: to.StringPtr("allkeys-lru"),
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "allkeys-lru",
+			},
+		},
+		{
+			name: "RedisScanner maxmemory-policy noeviction",
+			fields: fields{
+				rule: "redis-011",
+				target: &armredis.ResourceInfo{
+					Properties: &armredis.Properties{
+						RedisConfiguration: &armredis.CommonPropertiesRedisConfiguration{
+							MaxmemoryPolicy: to.StringPtr("noeviction"),
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "noeviction",
+			},
+		},
+		{
+			name: "RedisScanner maxmemory-policy not configured",
+			fields: fields{
+				rule: "redis-011",
+				target: &armredis.ResourceInfo{
+					Properties: &armredis.Properties{
+						RedisConfiguration: &armredis.CommonPropertiesRedisConfiguration{},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "volatile-lru",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,52 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"strings"
+)
+
+// regionPairs - Azure paired regions. Some pairs are one-way (e.g. Brazil South is paired with South Central US,
+// which is paired with North Central US), so both directions are checked by IsPairedRegion.
+// https://learn.microsoft.com/en-us/azure/reliability/cross-region-replication-azure
+var regionPairs = map[string]string{
+	"australiacentral":   "australiacentral2",
+	"australiaeast":      "australiasoutheast",
+	"brazilsouth":        "southcentralus",
+	"canadacentral":      "canadaeast",
+	"centralindia":       "southindia",
+	"centralus":          "eastus2",
+	"eastasia":           "southeastasia",
+	"eastus":             "westus",
+	"francecentral":      "francesouth",
+	"germanywestcentral": "germanynorth",
+	"japaneast":          "japanwest",
+	"koreacentral":       "koreasouth",
+	"northcentralus":     "southcentralus",
+	"northeurope":        "westeurope",
+	"norwayeast":         "norwaywest",
+	"southafricanorth":   "southafricawest",
+	"swedencentral":      "swedensouth",
+	"switzerlandnorth":   "switzerlandwest",
+	"uaenorth":           "uaecentral",
+	"uksouth":            "ukwest",
+	"westcentralus":      "westus2",
+	"westindia":          "southindia",
+	"westus3":            "eastus",
+}
+
+// NormalizeLocation - Returns the location in lower case without spaces (e.g. West Europe as westeurope)
+func NormalizeLocation(location string) string {
+	return strings.ToLower(strings.ReplaceAll(location, " ", ""))
+}
+
+// IsPairedRegion - Returns true if the locations are an Azure region pair
+func IsPairedRegion(location, partner string) bool {
+	location = NormalizeLocation(location)
+	partner = NormalizeLocation(partner)
+	if location == "" || partner == "" {
+		return false
+	}
+	return regionPairs[location] == partner || regionPairs[partner] == location
+}
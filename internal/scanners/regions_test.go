@@ -0,0 +1,53 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import "testing"
+
+func TestIsPairedRegion(t *testing.T) {
+	tests := []struct {
+		name     string
+		location string
+		partner  string
+		want     bool
+	}{
+		{
+			name:     "region pair",
+			location: "westeurope",
+			partner:  "northeurope",
+			want:     true,
+		},
+		{
+			name:     "region pair with display names",
+			location: "North Europe",
+			partner:  "West Europe",
+			want:     true,
+		},
+		{
+			name:     "one-way region pair",
+			location: "southcentralus",
+			partner:  "brazilsouth",
+			want:     true,
+		},
+		{
+			name:     "not a region pair",
+			location: "westeurope",
+			partner:  "eastus",
+			want:     false,
+		},
+		{
+			name:     "unknown region",
+			location: "westeurope",
+			partner:  "",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPairedRegion(tt.location, tt.partner); got != tt.want {
+				t.Errorf("IsPairedRegion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
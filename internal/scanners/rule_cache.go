@@ -0,0 +1,77 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+)
+
+type (
+	// RuleCache - Memoizes the Eval results of the rules with a CacheKey, so a resource evaluated again, e.g. by a retried
+	// scanner or by another scan sharing the cache, doesn't repeat the Azure calls of its rules.
+	// It is safe for concurrent use by the scanners.
+	RuleCache struct {
+		mu      sync.Mutex
+		results map[string]cachedRuleResult
+		hits    int
+	}
+
+	cachedRuleResult struct {
+		broken bool
+		result string
+	}
+)
+
+// NewRuleCache - Creates an empty RuleCache
+func NewRuleCache() *RuleCache {
+	return &RuleCache{
+		results: map[string]cachedRuleResult{},
+	}
+}
+
+// Hits - Returns the number of evaluations served from the cache
+func (c *RuleCache) Hits() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits
+}
+
+// eval - Returns the cached result of the rule for its CacheKey, calling Eval on a miss.
+// Rules without a CacheKey, or whose key can't be hashed, are always evaluated. Results of evaluations recording
+// a RuleError are not cached, so the rule is evaluated, and its error recorded, again.
+// Concurrent misses for the same key may both call Eval, the results are the same.
+func (c *RuleCache) eval(rule AzureRule, target interface{}, scanContext *ScanContext) (bool, string) {
+	if rule.CacheKey == nil {
+		return rule.Eval(target, scanContext)
+	}
+
+	b, err := json.Marshal([]interface{}{rule.Id, rule.CacheKey(target, scanContext)})
+	if err != nil {
+		return rule.Eval(target, scanContext)
+	}
+	sum := sha256.Sum256(b)
+	key := hex.EncodeToString(sum[:])
+
+	c.mu.Lock()
+	if r, ok := c.results[key]; ok {
+		c.hits++
+		c.mu.Unlock()
+		return r.broken, r.result
+	}
+	c.mu.Unlock()
+
+	errs := scanContext.ruleErrorCount()
+	broken, result := rule.Eval(target, scanContext)
+	if scanContext.ruleErrorCount() != errs {
+		return broken, result
+	}
+
+	c.mu.Lock()
+	c.results[key] = cachedRuleResult{broken: broken, result: result}
+	c.mu.Unlock()
+	return broken, result
+}
@@ -0,0 +1,188 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+type cacheTarget struct {
+	Name *string
+	SKU  *string
+}
+
+func countingRule(calls *int32, cacheKey func(target interface{}, scanContext *ScanContext) interface{}) AzureRule {
+	return AzureRule{
+		Id: "test-001",
+		Eval: func(target interface{}, scanContext *ScanContext) (bool, string) {
+			atomic.AddInt32(calls, 1)
+			t := target.(*cacheTarget)
+			if scanContext != nil {
+				if _, ok := scanContext.CAFPrefixes[*t.SKU]; ok {
+					return false, *t.SKU
+				}
+			}
+			return *t.SKU == "Basic", *t.SKU
+		},
+		CacheKey: cacheKey,
+	}
+}
+
+func skuKey(target interface{}, scanContext *ScanContext) interface{} {
+	return target.(*cacheTarget).SKU
+}
+
+func skuAndPrefixesKey(target interface{}, scanContext *ScanContext) interface{} {
+	sku := target.(*cacheTarget).SKU
+	return []interface{}{sku, scanContext.CAFPrefixes[*sku]}
+}
+
+func TestRuleEngine_Cache(t *testing.T) {
+	tests := []struct {
+		name         string
+		cache        *RuleCache
+		cacheKey     func(target interface{}, scanContext *ScanContext) interface{}
+		targets      []*cacheTarget
+		scanContexts []*ScanContext
+		wantCalls    int32
+		wantHits     int
+	}{
+		{
+			name:     "identical inputs are evaluated once",
+			cache:    NewRuleCache(),
+			cacheKey: skuKey,
+			targets: []*cacheTarget{
+				{Name: to.StringPtr("a"), SKU: to.StringPtr("Basic")},
+				{Name: to.StringPtr("b"), SKU: to.StringPtr("Basic")},
+			},
+			wantCalls: 1,
+			wantHits:  1,
+		},
+		{
+			name:     "different inputs are evaluated",
+			cache:    NewRuleCache(),
+			cacheKey: skuKey,
+			targets: []*cacheTarget{
+				{Name: to.StringPtr("a"), SKU: to.StringPtr("Basic")},
+				{Name: to.StringPtr("b"), SKU: to.StringPtr("Premium")},
+			},
+			wantCalls: 2,
+		},
+		{
+			name:     "different scan context inputs are evaluated",
+			cache:    NewRuleCache(),
+			cacheKey: skuAndPrefixesKey,
+			targets: []*cacheTarget{
+				{Name: to.StringPtr("a"), SKU: to.StringPtr("Basic")},
+				{Name: to.StringPtr("b"), SKU: to.StringPtr("Basic")},
+			},
+			scanContexts: []*ScanContext{
+				{CAFPrefixes: map[string][]string{}},
+				{CAFPrefixes: map[string][]string{"Basic": {"basic"}}},
+			},
+			wantCalls: 2,
+		},
+		{
+			name:     "cache disabled",
+			cache:    nil,
+			cacheKey: skuKey,
+			targets: []*cacheTarget{
+				{Name: to.StringPtr("a"), SKU: to.StringPtr("Basic")},
+				{Name: to.StringPtr("b"), SKU: to.StringPtr("Basic")},
+			},
+			wantCalls: 2,
+		},
+		{
+			name:     "rule without cache key",
+			cache:    NewRuleCache(),
+			cacheKey: nil,
+			targets: []*cacheTarget{
+				{Name: to.StringPtr("a"), SKU: to.StringPtr("Basic")},
+				{Name: to.StringPtr("b"), SKU: to.StringPtr("Basic")},
+			},
+			wantCalls: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			rule := countingRule(&calls, tt.cacheKey)
+			engine := RuleEngine{Cache: tt.cache}
+			for i, target := range tt.targets {
+				var scanContext *ScanContext
+				if tt.scanContexts != nil {
+					scanContext = tt.scanContexts[i]
+				}
+				wantBroken, wantResult := rule.Eval(target, scanContext)
+				atomic.AddInt32(&calls, -1)
+
+				got := engine.EvaluateRule(rule, target, scanContext)
+				if got.IsBroken != wantBroken || got.Result != wantResult {
+					t.Errorf("EvaluateRule() = %v, %s, want %v, %s", got.IsBroken, got.Result, wantBroken, wantResult)
+				}
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("Eval called %d times, want %d", calls, tt.wantCalls)
+			}
+			if tt.cache != nil && tt.cache.Hits() != tt.wantHits {
+				t.Errorf("Hits() = %d, want %d", tt.cache.Hits(), tt.wantHits)
+			}
+		})
+	}
+}
+
+func TestRuleEngine_CacheConcurrently(t *testing.T) {
+	var calls int32
+	rule := countingRule(&calls, skuKey)
+	engine := RuleEngine{Cache: NewRuleCache()}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got := engine.EvaluateRule(rule, &cacheTarget{Name: to.StringPtr("a"), SKU: to.StringPtr("Basic")}, nil)
+			if !got.IsBroken || got.Result != "Basic" {
+				t.Errorf("EvaluateRule() = %v, %s, want true, Basic", got.IsBroken, got.Result)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls < 1 || int(calls)+engine.Cache.Hits() != 8 {
+		t.Errorf("Eval called %d times with %d hits, want 8 evaluations in total", calls, engine.Cache.Hits())
+	}
+}
+
+func TestRuleEngine_CacheRuleErrors(t *testing.T) {
+	var calls int32
+	rule := AzureRule{
+		Id: "test-001",
+		Eval: func(target interface{}, scanContext *ScanContext) (bool, string) {
+			atomic.AddInt32(&calls, 1)
+			return scanContext.RuleError("test-001", *target.(*cacheTarget).Name, errors.New("throttled"))
+		},
+		CacheKey: skuKey,
+	}
+	engine := RuleEngine{Cache: NewRuleCache()}
+
+	for i := 0; i < 2; i++ {
+		scanContext := &ScanContext{}
+		got := engine.EvaluateRule(rule, &cacheTarget{Name: to.StringPtr("a"), SKU: to.StringPtr("Basic")}, scanContext)
+		if got.IsBroken || got.Result != "Unknown" {
+			t.Errorf("EvaluateRule() = %v, %s, want false, Unknown", got.IsBroken, got.Result)
+		}
+		if len(scanContext.RuleErrors()) != 1 {
+			t.Errorf("RuleErrors() = %v, want the error recorded by every evaluation", scanContext.RuleErrors())
+		}
+	}
+	if calls != 2 {
+		t.Errorf("Eval called %d times, want 2: results with rule errors must not be cached", calls)
+	}
+}
@@ -42,7 +42,7 @@ func (c *ServiceBusScanner) Scan(resourceGroupName string, scanContext *scanners
 	if err != nil {
 		return nil, err
 	}
-	engine := scanners.RuleEngine{}
+	engine := scanners.RuleEngine{Cache: c.config.RuleCache}
 	rules := c.GetRules()
 	results := []scanners.AzureServiceResult{}
 
@@ -52,9 +52,12 @@ func (c *ServiceBusScanner) Scan(resourceGroupName string, scanContext *scanners
 		results = append(results, scanners.AzureServiceResult{
 			SubscriptionID: c.config.SubscriptionID,
 			ResourceGroup:  resourceGroupName,
+			ResourceID:     *servicebus.ID,
 			ServiceName:    *servicebus.Name,
 			Type:           *servicebus.Type,
 			Location:       *servicebus.Location,
+			Tags:           servicebus.Tags,
+			CreatedAt:      scanners.GetCreatedAt(servicebus),
 			Rules:          rr,
 		})
 	}
@@ -72,9 +75,17 @@ func (c *ServiceBusScanner) listServiceBus(resourceGroupName string) ([]*armserv
 				return nil, err
 			}
 			namespaces = append(namespaces, resp.Value...)
+			if limited, ok := scanners.LimitResources(c.config, namespaces); ok {
+				return limited, nil
+			}
 		}
 		return namespaces, nil
 	}
 
 	return c.listServiceBusFunc(resourceGroupName)
 }
+
+// GetResourceTypes - Returns the resource types scanned by the ServiceBusScanner
+func (c *ServiceBusScanner) GetResourceTypes() []string {
+	return []string{"Microsoft.ServiceBus/namespaces"}
+}
@@ -6,8 +6,11 @@ package scanners
 import (
 	"context"
 	"fmt"
+	"log"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
@@ -16,32 +19,70 @@ import (
 type (
 	// ScannerConfig - Struct for Scanner Config
 	ScannerConfig struct {
-		Ctx                context.Context
-		Cred               azcore.TokenCredential
-		SubscriptionID     string
-		ClientOptions      *arm.ClientOptions
+		Ctx                     context.Context
+		Cred                    azcore.TokenCredential
+		SubscriptionID          string
+		ClientOptions           *arm.ClientOptions
+		MinRetentionDays        int
+		ConsumerGroupsThreshold int
+		Limit                   int
+		WithMetrics             bool
+		// ProductionSeverity - Lowers the severity of rules meant for production resources when the resource isn't tagged as production
+		ProductionSeverity bool
+		// RuleCache - Optional. Reuses the results of the rules with a CacheKey. When nil rules are always evaluated
+		RuleCache *RuleCache
 	}
 
-	// ScanContext - Struct for Scanner Context
+	// ScanContext - Struct for Scanner Context. It's shared by the scanners of a subscription, which may scan
+	// Resource Groups concurrently, so the maps are read only once the scan starts.
 	ScanContext struct {
 		PrivateEndpoints map[string]bool
+		CAFPrefixes      map[string][]string
+		// ManagementPolicies - Whether storage accounts have a lifecycle management policy, keyed by lower case id. Loaded by the StorageScanner
+		ManagementPolicies map[string]bool
+		// BlobSoftDelete - Soft delete state of the blobs of storage accounts, keyed by lower case id. Loaded by the StorageScanner
+		BlobSoftDelete map[string]SoftDeleteStatus
+		// AutoscaleTargets - Resources targeted by an enabled autoscale setting, keyed by lower case id. Loaded by the AppServiceScanner
+		AutoscaleTargets map[string]bool
+		// VnetIntegratedClusters - AKS clusters with API Server VNet Integration enabled, keyed by lower case id. Loaded by the AKSScanner
+		VnetIntegratedClusters map[string]bool
+		// ContainerInsightsClusters - AKS clusters with Container Insights enabled in their Azure Monitor profile, keyed by lower case id. Loaded by the AKSScanner
+		ContainerInsightsClusters map[string]bool
+		// PolicyConstraints - Number of Gatekeeper constraints assigned to AKS clusters, keyed by lower case id. Loaded by the AKSScanner
+		PolicyConstraints map[string]int
+		// LockedResourceGroups - Resource groups with a CanNotDelete or ReadOnly lock, keyed by lower case name. Loaded by the AKSScanner
+		LockedResourceGroups map[string]bool
+		// ruleErrors - Errors of rules that couldn't be evaluated, see RuleError
+		ruleErrors   []ScanError
+		ruleErrorsMu sync.Mutex
 	}
 
 	// IAzureScanner - Interface for all Azure Scanners
 	IAzureScanner interface {
 		Init(config *ScannerConfig) error
 		GetRules() map[string]AzureRule
+		GetResourceTypes() []string
 		Scan(resourceGroupName string, scanContext *ScanContext) ([]AzureServiceResult, error)
 	}
 
+	// IScanContextLoader - Implemented by scanners whose rules read data of many resources, listed up front, from the ScanContext.
+	// LoadScanContext is called once per subscription, after Init and before the Resource Groups are scanned.
+	IScanContextLoader interface {
+		LoadScanContext(resourceGroups []string, scanContext *ScanContext) error
+	}
+
 	// AzureServiceResult - Struct for all Azure Service Results
 	AzureServiceResult struct {
-		SubscriptionID string
-		ResourceGroup  string
-		Location       string
-		Type           string
-		ServiceName    string
-		Rules          map[string]AzureRuleResult
+		SubscriptionID    string
+		ResourceGroup     string
+		Location          string
+		Type              string
+		ResourceID        string
+		ServiceName       string
+		Tags              map[string]*string
+		CreatedAt         *time.Time
+		Rules             map[string]AzureRuleResult
+		AdvisorCategories []string
 	}
 
 	AzureRule struct {
@@ -53,6 +94,12 @@ type (
 		Url         string
 		IsSpecific  bool
 		Eval        func(target interface{}, scanContext *ScanContext) (bool, string)
+		// AdjustSeverity - Optional. Returns the severity to report, instead of Severity, when the rule is broken. An empty string keeps Severity.
+		AdjustSeverity func(target interface{}, scanContext *ScanContext) string
+		// CacheKey - Optional. Returns every input Eval reads: the fields of the target, the ScanContext entries
+		// and the scanner settings, so a RuleCache can reuse the result when the same inputs are evaluated again.
+		// Set it on rules whose Eval calls Azure, keyed on the resource id, where a cached result saves the calls.
+		CacheKey func(target interface{}, scanContext *ScanContext) interface{}
 	}
 
 	AzureRuleResult struct {
@@ -67,18 +114,91 @@ type (
 		IsBroken    bool
 	}
 
-	RuleEngine struct{}
+	// RuleEngine - Evaluates rules. Results of rules with a CacheKey are reused when Cache is set.
+	RuleEngine struct {
+		Cache *RuleCache
+	}
+
+	// ScanError - Error of a scanner that failed in a Resource Group, so its results are missing from the report,
+	// or of a rule that couldn't be evaluated for a resource
+	ScanError struct {
+		Scanner        string `json:"scanner,omitempty"`
+		Rule           string `json:"rule,omitempty"`
+		SubscriptionID string `json:"subscriptionId"`
+		ResourceGroup  string `json:"resourceGroup"`
+		ResourceID     string `json:"resourceId,omitempty"`
+		Error          string `json:"error"`
+	}
 )
 
+// RuleError - Records the error of a rule that couldn't be evaluated for a resource, so the scan continues
+// and the error is reported. Returns the result of the rule: not broken and Unknown.
+func (c *ScanContext) RuleError(ruleID, resourceID string, err error) (bool, string) {
+	log.Printf("WARNING: Unable to evaluate rule %s for %s: %s", ruleID, resourceID, err)
+	if c == nil {
+		return false, "Unknown"
+	}
+
+	e := ScanError{Rule: ruleID, ResourceID: resourceID, Error: err.Error()}
+	if resource, err := arm.ParseResourceID(resourceID); err == nil {
+		e.SubscriptionID = resource.SubscriptionID
+		e.ResourceGroup = resource.ResourceGroupName
+	}
+
+	c.ruleErrorsMu.Lock()
+	defer c.ruleErrorsMu.Unlock()
+	c.ruleErrors = append(c.ruleErrors, e)
+	return false, "Unknown"
+}
+
+// RuleErrors - Returns a copy of the errors recorded by RuleError
+func (c *ScanContext) RuleErrors() []ScanError {
+	c.ruleErrorsMu.Lock()
+	defer c.ruleErrorsMu.Unlock()
+	return append([]ScanError(nil), c.ruleErrors...)
+}
+
+// ruleErrorCount - Returns the number of errors recorded by RuleError
+func (c *ScanContext) ruleErrorCount() int {
+	if c == nil {
+		return 0
+	}
+	c.ruleErrorsMu.Lock()
+	defer c.ruleErrorsMu.Unlock()
+	return len(c.ruleErrors)
+}
+
+// LimitResources - Truncates the resources to config.Limit. Returns true when the limit is reached so listing can stop paging.
+// A limit of 0 means no limit.
+func LimitResources[T any](config *ScannerConfig, resources []T) ([]T, bool) {
+	if config.Limit <= 0 || len(resources) < config.Limit {
+		return resources, false
+	}
+	return resources[:config.Limit], true
+}
+
 func (e *RuleEngine) EvaluateRule(rule AzureRule, target interface{}, scanContext *ScanContext) AzureRuleResult {
-	broken, result := rule.Eval(target, scanContext)
+	var broken bool
+	var result string
+	if e.Cache != nil {
+		broken, result = e.Cache.eval(rule, target, scanContext)
+	} else {
+		broken, result = rule.Eval(target, scanContext)
+	}
+
+	severity := rule.Severity
+	if broken && rule.AdjustSeverity != nil {
+		if s := rule.AdjustSeverity(target, scanContext); s != "" {
+			severity = s
+		}
+	}
 
 	return AzureRuleResult{
 		Id:          rule.Id,
 		Category:    rule.Category,
 		Subcategory: rule.Subcategory,
 		Description: rule.Description,
-		Severity:    rule.Severity,
+		Severity:    severity,
 		Learn:       rule.Url,
 		IsSpecific:  rule.IsSpecific,
 		Result:      result,
@@ -134,6 +254,7 @@ func (r AzureServiceResult) ToMap(mask bool) map[string]string {
 		"PVT":            pvt,
 		"DS":             ds,
 		"CAF":            caf,
+		"Advisor":        strings.Join(r.AdvisorCategories, ", "),
 	}
 }
 
@@ -156,6 +277,7 @@ func (r AzureServiceResult) GetHeathers() []string {
 		"PVT",
 		"DS",
 		"CAF",
+		"Advisor",
 	}
 }
 
@@ -164,10 +286,30 @@ func parseLocation(location string) string {
 }
 
 func MaskSubscriptionID(subscriptionID string, mask bool) string {
-	if !mask {
+	// Errors of rules or scanners may have no subscription id, there is nothing to mask
+	if !mask || len(subscriptionID) < 36 {
 		return subscriptionID
 	}
 
 	// Show only last 7 chars of the subscription ID
 	return fmt.Sprintf("xxxxxxxx-xxxx-xxxx-xxxx-xxxxx%s", subscriptionID[29:])
 }
+
+// MaskResourceID - Masks the subscription id segment of a resource id with MaskSubscriptionID
+func MaskResourceID(resourceID string, mask bool) string {
+	const segment = "/subscriptions/"
+	if !mask {
+		return resourceID
+	}
+
+	start := strings.Index(strings.ToLower(resourceID), segment)
+	if start < 0 {
+		return resourceID
+	}
+	start += len(segment)
+	end := strings.Index(resourceID[start:], "/")
+	if end < 0 {
+		end = len(resourceID) - start
+	}
+	return resourceID[:start] + MaskSubscriptionID(resourceID[start:start+end], mask) + resourceID[start+end:]
+}
@@ -4,13 +4,20 @@
 package sigr
 
 import (
-	"log"
+	"fmt"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/signalr/armsignalr"
 	"github.com/cmendible/azqr/internal/scanners"
 )
 
+const (
+	// connectionsPerUnit - Concurrent connections per unit of the Standard and Premium tiers
+	connectionsPerUnit = 1000
+	// freeConnections - Concurrent connections of the Free tier
+	freeConnections = 20
+)
+
 // GetRules - Returns the rules for the SignalRScanner
 func (a *SignalRScanner) GetRules() map[string]scanners.AzureRule {
 	return map[string]scanners.AzureRule{
@@ -22,12 +29,7 @@ func (a *SignalRScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Medium",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				service := target.(*armsignalr.ResourceInfo)
-				hasDiagnostics, err := a.diagnosticsSettings.HasDiagnostics(*service.ID)
-				if err != nil {
-					log.Fatalf("Error checking diagnostic settings for service %s: %s", *service.Name, err)
-				}
-
-				return !hasDiagnostics, ""
+				return a.diagnosticsSettings.EvalDiagnostics("sigr-001", *service.ID, scanContext)
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/azure-signalr/signalr-howto-diagnostic-logs",
 		},
@@ -92,7 +94,7 @@ func (a *SignalRScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Low",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armsignalr.ResourceInfo)
-				caf := strings.HasPrefix(*c.Name, "sigr")
+				caf := scanners.HasCAFPrefix(*c.Name, "sigr", scanContext)
 				return !caf, ""
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
@@ -109,5 +111,98 @@ func (a *SignalRScanner) GetRules() map[string]scanners.AzureRule {
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
+		"sigr-008": scanners.NewPublicNetworkAccessRule("sigr-008", "SignalR", "https://learn.microsoft.com/en-us/azure/azure-signalr/howto-network-access-control"),
+		"sigr-013": {
+			Id:          "sigr-013",
+			Category:    "Security",
+			Subcategory: "Identity and Access Control",
+			Description: "SignalR in Serverless mode should use a Managed Identity for upstream calls",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armsignalr.ResourceInfo)
+				serverless := false
+				if c.Properties != nil {
+					for _, f := range c.Properties.Features {
+						if f.Flag != nil && *f.Flag == armsignalr.FeatureFlagsServiceMode && f.Value != nil && strings.EqualFold(*f.Value, "Serverless") {
+							serverless = true
+						}
+					}
+				}
+				if !serverless {
+					return false, ""
+				}
+
+				identity := c.Identity != nil && c.Identity.Type != nil && *c.Identity.Type != armsignalr.ManagedIdentityTypeNone
+				return !identity, ""
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/azure-signalr/howto-use-managed-identity",
+		},
+		"sigr-014": {
+			Id:          "sigr-014",
+			Category:    "High Availability and Resiliency",
+			Subcategory: "Reliability",
+			Description: "SignalR Premium should have a replica for regional failover",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armsignalr.ResourceInfo)
+				if c.SKU == nil || c.SKU.Tier == nil || *c.SKU.Tier != armsignalr.SignalRSKUTierPremium {
+					return false, ""
+				}
+
+				replicas := a.getReplicas(*c.ID)
+				return replicas == 0, fmt.Sprintf("%d replicas", replicas)
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/azure-signalr/howto-enable-geo-replication",
+		},
+		"sigr-015": {
+			Id:          "sigr-015",
+			Category:    "Security",
+			Subcategory: "Networking",
+			Description: "SignalR with public network access should deny requests by default in its network ACLs",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armsignalr.ResourceInfo)
+				if c.Properties == nil {
+					return false, ""
+				}
+				// Public network access defaults to Enabled
+				if c.Properties.PublicNetworkAccess != nil && !strings.EqualFold(*c.Properties.PublicNetworkAccess, "Enabled") {
+					return false, ""
+				}
+
+				action := armsignalr.ACLActionAllow
+				if c.Properties.NetworkACLs != nil && c.Properties.NetworkACLs.DefaultAction != nil {
+					action = *c.Properties.NetworkACLs.DefaultAction
+				}
+				return action != armsignalr.ACLActionDeny, string(action)
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/azure-signalr/howto-network-access-control",
+		},
+		"sigr-016": {
+			Id:          "sigr-016",
+			Category:    "High Availability and Resiliency",
+			Subcategory: "SKU",
+			Description: "SignalR units and concurrent connection limit (Free tier should not be used in production)",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armsignalr.ResourceInfo)
+				if c.SKU == nil {
+					return false, ""
+				}
+
+				free := (c.SKU.Tier != nil && *c.SKU.Tier == armsignalr.SignalRSKUTierFree) ||
+					(c.SKU.Name != nil && strings.HasPrefix(strings.ToLower(*c.SKU.Name), "free"))
+				units := int32(1)
+				if c.SKU.Capacity != nil && *c.SKU.Capacity > 0 {
+					units = *c.SKU.Capacity
+				}
+				limit := units * connectionsPerUnit
+				if free {
+					limit = freeConnections
+				}
+				return free && scanners.IsProduction(c.Tags), fmt.Sprintf("%d units, %d connections", units, limit)
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/azure-signalr/signalr-howto-scale-signalr",
+		},
 	}
 }
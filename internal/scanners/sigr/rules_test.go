@@ -18,6 +18,7 @@ func TestSignalRScanner_Rules(t *testing.T) {
 		target              interface{}
 		scanContext         *scanners.ScanContext
 		diagnosticsSettings scanners.DiagnosticsSettings
+		replicas            map[string]int
 	}
 	type want struct {
 		broken bool
@@ -130,11 +131,238 @@ func TestSignalRScanner_Rules(t *testing.T) {
 				result: "",
 			},
 		},
+		{
+			name: "SignalRScanner public network access enabled",
+			fields: fields{
+				rule: "sigr-008",
+				target: &armsignalr.ResourceInfo{
+					Properties: &armsignalr.Properties{
+						PublicNetworkAccess: to.StringPtr("Enabled"),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "Enabled",
+			},
+		},
+		{
+			name: "SignalRScanner Serverless with SystemAssigned Managed Identity",
+			fields: fields{
+				rule: "sigr-013",
+				target: &armsignalr.ResourceInfo{
+					Identity: &armsignalr.ManagedIdentity{
+						Type: getManagedIdentityType(armsignalr.ManagedIdentityTypeSystemAssigned),
+					},
+					Properties: &armsignalr.Properties{
+						Features: getServerlessFeatures(),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "SignalRScanner Serverless without Managed Identity",
+			fields: fields{
+				rule: "sigr-013",
+				target: &armsignalr.ResourceInfo{
+					Identity: &armsignalr.ManagedIdentity{
+						Type: getManagedIdentityType(armsignalr.ManagedIdentityTypeNone),
+					},
+					Properties: &armsignalr.Properties{
+						Features: getServerlessFeatures(),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "",
+			},
+		},
+		{
+			name: "SignalRScanner Premium with replica",
+			fields: fields{
+				rule: "sigr-014",
+				target: &armsignalr.ResourceInfo{
+					ID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.SignalRService/SignalR/sigr"),
+					SKU: &armsignalr.ResourceSKU{
+						Tier: getSKUTier(armsignalr.SignalRSKUTierPremium),
+					},
+				},
+				scanContext: &scanners.ScanContext{},
+				replicas: map[string]int{
+					"/subscriptions/sub/resourcegroups/rg/providers/microsoft.signalrservice/signalr/sigr": 1,
+				},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "1 replicas",
+			},
+		},
+		{
+			name: "SignalRScanner Premium without replica",
+			fields: fields{
+				rule: "sigr-014",
+				target: &armsignalr.ResourceInfo{
+					ID: to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.SignalRService/SignalR/sigr"),
+					SKU: &armsignalr.ResourceSKU{
+						Tier: getSKUTier(armsignalr.SignalRSKUTierPremium),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "0 replicas",
+			},
+		},
+		{
+			name: "SignalRScanner network ACLs default deny",
+			fields: fields{
+				rule: "sigr-015",
+				target: &armsignalr.ResourceInfo{
+					Properties: &armsignalr.Properties{
+						PublicNetworkAccess: to.StringPtr("Enabled"),
+						NetworkACLs: &armsignalr.NetworkACLs{
+							DefaultAction: getACLAction(armsignalr.ACLActionDeny),
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "Deny",
+			},
+		},
+		{
+			name: "SignalRScanner network ACLs default allow",
+			fields: fields{
+				rule: "sigr-015",
+				target: &armsignalr.ResourceInfo{
+					Properties: &armsignalr.Properties{
+						PublicNetworkAccess: to.StringPtr("Enabled"),
+						NetworkACLs: &armsignalr.NetworkACLs{
+							DefaultAction: getACLAction(armsignalr.ACLActionAllow),
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "Allow",
+			},
+		},
+		{
+			name: "SignalRScanner without network ACLs",
+			fields: fields{
+				rule: "sigr-015",
+				target: &armsignalr.ResourceInfo{
+					Properties: &armsignalr.Properties{},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "Allow",
+			},
+		},
+		{
+			name: "SignalRScanner network ACLs with public network access disabled",
+			fields: fields{
+				rule: "sigr-015",
+				target: &armsignalr.ResourceInfo{
+					Properties: &armsignalr.Properties{
+						PublicNetworkAccess: to.StringPtr("Disabled"),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "SignalRScanner Free tier in production",
+			fields: fields{
+				rule: "sigr-016",
+				target: &armsignalr.ResourceInfo{
+					SKU: &armsignalr.ResourceSKU{
+						Name:     to.StringPtr("Free_F1"),
+						Tier:     getSKUTier(armsignalr.SignalRSKUTierFree),
+						Capacity: to.Int32Ptr(1),
+					},
+					Tags: map[string]*string{"env": to.StringPtr("prod")},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "1 units, 20 connections",
+			},
+		},
+		{
+			name: "SignalRScanner Premium tier capacity",
+			fields: fields{
+				rule: "sigr-016",
+				target: &armsignalr.ResourceInfo{
+					SKU: &armsignalr.ResourceSKU{
+						Name:     to.StringPtr("Premium_P1"),
+						Tier:     getSKUTier(armsignalr.SignalRSKUTierPremium),
+						Capacity: to.Int32Ptr(10),
+					},
+					Tags: map[string]*string{"env": to.StringPtr("prod")},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "10 units, 10000 connections",
+			},
+		},
+		{
+			name: "SignalRScanner Premium tier without capacity",
+			fields: fields{
+				rule: "sigr-016",
+				target: &armsignalr.ResourceInfo{
+					SKU: &armsignalr.ResourceSKU{
+						Name: to.StringPtr("Premium_P1"),
+						Tier: getSKUTier(armsignalr.SignalRSKUTierPremium),
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "1 units, 1000 connections",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			s := &SignalRScanner{
 				diagnosticsSettings: tt.fields.diagnosticsSettings,
+				replicas:            tt.fields.replicas,
 			}
 			rules := s.GetRules()
 			b, w := rules[tt.fields.rule].Eval(tt.fields.target, tt.fields.scanContext)
@@ -148,3 +376,25 @@ func TestSignalRScanner_Rules(t *testing.T) {
 		})
 	}
 }
+
+func getManagedIdentityType(t armsignalr.ManagedIdentityType) *armsignalr.ManagedIdentityType {
+	return &t
+}
+
+func getServerlessFeatures() []*armsignalr.Feature {
+	flag := armsignalr.FeatureFlagsServiceMode
+	return []*armsignalr.Feature{
+		{
+			Flag:  &flag,
+			Value: to.StringPtr("Serverless"),
+		},
+	}
+}
+
+func getSKUTier(t armsignalr.SignalRSKUTier) *armsignalr.SignalRSKUTier {
+	return &t
+}
+
+func getACLAction(a armsignalr.ACLAction) *armsignalr.ACLAction {
+	return &a
+}
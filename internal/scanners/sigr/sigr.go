@@ -4,7 +4,10 @@
 package sigr
 
 import (
+	"fmt"
 	"log"
+	"strings"
+	"sync"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/signalr/armsignalr"
 	"github.com/cmendible/azqr/internal/scanners"
@@ -15,17 +18,29 @@ type SignalRScanner struct {
 	config              *scanners.ScannerConfig
 	diagnosticsSettings scanners.DiagnosticsSettings
 	signalrClient       *armsignalr.Client
-	listSignalRFunc     func(resourceGroupName string) ([]*armsignalr.ResourceInfo, error)
+	graph               scanners.ResourceGraph
+	// mu - Guards the replicas counted by Scan, read by the rules while other Resource Groups are scanned
+	mu                sync.RWMutex
+	replicas          map[string]int
+	listSignalRFunc   func(resourceGroupName string) ([]*armsignalr.ResourceInfo, error)
+	countReplicasFunc func(resourceGroupName string) (map[string]int, error)
 }
 
 // Init - Initializes the SignalRScanner
 func (c *SignalRScanner) Init(config *scanners.ScannerConfig) error {
 	c.config = config
+	// The replicas counted by Scan belong to the subscription of the previous config
+	c.replicas = nil
 	var err error
 	c.signalrClient, err = armsignalr.NewClient(config.SubscriptionID, config.Cred, config.ClientOptions)
 	if err != nil {
 		return err
 	}
+	c.graph = scanners.ResourceGraph{}
+	err = c.graph.Init(config)
+	if err != nil {
+		return err
+	}
 	c.diagnosticsSettings = scanners.DiagnosticsSettings{}
 	err = c.diagnosticsSettings.Init(config)
 	if err != nil {
@@ -42,19 +57,37 @@ func (c *SignalRScanner) Scan(resourceGroupName string, scanContext *scanners.Sc
 	if err != nil {
 		return nil, err
 	}
-	engine := scanners.RuleEngine{}
+	engine := scanners.RuleEngine{Cache: c.config.RuleCache}
 	rules := c.GetRules()
 	results := []scanners.AzureServiceResult{}
 
+	if len(signalr) > 0 {
+		replicas, err := c.countReplicas(resourceGroupName)
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		if c.replicas == nil {
+			c.replicas = map[string]int{}
+		}
+		for id, count := range replicas {
+			c.replicas[strings.ToLower(id)] = count
+		}
+		c.mu.Unlock()
+	}
+
 	for _, signalr := range signalr {
 		rr := engine.EvaluateRules(rules, signalr, scanContext)
 
 		results = append(results, scanners.AzureServiceResult{
 			SubscriptionID: c.config.SubscriptionID,
 			ResourceGroup:  resourceGroupName,
+			ResourceID:     *signalr.ID,
 			ServiceName:    *signalr.Name,
 			Type:           *signalr.Type,
 			Location:       *signalr.Location,
+			Tags:           signalr.Tags,
+			CreatedAt:      scanners.GetCreatedAt(signalr),
 			Rules:          rr,
 		})
 	}
@@ -72,9 +105,51 @@ func (c *SignalRScanner) listSignalR(resourceGroupName string) ([]*armsignalr.Re
 				return nil, err
 			}
 			signalrs = append(signalrs, resp.Value...)
+			if limited, ok := scanners.LimitResources(c.config, signalrs); ok {
+				return limited, nil
+			}
 		}
 		return signalrs, nil
 	}
 
 	return c.listSignalRFunc(resourceGroupName)
 }
+
+// getReplicas - Returns the number of replicas of the SignalR counted by Scan
+func (c *SignalRScanner) getReplicas(signalrID string) int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.replicas[strings.ToLower(signalrID)]
+}
+
+// countReplicas - Returns the number of replicas of each SignalR in the Resource Group, keyed by SignalR id.
+// The Replicas API is not exposed by the stable SDK, so replicas are counted with Resource Graph.
+func (c *SignalRScanner) countReplicas(resourceGroupName string) (map[string]int, error) {
+	if c.countReplicasFunc == nil {
+		query := fmt.Sprintf("resources | where type =~ 'microsoft.signalrservice/signalr/replicas' and resourceGroup =~ '%s' | extend signalr = substring(id, 0, indexof(id, '/replicas/')) | summarize count=count() by signalr", resourceGroupName)
+		rows, err := c.graph.Query(query, []string{c.config.SubscriptionID})
+		if err != nil {
+			return nil, err
+		}
+		counts := map[string]int{}
+		for _, row := range rows {
+			id, ok := row["signalr"].(string)
+			if !ok {
+				continue
+			}
+			count, ok := row["count"].(float64)
+			if !ok {
+				continue
+			}
+			counts[id] = int(count)
+		}
+		return counts, nil
+	}
+
+	return c.countReplicasFunc(resourceGroupName)
+}
+
+// GetResourceTypes - Returns the resource types scanned by the SignalRScanner
+func (c *SignalRScanner) GetResourceTypes() []string {
+	return []string{"Microsoft.SignalRService/SignalR"}
+}
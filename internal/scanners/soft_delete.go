@@ -0,0 +1,35 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import "fmt"
+
+// SoftDeleteStatus - Soft delete state of a resource
+type SoftDeleteStatus struct {
+	Enabled       bool
+	RetentionDays int
+}
+
+// NewSoftDeleteRule - Returns a rule flagging resources without soft delete.
+// status returns the soft delete state of the target, and false when it's unknown so the rule is not broken.
+func NewSoftDeleteRule(id, service, url string, status func(target interface{}, scanContext *ScanContext) (SoftDeleteStatus, bool)) AzureRule {
+	return AzureRule{
+		Id:          id,
+		Category:    "High Availability and Resiliency",
+		Subcategory: "Reliability",
+		Description: service + " should have soft delete enabled",
+		Severity:    "Medium",
+		Eval: func(target interface{}, scanContext *ScanContext) (bool, string) {
+			s, ok := status(target, scanContext)
+			if !ok {
+				return false, ""
+			}
+			if !s.Enabled {
+				return true, "Disabled"
+			}
+			return false, fmt.Sprintf("Enabled, %d days", s.RetentionDays)
+		},
+		Url: url,
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewSoftDeleteRule(t *testing.T) {
+	type want struct {
+		broken bool
+		result string
+	}
+	tests := []struct {
+		name   string
+		status SoftDeleteStatus
+		known  bool
+		want   want
+	}{
+		{
+			name:   "soft delete enabled",
+			status: SoftDeleteStatus{Enabled: true, RetentionDays: 90},
+			known:  true,
+			want: want{
+				broken: false,
+				result: "Enabled, 90 days",
+			},
+		},
+		{
+			name:  "soft delete disabled",
+			known: true,
+			want: want{
+				broken: true,
+				result: "Disabled",
+			},
+		},
+		{
+			name: "soft delete unknown",
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := NewSoftDeleteRule("test-001", "Test", "", func(target interface{}, scanContext *ScanContext) (SoftDeleteStatus, bool) {
+				return tt.status, tt.known
+			})
+			b, w := rule.Eval(nil, &ScanContext{})
+			got := want{
+				broken: b,
+				result: w,
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewSoftDeleteRule().Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,9 +4,10 @@
 package sql
 
 import (
-	"log"
+	"fmt"
 	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/sql/armsql"
 	"github.com/cmendible/azqr/internal/scanners"
 )
@@ -22,12 +23,7 @@ func (a *SQLScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Medium",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				service := target.(*armsql.Server)
-				hasDiagnostics, err := a.diagnosticsSettings.HasDiagnostics(*service.ID)
-				if err != nil {
-					log.Fatalf("Error checking diagnostic settings for service %s: %s", *service.Name, err)
-				}
-
-				return !hasDiagnostics, ""
+				return a.diagnosticsSettings.EvalDiagnostics("sql-001", *service.ID, scanContext)
 			},
 		},
 		"Private": {
@@ -50,7 +46,7 @@ func (a *SQLScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Low",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armsql.Server)
-				caf := strings.HasPrefix(*c.Name, "sql")
+				caf := scanners.HasCAFPrefix(*c.Name, "sql", scanContext)
 				return !caf, ""
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
@@ -79,6 +75,20 @@ func (a *SQLScanner) GetRules() map[string]scanners.AzureRule {
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/azure-sql/database/connectivity-settings?view=azuresql&tabs=azure-portal#minimal-tls-version",
 		},
+		"sql-009": {
+			Id:          "sql-009",
+			Category:    "Security",
+			Subcategory: "Identity and Access Control",
+			Description: "SQL should use Azure AD-only authentication",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armsql.Server)
+				aadOnly := c.Properties != nil && c.Properties.Administrators != nil &&
+					c.Properties.Administrators.AzureADOnlyAuthentication != nil && *c.Properties.Administrators.AzureADOnlyAuthentication
+				return !aadOnly, ""
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/azure-sql/database/authentication-azure-ad-only-authentication",
+		},
 	}
 }
 
@@ -93,12 +103,7 @@ func (a *SQLScanner) GetDatabaseRules() map[string]scanners.AzureRule {
 			Severity:    "Medium",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				service := target.(*armsql.Database)
-				hasDiagnostics, err := a.diagnosticsSettings.HasDiagnostics(*service.ID)
-				if err != nil {
-					log.Fatalf("Error checking diagnostic settings for service %s: %s", *service.Name, err)
-				}
-
-				return !hasDiagnostics, ""
+				return a.diagnosticsSettings.EvalDiagnostics("sqldb-001", *service.ID, scanContext)
 			},
 		},
 		"AvailabilityZones": {
@@ -148,7 +153,7 @@ func (a *SQLScanner) GetDatabaseRules() map[string]scanners.AzureRule {
 			Severity:    "Low",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armsql.Database)
-				caf := strings.HasPrefix(*c.Name, "sqldb")
+				caf := scanners.HasCAFPrefix(*c.Name, "sqldb", scanContext)
 				return !caf, ""
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
@@ -165,5 +170,30 @@ func (a *SQLScanner) GetDatabaseRules() map[string]scanners.AzureRule {
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources?tabs=json",
 		},
+		"sqldb-008": {
+			Id:          "sqldb-008",
+			Category:    "Security",
+			Subcategory: "Encryption",
+			Description: "SQL Database should have transparent data encryption enabled",
+			Severity:    "High",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armsql.Database)
+				// The master database can't be encrypted
+				if strings.EqualFold(*c.Name, "master") {
+					return false, ""
+				}
+				resource, err := arm.ParseResourceID(*c.ID)
+				if err != nil {
+					return scanContext.RuleError("sqldb-008", *c.ID, err)
+				}
+				tde, err := a.getTDE(resource.ResourceGroupName, resource.Parent.Name, *c.Name)
+				if err != nil {
+					return scanContext.RuleError("sqldb-008", *c.ID, fmt.Errorf("getting transparent data encryption: %w", err))
+				}
+				enabled := tde.Properties != nil && tde.Properties.State != nil && *tde.Properties.State == armsql.TransparentDataEncryptionStateEnabled
+				return !enabled, ""
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/azure-sql/database/transparent-data-encryption-tde-overview",
+		},
 	}
 }
@@ -100,6 +100,40 @@ func TestSQLScanner_Rules(t *testing.T) {
 				result: "",
 			},
 		},
+		{
+			name: "SQLScanner Azure AD-only authentication",
+			fields: fields{
+				rule: "sql-009",
+				target: &armsql.Server{
+					Properties: &armsql.ServerProperties{
+						Administrators: &armsql.ServerExternalAdministrator{
+							AzureADOnlyAuthentication: to.BoolPtr(true),
+						},
+					},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "SQLScanner SQL authentication allowed",
+			fields: fields{
+				rule: "sql-009",
+				target: &armsql.Server{
+					Properties: &armsql.ServerProperties{},
+				},
+				scanContext:         &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{},
+			},
+			want: want{
+				broken: true,
+				result: "",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -256,3 +290,70 @@ func TestSQLScanner_DatabaseRules(t *testing.T) {
 		})
 	}
 }
+
+func TestSQLScanner_TransparentDataEncryptionRule(t *testing.T) {
+	type want struct {
+		broken bool
+		result string
+	}
+	tests := []struct {
+		name     string
+		database string
+		state    armsql.TransparentDataEncryptionState
+		want     want
+	}{
+		{
+			name:     "SQLScanner transparent data encryption enabled",
+			database: "sqldb-test",
+			state:    armsql.TransparentDataEncryptionStateEnabled,
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name:     "SQLScanner transparent data encryption disabled",
+			database: "sqldb-test",
+			state:    armsql.TransparentDataEncryptionStateDisabled,
+			want: want{
+				broken: true,
+				result: "",
+			},
+		},
+		{
+			name:     "SQLScanner master database",
+			database: "master",
+			state:    armsql.TransparentDataEncryptionStateDisabled,
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &SQLScanner{
+				getTDEFunc: func(resourceGroupName, serverName, databaseName string) (*armsql.LogicalDatabaseTransparentDataEncryption, error) {
+					state := tt.state
+					return &armsql.LogicalDatabaseTransparentDataEncryption{
+						Properties: &armsql.TransparentDataEncryptionProperties{
+							State: &state,
+						},
+					}, nil
+				},
+			}
+			rules := s.GetDatabaseRules()
+			b, w := rules["sqldb-008"].Eval(&armsql.Database{
+				ID:   to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Sql/servers/sql-test/databases/" + tt.database),
+				Name: to.StringPtr(tt.database),
+			}, &scanners.ScanContext{})
+			got := want{
+				broken: b,
+				result: w,
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SQLScanner Rule.Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
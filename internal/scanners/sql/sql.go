@@ -16,8 +16,10 @@ type SQLScanner struct {
 	diagnosticsSettings scanners.DiagnosticsSettings
 	sqlClient           *armsql.ServersClient
 	sqlDatabasedClient  *armsql.DatabasesClient
+	tdeClient           *armsql.TransparentDataEncryptionsClient
 	listServersFunc     func(resourceGroupName string) ([]*armsql.Server, error)
 	listDatabasesFunc   func(resourceGroupName, serverName string) ([]*armsql.Database, error)
+	getTDEFunc          func(resourceGroupName, serverName, databaseName string) (*armsql.LogicalDatabaseTransparentDataEncryption, error)
 }
 
 // Init - Initializes the SQLScanner
@@ -32,6 +34,10 @@ func (c *SQLScanner) Init(config *scanners.ScannerConfig) error {
 	if err != nil {
 		return err
 	}
+	c.tdeClient, err = armsql.NewTransparentDataEncryptionsClient(config.SubscriptionID, config.Cred, config.ClientOptions)
+	if err != nil {
+		return err
+	}
 	c.diagnosticsSettings = scanners.DiagnosticsSettings{}
 	err = c.diagnosticsSettings.Init(config)
 	if err != nil {
@@ -48,7 +54,7 @@ func (c *SQLScanner) Scan(resourceGroupName string, scanContext *scanners.ScanCo
 	if err != nil {
 		return nil, err
 	}
-	engine := scanners.RuleEngine{}
+	engine := scanners.RuleEngine{Cache: c.config.RuleCache}
 	rules := c.GetRules()
 	databaseRules := c.GetDatabaseRules()
 	results := []scanners.AzureServiceResult{}
@@ -59,9 +65,12 @@ func (c *SQLScanner) Scan(resourceGroupName string, scanContext *scanners.ScanCo
 		results = append(results, scanners.AzureServiceResult{
 			SubscriptionID: c.config.SubscriptionID,
 			ResourceGroup:  resourceGroupName,
+			ResourceID:     *sql.ID,
 			ServiceName:    *sql.Name,
 			Type:           *sql.Type,
 			Location:       *sql.Location,
+			Tags:           sql.Tags,
+			CreatedAt:      scanners.GetCreatedAt(sql),
 			Rules:          rr,
 		})
 
@@ -75,9 +84,12 @@ func (c *SQLScanner) Scan(resourceGroupName string, scanContext *scanners.ScanCo
 			results = append(results, scanners.AzureServiceResult{
 				SubscriptionID: c.config.SubscriptionID,
 				ResourceGroup:  resourceGroupName,
+				ResourceID:     *database.ID,
 				ServiceName:    *database.Name,
 				Type:           *database.Type,
 				Location:       *database.Location,
+				Tags:           database.Tags,
+				CreatedAt:      scanners.GetCreatedAt(database),
 				Rules:          rr,
 			})
 		}
@@ -97,6 +109,9 @@ func (c *SQLScanner) listSQL(resourceGroupName string) ([]*armsql.Server, error)
 				return nil, err
 			}
 			servers = append(servers, resp.Value...)
+			if limited, ok := scanners.LimitResources(c.config, servers); ok {
+				return limited, nil
+			}
 		}
 		return servers, nil
 	}
@@ -121,3 +136,24 @@ func (c *SQLScanner) listDatabases(resourceGroupName, serverName string) ([]*arm
 
 	return c.listDatabasesFunc(resourceGroupName, serverName)
 }
+
+// getTDE - Returns the transparent data encryption configuration of the database
+func (c *SQLScanner) getTDE(resourceGroupName, serverName, databaseName string) (*armsql.LogicalDatabaseTransparentDataEncryption, error) {
+	if c.getTDEFunc == nil {
+		resp, err := c.tdeClient.Get(c.config.Ctx, resourceGroupName, serverName, databaseName, armsql.TransparentDataEncryptionNameCurrent, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &resp.LogicalDatabaseTransparentDataEncryption, nil
+	}
+
+	return c.getTDEFunc(resourceGroupName, serverName, databaseName)
+}
+
+// GetResourceTypes - Returns the resource types scanned by the SQLScanner
+func (c *SQLScanner) GetResourceTypes() []string {
+	return []string{
+		"Microsoft.Sql/servers",
+		"Microsoft.Sql/servers/databases",
+	}
+}
@@ -4,7 +4,6 @@
 package st
 
 import (
-	"log"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
@@ -22,12 +21,7 @@ func (a *StorageScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Medium",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				service := target.(*armstorage.Account)
-				hasDiagnostics, err := a.diagnosticsSettings.HasDiagnostics(*service.ID)
-				if err != nil {
-					log.Fatalf("Error checking diagnostic settings for service %s: %s", *service.Name, err)
-				}
-
-				return !hasDiagnostics, ""
+				return a.diagnosticsSettings.EvalDiagnostics("st-001", *service.ID, scanContext)
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/storage/blobs/monitor-blob-storage",
 		},
@@ -108,7 +102,7 @@ func (a *StorageScanner) GetRules() map[string]scanners.AzureRule {
 			Severity:    "Low",
 			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
 				c := target.(*armstorage.Account)
-				caf := strings.HasPrefix(*c.Name, "st")
+				caf := scanners.HasCAFPrefix(*c.Name, "st", scanContext)
 				return !caf, ""
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/cloud-adoption-framework/ready/azure-best-practices/resource-abbreviations",
@@ -150,5 +144,57 @@ func (a *StorageScanner) GetRules() map[string]scanners.AzureRule {
 			},
 			Url: "https://learn.microsoft.com/en-us/azure/storage/common/transport-layer-security-configure-minimum-version?tabs=portal",
 		},
+		"st-010": {
+			Id:          "st-010",
+			Category:    "Governance",
+			Subcategory: "Lifecycle Management",
+			Description: "Storage Account should have a lifecycle management policy",
+			Severity:    "Low",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armstorage.Account)
+				// Loaded by LoadScanContext, accounts that couldn't be read are already recorded as errors of the rule
+				hasPolicy, ok := scanContext.ManagementPolicies[strings.ToLower(*c.ID)]
+				if !ok {
+					return false, "Unknown"
+				}
+				return !hasPolicy, ""
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/storage/blobs/lifecycle-management-overview",
+		},
+		"st-011": scanners.NewSoftDeleteRule("st-011", "Storage Account blob service", "https://learn.microsoft.com/en-us/azure/storage/blobs/soft-delete-blob-overview",
+			func(target interface{}, scanContext *scanners.ScanContext) (scanners.SoftDeleteStatus, bool) {
+				c := target.(*armstorage.Account)
+				// Loaded by LoadScanContext, FileStorage accounts have no blob service
+				// and accounts that couldn't be read are already recorded as errors of the rule
+				status, ok := scanContext.BlobSoftDelete[strings.ToLower(*c.ID)]
+				return status, ok
+			}),
+		"st-012": {
+			Id:          "st-012",
+			Category:    "Monitoring and Logging",
+			Subcategory: "Diagnostic Logs",
+			Description: "Storage Account blob, queue and table services should have diagnostic settings enabled",
+			Severity:    "Medium",
+			Eval: func(target interface{}, scanContext *scanners.ScanContext) (bool, string) {
+				c := target.(*armstorage.Account)
+				subResources := map[string]string{
+					"blob":  "blobServices/default",
+					"queue": "queueServices/default",
+					"table": "tableServices/default",
+				}
+				if c.Kind != nil {
+					switch *c.Kind {
+					case armstorage.KindFileStorage:
+						return false, ""
+					case armstorage.KindBlobStorage, armstorage.KindBlockBlobStorage:
+						subResources = map[string]string{
+							"blob": "blobServices/default",
+						}
+					}
+				}
+				return a.diagnosticsSettings.EvalSubResourceDiagnostics("st-012", *c.ID, subResources, scanContext)
+			},
+			Url: "https://learn.microsoft.com/en-us/azure/storage/queues/monitor-queue-storage",
+		},
 	}
 }
@@ -4,6 +4,7 @@
 package st
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
@@ -171,6 +172,64 @@ func TestStorageScanner_Rules(t *testing.T) {
 				result: "",
 			},
 		},
+		{
+			name: "StorageScanner service diagnostic settings enabled",
+			fields: fields{
+				rule: "st-012",
+				target: &armstorage.Account{
+					ID: to.StringPtr("test"),
+				},
+				scanContext: &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{
+					HasDiagnosticsFunc: func(resourceId string) (bool, error) {
+						return true, nil
+					},
+				},
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "StorageScanner queue and table diagnostic settings disabled",
+			fields: fields{
+				rule: "st-012",
+				target: &armstorage.Account{
+					ID: to.StringPtr("test"),
+				},
+				scanContext: &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{
+					HasDiagnosticsFunc: func(resourceId string) (bool, error) {
+						return resourceId == "test/blobServices/default", nil
+					},
+				},
+			},
+			want: want{
+				broken: true,
+				result: "queue, table",
+			},
+		},
+		{
+			name: "StorageScanner blob diagnostic settings disabled on a BlockBlobStorage account",
+			fields: fields{
+				rule: "st-012",
+				target: &armstorage.Account{
+					ID:   to.StringPtr("test"),
+					Kind: getBlockBlobStorageKind(),
+				},
+				scanContext: &scanners.ScanContext{},
+				diagnosticsSettings: scanners.DiagnosticsSettings{
+					HasDiagnosticsFunc: func(resourceId string) (bool, error) {
+						return resourceId != "test/blobServices/default", nil
+					},
+				},
+			},
+			want: want{
+				broken: true,
+				result: "blob",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -190,6 +249,211 @@ func TestStorageScanner_Rules(t *testing.T) {
 	}
 }
 
+func TestStorageScanner_ManagementPolicyRule(t *testing.T) {
+	type want struct {
+		broken bool
+		result string
+		errors int
+	}
+	tests := []struct {
+		name      string
+		hasPolicy bool
+		err       error
+		want      want
+	}{
+		{
+			name:      "StorageScanner lifecycle management policy",
+			hasPolicy: true,
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "StorageScanner without lifecycle management policy",
+			want: want{
+				broken: true,
+				result: "",
+			},
+		},
+		{
+			name: "StorageScanner lifecycle management policy can't be read",
+			err:  errors.New("403 Forbidden"),
+			want: want{
+				broken: false,
+				result: "Unknown",
+				errors: 1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account := &armstorage.Account{
+				ID:   to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/sttest"),
+				Name: to.StringPtr("sttest"),
+			}
+			s := &StorageScanner{
+				config: &scanners.ScannerConfig{},
+				listAllStorageFunc: func() ([]*armstorage.Account, error) {
+					return []*armstorage.Account{account}, nil
+				},
+				hasManagementPolicyFunc: func(resourceGroupName string, accountName string) (bool, error) {
+					return tt.hasPolicy, tt.err
+				},
+				getBlobSoftDeleteFunc: func(resourceGroupName string, accountName string) (scanners.SoftDeleteStatus, error) {
+					return scanners.SoftDeleteStatus{}, nil
+				},
+			}
+			scanContext := &scanners.ScanContext{}
+			if err := s.LoadScanContext([]string{"rg"}, scanContext); err != nil {
+				t.Fatalf("LoadScanContext() error = %v", err)
+			}
+			rules := s.GetRules()
+			b, w := rules["st-010"].Eval(account, scanContext)
+			got := want{
+				broken: b,
+				result: w,
+				errors: len(scanContext.RuleErrors()),
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("StorageScanner Rule.Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStorageScanner_BlobSoftDeleteRule(t *testing.T) {
+	type want struct {
+		broken bool
+		result string
+		errors int
+	}
+	tests := []struct {
+		name   string
+		kind   armstorage.Kind
+		status scanners.SoftDeleteStatus
+		err    error
+		want   want
+	}{
+		{
+			name:   "StorageScanner blob soft delete enabled",
+			kind:   armstorage.KindStorageV2,
+			status: scanners.SoftDeleteStatus{Enabled: true, RetentionDays: 7},
+			want: want{
+				broken: false,
+				result: "Enabled, 7 days",
+			},
+		},
+		{
+			name: "StorageScanner blob soft delete disabled",
+			kind: armstorage.KindStorageV2,
+			want: want{
+				broken: true,
+				result: "Disabled",
+			},
+		},
+		{
+			name: "StorageScanner FileStorage account without blob service",
+			kind: armstorage.KindFileStorage,
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "StorageScanner blob service properties can't be read",
+			kind: armstorage.KindStorageV2,
+			err:  errors.New("403 Forbidden"),
+			want: want{
+				broken: false,
+				result: "",
+				errors: 1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account := &armstorage.Account{
+				ID:   to.StringPtr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/sttest"),
+				Name: to.StringPtr("sttest"),
+				Kind: &tt.kind,
+			}
+			s := &StorageScanner{
+				config: &scanners.ScannerConfig{},
+				listAllStorageFunc: func() ([]*armstorage.Account, error) {
+					return []*armstorage.Account{account}, nil
+				},
+				hasManagementPolicyFunc: func(resourceGroupName string, accountName string) (bool, error) {
+					return true, nil
+				},
+				getBlobSoftDeleteFunc: func(resourceGroupName string, accountName string) (scanners.SoftDeleteStatus, error) {
+					if tt.kind == armstorage.KindFileStorage {
+						t.Fatalf("getBlobSoftDelete called for a FileStorage account")
+					}
+					return tt.status, tt.err
+				},
+			}
+			scanContext := &scanners.ScanContext{}
+			if err := s.LoadScanContext([]string{"rg"}, scanContext); err != nil {
+				t.Fatalf("LoadScanContext() error = %v", err)
+			}
+			rules := s.GetRules()
+			b, w := rules["st-011"].Eval(account, scanContext)
+			got := want{
+				broken: b,
+				result: w,
+				errors: len(scanContext.RuleErrors()),
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("StorageScanner Rule.Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStorageScanner_LoadScanContext(t *testing.T) {
+	newAccount := func(rg, name string) *armstorage.Account {
+		return &armstorage.Account{
+			ID:   to.StringPtr("/subscriptions/sub/resourceGroups/" + rg + "/providers/Microsoft.Storage/storageAccounts/" + name),
+			Name: to.StringPtr(name),
+		}
+	}
+	policies := []string{}
+	s := &StorageScanner{
+		config: &scanners.ScannerConfig{Limit: 2},
+		listAllStorageFunc: func() ([]*armstorage.Account, error) {
+			return []*armstorage.Account{
+				newAccount("rg1", "st1"),
+				newAccount("RG1", "st2"),
+				newAccount("rg1", "st3"),
+				newAccount("rg2", "st4"),
+				newAccount("other", "st5"),
+			}, nil
+		},
+		hasManagementPolicyFunc: func(resourceGroupName string, accountName string) (bool, error) {
+			policies = append(policies, accountName)
+			return true, nil
+		},
+		getBlobSoftDeleteFunc: func(resourceGroupName string, accountName string) (scanners.SoftDeleteStatus, error) {
+			return scanners.SoftDeleteStatus{Enabled: true}, nil
+		},
+	}
+
+	scanContext := &scanners.ScanContext{}
+	if err := s.LoadScanContext([]string{"rg1", "rg2"}, scanContext); err != nil {
+		t.Fatalf("LoadScanContext() error = %v", err)
+	}
+	if want := []string{"st1", "st2", "st4"}; !reflect.DeepEqual(policies, want) {
+		t.Errorf("LoadScanContext() read the management policies of %v, want %v once each", policies, want)
+	}
+	if len(scanContext.ManagementPolicies) != 3 || len(scanContext.BlobSoftDelete) != 3 {
+		t.Errorf("LoadScanContext() = %v, %v, want 3 accounts", scanContext.ManagementPolicies, scanContext.BlobSoftDelete)
+	}
+	if !scanContext.ManagementPolicies["/subscriptions/sub/resourcegroups/rg1/providers/microsoft.storage/storageaccounts/st2"] {
+		t.Errorf("LoadScanContext() should key the accounts by lower case id")
+	}
+}
+
 func getPremiumZRSSKU() *armstorage.SKUName {
 	s := armstorage.SKUNamePremiumZRS
 	return &s
@@ -203,4 +467,9 @@ func getHotTier() *armstorage.AccessTier {
 func getTLSVersion() *armstorage.MinimumTLSVersion {
 	s := armstorage.MinimumTLSVersionTLS12
 	return &s
-}
\ No newline at end of file
+}
+
+func getBlockBlobStorageKind() *armstorage.Kind {
+	s := armstorage.KindBlockBlobStorage
+	return &s
+}
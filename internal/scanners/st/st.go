@@ -4,18 +4,29 @@
 package st
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
+	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
 	"github.com/cmendible/azqr/internal/scanners"
 )
 
 // StorageScanner - Scanner for Storage
 type StorageScanner struct {
-	config              *scanners.ScannerConfig
-	diagnosticsSettings scanners.DiagnosticsSettings
-	storageClient       *armstorage.AccountsClient
-	listStorageFunc     func(resourceGroupName string) ([]*armstorage.Account, error)
+	config                   *scanners.ScannerConfig
+	diagnosticsSettings      scanners.DiagnosticsSettings
+	storageClient            *armstorage.AccountsClient
+	managementPoliciesClient *armstorage.ManagementPoliciesClient
+	blobServicesClient       *armstorage.BlobServicesClient
+	listStorageFunc          func(resourceGroupName string) ([]*armstorage.Account, error)
+	listAllStorageFunc       func() ([]*armstorage.Account, error)
+	hasManagementPolicyFunc  func(resourceGroupName string, accountName string) (bool, error)
+	getBlobSoftDeleteFunc    func(resourceGroupName string, accountName string) (scanners.SoftDeleteStatus, error)
 }
 
 // Init - Initializes the StorageScanner
@@ -27,6 +38,14 @@ func (c *StorageScanner) Init(config *scanners.ScannerConfig) error {
 	if err != nil {
 		return err
 	}
+	c.managementPoliciesClient, err = armstorage.NewManagementPoliciesClient(config.SubscriptionID, config.Cred, config.ClientOptions)
+	if err != nil {
+		return err
+	}
+	c.blobServicesClient, err = armstorage.NewBlobServicesClient(config.SubscriptionID, config.Cred, config.ClientOptions)
+	if err != nil {
+		return err
+	}
 	c.diagnosticsSettings = scanners.DiagnosticsSettings{}
 	err = c.diagnosticsSettings.Init(config)
 	if err != nil {
@@ -43,7 +62,7 @@ func (c *StorageScanner) Scan(resourceGroupName string, scanContext *scanners.Sc
 	if err != nil {
 		return nil, err
 	}
-	engine := scanners.RuleEngine{}
+	engine := scanners.RuleEngine{Cache: c.config.RuleCache}
 	rules := c.GetRules()
 	results := []scanners.AzureServiceResult{}
 
@@ -53,9 +72,12 @@ func (c *StorageScanner) Scan(resourceGroupName string, scanContext *scanners.Sc
 		results = append(results, scanners.AzureServiceResult{
 			SubscriptionID: c.config.SubscriptionID,
 			ResourceGroup:  resourceGroupName,
+			ResourceID:     *storage.ID,
 			ServiceName:    *storage.Name,
 			Type:           *storage.Type,
 			Location:       *storage.Location,
+			Tags:           storage.Tags,
+			CreatedAt:      scanners.GetCreatedAt(storage),
 			Rules:          rr,
 		})
 	}
@@ -73,9 +95,130 @@ func (c *StorageScanner) listStorage(resourceGroupName string) ([]*armstorage.Ac
 				return nil, err
 			}
 			staccounts = append(staccounts, resp.Value...)
+			if limited, ok := scanners.LimitResources(c.config, staccounts); ok {
+				return limited, nil
+			}
 		}
 		return staccounts, nil
 	}
 
 	return c.listStorageFunc(resourceGroupName)
 }
+
+// LoadScanContext - Loads whether the storage accounts of the Resource Groups have a lifecycle management policy (st-010)
+// and the soft delete state of their blobs (st-011). Accounts are listed once for the subscription, at most config.Limit
+// per Resource Group as Scan does. Errors reading an account are recorded as errors of the rule, so the scan continues.
+func (c *StorageScanner) LoadScanContext(resourceGroups []string, scanContext *scanners.ScanContext) error {
+	accounts, err := c.listAllStorage()
+	if err != nil {
+		return err
+	}
+
+	inScope := map[string]int{}
+	for _, rg := range resourceGroups {
+		inScope[strings.ToLower(rg)] = 0
+	}
+	if scanContext.ManagementPolicies == nil {
+		scanContext.ManagementPolicies = map[string]bool{}
+	}
+	if scanContext.BlobSoftDelete == nil {
+		scanContext.BlobSoftDelete = map[string]scanners.SoftDeleteStatus{}
+	}
+
+	for _, account := range accounts {
+		resource, err := arm.ParseResourceID(*account.ID)
+		if err != nil {
+			scanContext.RuleError("st-010", *account.ID, err)
+			continue
+		}
+		rg := strings.ToLower(resource.ResourceGroupName)
+		count, ok := inScope[rg]
+		if !ok || (c.config.Limit > 0 && count >= c.config.Limit) {
+			continue
+		}
+		inScope[rg] = count + 1
+
+		id := strings.ToLower(*account.ID)
+		hasPolicy, err := c.hasManagementPolicy(resource.ResourceGroupName, *account.Name)
+		if err != nil {
+			scanContext.RuleError("st-010", *account.ID, fmt.Errorf("reading the management policy: %w", err))
+		} else {
+			scanContext.ManagementPolicies[id] = hasPolicy
+		}
+
+		// FileStorage accounts have no blob service
+		if account.Kind != nil && *account.Kind == armstorage.KindFileStorage {
+			continue
+		}
+		status, err := c.getBlobSoftDelete(resource.ResourceGroupName, *account.Name)
+		if err != nil {
+			scanContext.RuleError("st-011", *account.ID, fmt.Errorf("reading the blob service properties: %w", err))
+		} else {
+			scanContext.BlobSoftDelete[id] = status
+		}
+	}
+	return nil
+}
+
+// listAllStorage - Returns the storage accounts of the subscription
+func (c *StorageScanner) listAllStorage() ([]*armstorage.Account, error) {
+	if c.listAllStorageFunc == nil {
+		pager := c.storageClient.NewListPager(nil)
+
+		staccounts := make([]*armstorage.Account, 0)
+		for pager.More() {
+			resp, err := pager.NextPage(c.config.Ctx)
+			if err != nil {
+				return nil, err
+			}
+			staccounts = append(staccounts, resp.Value...)
+		}
+		return staccounts, nil
+	}
+
+	return c.listAllStorageFunc()
+}
+
+func (c *StorageScanner) hasManagementPolicy(resourceGroupName string, accountName string) (bool, error) {
+	if c.hasManagementPolicyFunc == nil {
+		_, err := c.managementPoliciesClient.Get(c.config.Ctx, resourceGroupName, accountName, armstorage.ManagementPolicyNameDefault, nil)
+		if err != nil {
+			var respErr *azcore.ResponseError
+			if errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	}
+
+	return c.hasManagementPolicyFunc(resourceGroupName, accountName)
+}
+
+// getBlobSoftDelete - Returns the soft delete state of the blobs of the account
+func (c *StorageScanner) getBlobSoftDelete(resourceGroupName string, accountName string) (scanners.SoftDeleteStatus, error) {
+	if c.getBlobSoftDeleteFunc == nil {
+		resp, err := c.blobServicesClient.GetServiceProperties(c.config.Ctx, resourceGroupName, accountName, nil)
+		if err != nil {
+			return scanners.SoftDeleteStatus{}, err
+		}
+		status := scanners.SoftDeleteStatus{}
+		if resp.BlobServiceProperties.BlobServiceProperties != nil {
+			policy := resp.BlobServiceProperties.BlobServiceProperties.DeleteRetentionPolicy
+			if policy != nil && policy.Enabled != nil && *policy.Enabled {
+				status.Enabled = true
+				if policy.Days != nil {
+					status.RetentionDays = int(*policy.Days)
+				}
+			}
+		}
+		return status, nil
+	}
+
+	return c.getBlobSoftDeleteFunc(resourceGroupName, accountName)
+}
+
+// GetResourceTypes - Returns the resource types scanned by the StorageScanner
+func (c *StorageScanner) GetResourceTypes() []string {
+	return []string{"Microsoft.Storage/storageAccounts"}
+}
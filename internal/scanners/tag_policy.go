@@ -0,0 +1,93 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TagPolicyRuleID - Id of the rule added by AddTagPolicyRule
+const TagPolicyRuleID = "TagPolicy"
+
+// TagPolicy - Regular expression the value of a tag must match
+type TagPolicy struct {
+	Key     string
+	Pattern *regexp.Regexp
+}
+
+// ParseTagPolicies - Parses key=regex pairs (e.g. costcenter=^CC\d{4}$) into tag policies sorted by key
+func ParseTagPolicies(pairs []string) ([]TagPolicy, error) {
+	policies := []TagPolicy{}
+	for _, p := range pairs {
+		key, expr, found := strings.Cut(p, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid tag policy %s, expected key=regex", p)
+		}
+		pattern, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex for tag policy %s: %w", key, err)
+		}
+		policies = append(policies, TagPolicy{Key: key, Pattern: pattern})
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].Key < policies[j].Key
+	})
+	return policies, nil
+}
+
+// NewTagPolicyRule - Returns a rule flagging results whose tag values don't match the policies. Missing tags are also flagged.
+func NewTagPolicyRule(policies []TagPolicy) AzureRule {
+	return AzureRule{
+		Id:          TagPolicyRuleID,
+		Category:    "Governance",
+		Subcategory: "Use tags to organize your resources",
+		Description: "Resource tag values should comply with the tag policy",
+		Severity:    "Low",
+		Eval: func(target interface{}, scanContext *ScanContext) (bool, string) {
+			r := target.(AzureServiceResult)
+			failing := []string{}
+			for _, p := range policies {
+				value, ok := getTagValue(r.Tags, p.Key)
+				if !ok {
+					failing = append(failing, fmt.Sprintf("%s: Missing", p.Key))
+					continue
+				}
+				if !p.Pattern.MatchString(value) {
+					failing = append(failing, fmt.Sprintf("%s: %s", p.Key, value))
+				}
+			}
+			return len(failing) > 0, strings.Join(failing, ", ")
+		},
+		Url: "https://learn.microsoft.com/en-us/azure/azure-resource-manager/management/tag-resources",
+	}
+}
+
+// AddTagPolicyRule - Evaluates the tag policy rule against every result. Does nothing when there are no policies.
+func AddTagPolicyRule(results []AzureServiceResult, policies []TagPolicy) {
+	if len(policies) == 0 {
+		return
+	}
+
+	engine := RuleEngine{}
+	rule := NewTagPolicyRule(policies)
+	for i := range results {
+		if results[i].Rules == nil {
+			results[i].Rules = map[string]AzureRuleResult{}
+		}
+		results[i].Rules[TagPolicyRuleID] = engine.EvaluateRule(rule, results[i], nil)
+	}
+}
+
+// getTagValue - Returns the value of the tag. Tag keys are case insensitive.
+func getTagValue(tags map[string]*string, key string) (string, bool) {
+	for k, v := range tags {
+		if strings.EqualFold(k, key) && v != nil {
+			return *v, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,85 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+func TestAddTagPolicyRule(t *testing.T) {
+	type want struct {
+		broken bool
+		result string
+	}
+	tests := []struct {
+		name string
+		tags map[string]*string
+		want want
+	}{
+		{
+			name: "matching values",
+			tags: map[string]*string{
+				"CostCenter": to.StringPtr("CC1234"),
+				"owner":      to.StringPtr("team-a"),
+			},
+			want: want{
+				broken: false,
+				result: "",
+			},
+		},
+		{
+			name: "non matching value",
+			tags: map[string]*string{
+				"costcenter": to.StringPtr("1234"),
+				"owner":      to.StringPtr("team-a"),
+			},
+			want: want{
+				broken: true,
+				result: "costcenter: 1234",
+			},
+		},
+		{
+			name: "missing tag",
+			tags: map[string]*string{
+				"costcenter": to.StringPtr("CC1234"),
+			},
+			want: want{
+				broken: true,
+				result: "owner: Missing",
+			},
+		},
+	}
+	policies, err := ParseTagPolicies([]string{`owner=^team-`, `costcenter=^CC\d{4}$`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := []AzureServiceResult{
+				{
+					Tags:  tt.tags,
+					Rules: map[string]AzureRuleResult{},
+				},
+			}
+			AddTagPolicyRule(results, policies)
+			got, ok := results[0].Rules[TagPolicyRuleID]
+			if !ok {
+				t.Fatalf("AddTagPolicyRule() did not add the %s rule", TagPolicyRuleID)
+			}
+			if got.IsBroken != tt.want.broken || got.Result != tt.want.result {
+				t.Errorf("AddTagPolicyRule() = %v, %s, want %v", got.IsBroken, got.Result, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTagPolicies_Invalid(t *testing.T) {
+	for _, p := range []string{"costcenter", "=^CC$", "costcenter=^CC(\\d$"} {
+		if _, err := ParseTagPolicies([]string{p}); err == nil {
+			t.Errorf("ParseTagPolicies(%s) expected an error", p)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import "strings"
+
+// IsProduction - Returns true if the tags mark the resource as a production resource (e.g. env=prod)
+func IsProduction(tags map[string]*string) bool {
+	_, ok := GetProductionTag(tags)
+	return ok
+}
+
+// GetProductionTag - Returns the tag, as key=value, that marks the resource as a production resource
+func GetProductionTag(tags map[string]*string) (string, bool) {
+	for k, v := range tags {
+		if v == nil {
+			continue
+		}
+		switch strings.ToLower(k) {
+		case "env", "environment":
+			switch strings.ToLower(*v) {
+			case "prod", "production", "prd":
+				return k + "=" + *v, true
+			}
+		}
+	}
+	return "", false
+}
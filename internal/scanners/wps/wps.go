@@ -42,7 +42,7 @@ func (c *WebPubSubScanner) Scan(resourceGroupName string, scanContext *scanners.
 	if err != nil {
 		return nil, err
 	}
-	engine := scanners.RuleEngine{}
+	engine := scanners.RuleEngine{Cache: c.config.RuleCache}
 	rules := c.GetRules()
 	results := []scanners.AzureServiceResult{}
 
@@ -52,9 +52,12 @@ func (c *WebPubSubScanner) Scan(resourceGroupName string, scanContext *scanners.
 		results = append(results, scanners.AzureServiceResult{
 			SubscriptionID: c.config.SubscriptionID,
 			ResourceGroup:  resourceGroupName,
+			ResourceID:     *w.ID,
 			ServiceName:    *w.Name,
 			Type:           *w.Type,
 			Location:       *w.Location,
+			Tags:           w.Tags,
+			CreatedAt:      scanners.GetCreatedAt(w),
 			Rules:          rr,
 		})
 	}
@@ -72,9 +75,17 @@ func (c *WebPubSubScanner) listWebPubSub(resourceGroupName string) ([]*armwebpub
 				return nil, err
 			}
 			WebPubSubs = append(WebPubSubs, resp.Value...)
+			if limited, ok := scanners.LimitResources(c.config, WebPubSubs); ok {
+				return limited, nil
+			}
 		}
 		return WebPubSubs, nil
 	}
 
 	return c.listWebPubSubFunc(resourceGroupName)
 }
+
+// GetResourceTypes - Returns the resource types scanned by the WebPubSubScanner
+func (c *WebPubSubScanner) GetResourceTypes() []string {
+	return []string{"Microsoft.SignalRService/WebPubSub"}
+}
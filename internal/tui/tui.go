@@ -0,0 +1,248 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+// severities - Severity filters, cycled with tab. An empty severity shows every finding
+var severities = []string{"", "High", "Medium", "Low"}
+
+var severityRank = map[string]int{
+	"High":   0,
+	"Medium": 1,
+	"Low":    2,
+}
+
+// finding - A rule result of a resource
+type finding struct {
+	Category      string
+	Subcategory   string
+	Severity      string
+	RuleID        string
+	Description   string
+	Broken        bool
+	Result        string
+	Learn         string
+	Name          string
+	ResourceGroup string
+	ResourceID    string
+	Type          string
+	Location      string
+}
+
+// categoryCount - Number of findings of a category
+type categoryCount struct {
+	Category string
+	Count    int
+}
+
+type view int
+
+const (
+	categoriesView view = iota
+	findingsView
+	detailView
+)
+
+// model - Browses the findings by category, then the findings of the category and finally the details of a finding
+type model struct {
+	findings []finding
+	severity int
+	view     view
+	category string
+	cursor   int
+	selected finding
+}
+
+// Run - Opens the terminal UI to browse the findings of the scan. As in the reports, passed rules are only shown with showPassed, unless they carry a result.
+func Run(results []scanners.AzureServiceResult, showPassed bool) error {
+	_, err := tea.NewProgram(newModel(results, showPassed), tea.WithAltScreen()).Run()
+	return err
+}
+
+func newModel(results []scanners.AzureServiceResult, showPassed bool) model {
+	findings := []finding{}
+	for _, r := range results {
+		for _, rule := range r.Rules {
+			if !rule.IsBroken && !showPassed && rule.Result == "" {
+				continue
+			}
+			findings = append(findings, finding{
+				Category:      rule.Category,
+				Subcategory:   rule.Subcategory,
+				Severity:      rule.Severity,
+				RuleID:        rule.Id,
+				Description:   rule.Description,
+				Broken:        rule.IsBroken,
+				Result:        rule.Result,
+				Learn:         rule.Learn,
+				Name:          r.ServiceName,
+				ResourceGroup: r.ResourceGroup,
+				ResourceID:    r.ResourceID,
+				Type:          r.Type,
+				Location:      r.Location,
+			})
+		}
+	}
+	return model{findings: findings}
+}
+
+// filterFindings - Returns the findings of the severity and category, any if empty, ordered by severity, rule and resource
+func filterFindings(findings []finding, severity, category string) []finding {
+	filtered := []finding{}
+	for _, f := range findings {
+		if severity != "" && f.Severity != severity {
+			continue
+		}
+		if category != "" && f.Category != category {
+			continue
+		}
+		filtered = append(filtered, f)
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		a, b := filtered[i], filtered[j]
+		if severityRank[a.Severity] != severityRank[b.Severity] {
+			return severityRank[a.Severity] < severityRank[b.Severity]
+		}
+		if a.RuleID != b.RuleID {
+			return a.RuleID < b.RuleID
+		}
+		return a.Name < b.Name
+	})
+	return filtered
+}
+
+// countByCategory - Returns the number of findings of the severity, any if empty, in each category ordered by name
+func countByCategory(findings []finding, severity string) []categoryCount {
+	counts := map[string]int{}
+	for _, f := range filterFindings(findings, severity, "") {
+		counts[f.Category]++
+	}
+	result := make([]categoryCount, 0, len(counts))
+	for c, n := range counts {
+		result = append(result, categoryCount{Category: c, Count: n})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Category < result[j].Category
+	})
+	return result
+}
+
+// rows - Returns the number of selectable rows of the current view
+func (m model) rows() int {
+	switch m.view {
+	case categoriesView:
+		return len(countByCategory(m.findings, severities[m.severity]))
+	case findingsView:
+		return len(filterFindings(m.findings, severities[m.severity], m.category))
+	}
+	return 0
+}
+
+func (m model) Init() tea.Cmd {
+	return nil
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch key.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < m.rows()-1 {
+			m.cursor++
+		}
+	case "tab":
+		m.severity = (m.severity + 1) % len(severities)
+		m.cursor = 0
+		if m.view == detailView {
+			m.view = findingsView
+		}
+	case "enter":
+		switch m.view {
+		case categoriesView:
+			categories := countByCategory(m.findings, severities[m.severity])
+			if m.cursor < len(categories) {
+				m.category = categories[m.cursor].Category
+				m.view = findingsView
+				m.cursor = 0
+			}
+		case findingsView:
+			findings := filterFindings(m.findings, severities[m.severity], m.category)
+			if m.cursor < len(findings) {
+				m.selected = findings[m.cursor]
+				m.view = detailView
+			}
+		}
+	case "esc", "backspace":
+		switch m.view {
+		case findingsView:
+			m.view = categoriesView
+			m.category = ""
+			m.cursor = 0
+		case detailView:
+			m.view = findingsView
+		}
+	}
+	return m, nil
+}
+
+func (m model) View() string {
+	b := &strings.Builder{}
+	severity := severities[m.severity]
+	if severity == "" {
+		severity = "All"
+	}
+	fmt.Fprintf(b, "azqr findings - severity: %s\n\n", severity)
+
+	switch m.view {
+	case categoriesView:
+		for i, c := range countByCategory(m.findings, severities[m.severity]) {
+			fmt.Fprintf(b, "%s%s (%d)\n", cursor(i == m.cursor), c.Category, c.Count)
+		}
+	case findingsView:
+		fmt.Fprintf(b, "%s\n\n", m.category)
+		for i, f := range filterFindings(m.findings, severities[m.severity], m.category) {
+			fmt.Fprintf(b, "%s%-6s %-10s %s: %s\n", cursor(i == m.cursor), f.Severity, f.RuleID, f.Name, f.Description)
+		}
+	case detailView:
+		f := m.selected
+		fmt.Fprintf(b, "Rule:           %s - %s\n", f.RuleID, f.Description)
+		fmt.Fprintf(b, "Category:       %s / %s\n", f.Category, f.Subcategory)
+		fmt.Fprintf(b, "Severity:       %s\n", f.Severity)
+		fmt.Fprintf(b, "Broken:         %t\n", f.Broken)
+		fmt.Fprintf(b, "Result:         %s\n", f.Result)
+		fmt.Fprintf(b, "Resource:       %s\n", f.Name)
+		fmt.Fprintf(b, "Type:           %s\n", f.Type)
+		fmt.Fprintf(b, "Resource Group: %s\n", f.ResourceGroup)
+		fmt.Fprintf(b, "Location:       %s\n", f.Location)
+		fmt.Fprintf(b, "Id:             %s\n", f.ResourceID)
+		fmt.Fprintf(b, "Learn:          %s\n", f.Learn)
+	}
+
+	fmt.Fprint(b, "\nup/down: move  enter: open  esc: back  tab: severity  q: quit\n")
+	return b.String()
+}
+
+func cursor(selected bool) string {
+	if selected {
+		return "> "
+	}
+	return "  "
+}
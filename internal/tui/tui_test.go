@@ -0,0 +1,168 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package tui
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+func testFindings() []finding {
+	return []finding{
+		{Category: "Security", Severity: "Low", RuleID: "st-007", Name: "st1"},
+		{Category: "Security", Severity: "High", RuleID: "st-003", Name: "st2"},
+		{Category: "Security", Severity: "High", RuleID: "st-003", Name: "st1"},
+		{Category: "Governance", Severity: "Medium", RuleID: "aks-001", Name: "aks1"},
+		{Category: "Monitoring and Logging", Severity: "High", RuleID: "kv-001", Name: "kv1"},
+	}
+}
+
+func names(findings []finding) []string {
+	result := []string{}
+	for _, f := range findings {
+		result = append(result, f.RuleID+"/"+f.Name)
+	}
+	return result
+}
+
+func TestFilterFindings(t *testing.T) {
+	tests := []struct {
+		name     string
+		severity string
+		category string
+		want     []string
+	}{
+		{
+			name:     "all findings ordered by severity, rule and resource",
+			severity: "",
+			category: "",
+			want:     []string{"kv-001/kv1", "st-003/st1", "st-003/st2", "aks-001/aks1", "st-007/st1"},
+		},
+		{
+			name:     "severity",
+			severity: "High",
+			category: "",
+			want:     []string{"kv-001/kv1", "st-003/st1", "st-003/st2"},
+		},
+		{
+			name:     "category",
+			severity: "",
+			category: "Security",
+			want:     []string{"st-003/st1", "st-003/st2", "st-007/st1"},
+		},
+		{
+			name:     "severity and category",
+			severity: "Low",
+			category: "Security",
+			want:     []string{"st-007/st1"},
+		},
+		{
+			name:     "no findings",
+			severity: "Medium",
+			category: "Security",
+			want:     []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := names(filterFindings(testFindings(), tt.severity, tt.category)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterFindings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountByCategory(t *testing.T) {
+	tests := []struct {
+		name     string
+		severity string
+		want     []categoryCount
+	}{
+		{
+			name:     "all severities",
+			severity: "",
+			want: []categoryCount{
+				{Category: "Governance", Count: 1},
+				{Category: "Monitoring and Logging", Count: 1},
+				{Category: "Security", Count: 3},
+			},
+		},
+		{
+			name:     "high severity",
+			severity: "High",
+			want: []categoryCount{
+				{Category: "Monitoring and Logging", Count: 1},
+				{Category: "Security", Count: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countByCategory(testFindings(), tt.severity); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("countByCategory() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewModel(t *testing.T) {
+	results := []scanners.AzureServiceResult{
+		{
+			ServiceName: "st1",
+			Rules: map[string]scanners.AzureRuleResult{
+				"st-001": {Id: "st-001", Category: "Security", Severity: "High", IsBroken: true},
+				"st-002": {Id: "st-002", Category: "Security", Severity: "Low", IsBroken: false},
+				"st-003": {Id: "st-003", Category: "Reliability", Severity: "Low", IsBroken: false, Result: "Standard_LRS"},
+			},
+		},
+	}
+
+	if got := len(newModel(results, false).findings); got != 2 {
+		t.Errorf("newModel() without passed rules has %d findings, want 2", got)
+	}
+	if got := len(newModel(results, true).findings); got != 3 {
+		t.Errorf("newModel() with passed rules has %d findings, want 3", got)
+	}
+}
+
+func TestModel_Update(t *testing.T) {
+	press := func(m model, key tea.KeyType) model {
+		updated, _ := m.Update(tea.KeyMsg{Type: key})
+		return updated.(model)
+	}
+
+	m := model{findings: testFindings()}
+
+	// Security is the third category
+	m = press(m, tea.KeyDown)
+	m = press(m, tea.KeyDown)
+	m = press(m, tea.KeyDown)
+	if m.cursor != 2 {
+		t.Fatalf("cursor = %d, want 2", m.cursor)
+	}
+
+	m = press(m, tea.KeyEnter)
+	if m.view != findingsView || m.category != "Security" {
+		t.Fatalf("view = %v, category = %s, want findings of Security", m.view, m.category)
+	}
+
+	m = press(m, tea.KeyEnter)
+	if m.view != detailView || m.selected.RuleID != "st-003" || m.selected.Name != "st1" {
+		t.Fatalf("selected = %v, want st-003/st1", m.selected)
+	}
+
+	m = press(m, tea.KeyEsc)
+	m = press(m, tea.KeyTab)
+	if severities[m.severity] != "High" || m.rows() != 2 {
+		t.Errorf("severity = %s with %d rows, want High with 2 rows", severities[m.severity], m.rows())
+	}
+
+	m = press(m, tea.KeyEsc)
+	if m.view != categoriesView || m.category != "" {
+		t.Errorf("view = %v, category = %s, want categories", m.view, m.category)
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Package renderers - Public registry of the report renderers, to add report formats when embedding azqr
+package renderers
+
+import (
+	"github.com/cmendible/azqr/internal/renderers"
+)
+
+type (
+	// Renderer - Interface for all report renderers
+	Renderer = renderers.Renderer
+	// ReportData - Data of the scan passed to the renderers
+	ReportData = renderers.ReportData
+)
+
+// Register - Makes a renderer available by name to the --output-format flag of the scan command.
+// Register panics if the renderer is nil or if it's called twice with the same name.
+func Register(name string, renderer Renderer) {
+	renderers.Register(name, renderer)
+}
+
+// Get - Returns the renderer registered with the given name
+func Get(name string) (Renderer, bool) {
+	return renderers.Get(name)
+}
+
+// Names - Returns the sorted names of the registered renderers
+func Names() []string {
+	return renderers.Names()
+}
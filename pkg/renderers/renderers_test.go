@@ -0,0 +1,44 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package renderers
+
+import (
+	"testing"
+
+	"github.com/cmendible/azqr/pkg/scanners"
+)
+
+type fakeRenderer struct {
+	rendered []scanners.AzureServiceResult
+}
+
+func (f *fakeRenderer) Render(data ReportData) error {
+	f.rendered = append(f.rendered, data.MainData...)
+	return nil
+}
+
+func TestRegister(t *testing.T) {
+	fake := &fakeRenderer{}
+	Register("public-fake", fake)
+
+	r, exists := Get("public-fake")
+	if !exists {
+		t.Fatal("Get() public-fake renderer not registered")
+	}
+	data := ReportData{MainData: []scanners.AzureServiceResult{{ServiceName: "evh"}}}
+	if err := r.Render(data); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if len(fake.rendered) != 1 || fake.rendered[0].ServiceName != "evh" {
+		t.Errorf("Render() rendered = %v, want the results of the report", fake.rendered)
+	}
+
+	found := false
+	for _, name := range Names() {
+		found = found || name == "public-fake"
+	}
+	if !found {
+		t.Errorf("Names() = %v, want public-fake", Names())
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cmendible/azqr/internal/scanners/afd"
+	"github.com/cmendible/azqr/internal/scanners/afw"
+	"github.com/cmendible/azqr/internal/scanners/agw"
+	"github.com/cmendible/azqr/internal/scanners/aks"
+	"github.com/cmendible/azqr/internal/scanners/apim"
+	"github.com/cmendible/azqr/internal/scanners/appcs"
+	"github.com/cmendible/azqr/internal/scanners/cae"
+	"github.com/cmendible/azqr/internal/scanners/ci"
+	"github.com/cmendible/azqr/internal/scanners/cosmos"
+	"github.com/cmendible/azqr/internal/scanners/cr"
+	"github.com/cmendible/azqr/internal/scanners/evgd"
+	"github.com/cmendible/azqr/internal/scanners/evh"
+	"github.com/cmendible/azqr/internal/scanners/kv"
+	"github.com/cmendible/azqr/internal/scanners/mysql"
+	"github.com/cmendible/azqr/internal/scanners/plan"
+	"github.com/cmendible/azqr/internal/scanners/psql"
+	"github.com/cmendible/azqr/internal/scanners/redis"
+	"github.com/cmendible/azqr/internal/scanners/sb"
+	"github.com/cmendible/azqr/internal/scanners/sigr"
+	"github.com/cmendible/azqr/internal/scanners/sql"
+	"github.com/cmendible/azqr/internal/scanners/st"
+	"github.com/cmendible/azqr/internal/scanners/wps"
+)
+
+// registry - Supported services, keyed by service code, in the order they are scanned
+var registry = []struct {
+	key        string
+	newScanner func() IAzureScanner
+}{
+	{"aks", func() IAzureScanner { return &aks.AKSScanner{} }},
+	{"apim", func() IAzureScanner { return &apim.APIManagementScanner{} }},
+	{"agw", func() IAzureScanner { return &agw.ApplicationGatewayScanner{} }},
+	{"cae", func() IAzureScanner { return &cae.ContainerAppsScanner{} }},
+	{"ci", func() IAzureScanner { return &ci.ContainerInstanceScanner{} }},
+	{"cosmos", func() IAzureScanner { return &cosmos.CosmosDBScanner{} }},
+	{"cr", func() IAzureScanner { return &cr.ContainerRegistryScanner{} }},
+	{"evh", func() IAzureScanner { return &evh.EventHubScanner{} }},
+	{"evgd", func() IAzureScanner { return &evgd.EventGridScanner{} }},
+	{"kv", func() IAzureScanner { return &kv.KeyVaultScanner{} }},
+	{"appcs", func() IAzureScanner { return &appcs.AppConfigurationScanner{} }},
+	{"plan", func() IAzureScanner { return &plan.AppServiceScanner{} }},
+	{"redis", func() IAzureScanner { return &redis.RedisScanner{} }},
+	{"sb", func() IAzureScanner { return &sb.ServiceBusScanner{} }},
+	{"sigr", func() IAzureScanner { return &sigr.SignalRScanner{} }},
+	{"wps", func() IAzureScanner { return &wps.WebPubSubScanner{} }},
+	{"st", func() IAzureScanner { return &st.StorageScanner{} }},
+	{"psql", func() IAzureScanner { return &psql.PostgreScanner{} }},
+	{"psqlf", func() IAzureScanner { return &psql.PostgreFlexibleScanner{} }},
+	{"sql", func() IAzureScanner { return &sql.SQLScanner{} }},
+	{"afd", func() IAzureScanner { return &afd.FrontDoorScanner{} }},
+	{"afw", func() IAzureScanner { return &afw.FirewallScanner{} }},
+	{"mysql", func() IAzureScanner { return &mysql.MySQLScanner{} }},
+	{"mysqlf", func() IAzureScanner { return &mysql.MySQLFlexibleScanner{} }},
+}
+
+// Services - Returns the codes of the supported services (e.g. aks, evh), in the order they are scanned
+func Services() []string {
+	services := make([]string, 0, len(registry))
+	for _, r := range registry {
+		services = append(services, r.key)
+	}
+	return services
+}
+
+// NewScanner - Returns a new scanner for the service code (e.g. evh)
+func NewScanner(service string) (IAzureScanner, error) {
+	service = strings.ToLower(strings.TrimSpace(service))
+	for _, r := range registry {
+		if r.key == service {
+			return r.newScanner(), nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported service: %s", service)
+}
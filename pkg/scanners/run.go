@@ -0,0 +1,99 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+// Scope - Subscription and Resource Group scanned by Run
+type Scope struct {
+	SubscriptionID string
+	ResourceGroup  string
+}
+
+// RunOptions - Options used by Run to initialize and run a scanner
+type RunOptions struct {
+	Cred                    azcore.TokenCredential
+	ClientOptions           *arm.ClientOptions
+	MinRetentionDays        int
+	ConsumerGroupsThreshold int
+	// Limit of resources evaluated by the scanner. 0 means no limit.
+	Limit int
+	// ScanContext shared by the scanners. When nil an empty context is used. Its PrivateEndpoints are listed
+	// for the subscription in scope when nil, so share a ScanContext only between scanners of the same subscription.
+	// Rules that couldn't be evaluated are reported by its RuleErrors.
+	ScanContext *ScanContext
+	// WithMetrics enables the rules based on Azure Monitor metrics
+	WithMetrics bool
+	// ProductionSeverity lowers the severity of rules meant for production resources when the resource isn't tagged as production
+	ProductionSeverity bool
+	// RuleCache reuses the results of the rules that call Azure APIs for resources evaluated again, e.g. by another Run sharing it.
+	// When nil rules are always evaluated.
+	RuleCache *RuleCache
+}
+
+// listPrivateEndpoints - Returns the ids of the resources with private endpoints in the subscription of the config
+var listPrivateEndpoints = func(config *ScannerConfig) (map[string]bool, error) {
+	pe := scanners.PrivateEndpointScanner{}
+	if err := pe.Init(config); err != nil {
+		return nil, err
+	}
+	return pe.ListResourcesWithPrivateEndpoints()
+}
+
+// newScannerConfig - Returns the config of the scanners of the subscription
+func newScannerConfig(ctx context.Context, subscriptionID string, opts RunOptions) *ScannerConfig {
+	return &ScannerConfig{
+		Ctx:                     ctx,
+		Cred:                    opts.Cred,
+		SubscriptionID:          subscriptionID,
+		ClientOptions:           opts.ClientOptions,
+		MinRetentionDays:        opts.MinRetentionDays,
+		ConsumerGroupsThreshold: opts.ConsumerGroupsThreshold,
+		Limit:                   opts.Limit,
+		WithMetrics:             opts.WithMetrics,
+		ProductionSeverity:      opts.ProductionSeverity,
+		RuleCache:               opts.RuleCache,
+	}
+}
+
+// Init - Initializes the scanner for the subscription. Once initialized, a scanner can scan the Resource Groups
+// of the subscription concurrently, sharing a ScanContext whose PrivateEndpoints are already listed and, for scanners
+// implementing IScanContextLoader, loaded with LoadScanContext for those Resource Groups.
+func Init(ctx context.Context, scanner IAzureScanner, subscriptionID string, opts RunOptions) error {
+	return scanner.Init(newScannerConfig(ctx, subscriptionID, opts))
+}
+
+// Run - Initializes the scanner for the subscription in scope and scans the resource group in scope
+func Run(ctx context.Context, scanner IAzureScanner, scope Scope, opts RunOptions) ([]AzureServiceResult, error) {
+	config := newScannerConfig(ctx, scope.SubscriptionID, opts)
+	if err := scanner.Init(config); err != nil {
+		return nil, err
+	}
+
+	scanContext := opts.ScanContext
+	if scanContext == nil {
+		scanContext = &ScanContext{}
+	}
+	// Rules checking private endpoints need them, the CLI lists them once per subscription
+	if scanContext.PrivateEndpoints == nil {
+		pe, err := listPrivateEndpoints(config)
+		if err != nil {
+			return nil, err
+		}
+		scanContext.PrivateEndpoints = pe
+	}
+	// Rules reading data listed up front, the CLI loads it once per subscription
+	if loader, ok := scanner.(IScanContextLoader); ok {
+		if err := loader.LoadScanContext([]string{scope.ResourceGroup}, scanContext); err != nil {
+			return nil, err
+		}
+	}
+	return scanner.Scan(scope.ResourceGroup, scanContext)
+}
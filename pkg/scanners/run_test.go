@@ -0,0 +1,164 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package scanners
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/cmendible/azqr/internal/scanners/evh"
+)
+
+type fakeEventHubScanner struct {
+	config      *ScannerConfig
+	scanContext *ScanContext
+	initErr     error
+}
+
+func (f *fakeEventHubScanner) Init(config *ScannerConfig) error {
+	f.config = config
+	return f.initErr
+}
+
+func (f *fakeEventHubScanner) GetRules() map[string]AzureRule {
+	return map[string]AzureRule{}
+}
+
+func (f *fakeEventHubScanner) GetResourceTypes() []string {
+	return []string{"Microsoft.EventHub/namespaces"}
+}
+
+func (f *fakeEventHubScanner) Scan(resourceGroupName string, scanContext *ScanContext) ([]AzureServiceResult, error) {
+	f.scanContext = scanContext
+	return []AzureServiceResult{
+		{
+			SubscriptionID: f.config.SubscriptionID,
+			ResourceGroup:  resourceGroupName,
+			Type:           "Microsoft.EventHub/namespaces",
+			ServiceName:    "evh",
+		},
+	}, nil
+}
+
+// fakePrivateEndpoints - Replaces the listing of private endpoints for the duration of the test
+func fakePrivateEndpoints(t *testing.T, pe map[string]bool) *int {
+	calls := 0
+	list := listPrivateEndpoints
+	listPrivateEndpoints = func(config *ScannerConfig) (map[string]bool, error) {
+		calls++
+		return pe, nil
+	}
+	t.Cleanup(func() { listPrivateEndpoints = list })
+	return &calls
+}
+
+func TestRun(t *testing.T) {
+	pe := map[string]bool{"/subscriptions/sub/resourcegroups/rg/providers/microsoft.eventhub/namespaces/evh": true}
+	calls := fakePrivateEndpoints(t, pe)
+
+	scanner := &fakeEventHubScanner{}
+	scope := Scope{SubscriptionID: "sub", ResourceGroup: "rg"}
+	opts := RunOptions{ConsumerGroupsThreshold: 5}
+
+	got, err := Run(context.Background(), scanner, scope, opts)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := []AzureServiceResult{
+		{
+			SubscriptionID: "sub",
+			ResourceGroup:  "rg",
+			Type:           "Microsoft.EventHub/namespaces",
+			ServiceName:    "evh",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Run() = %v, want %v", got, want)
+	}
+	if scanner.config.ConsumerGroupsThreshold != 5 {
+		t.Errorf("Run() ConsumerGroupsThreshold = %d, want 5", scanner.config.ConsumerGroupsThreshold)
+	}
+	if *calls != 1 || !reflect.DeepEqual(scanner.scanContext.PrivateEndpoints, pe) {
+		t.Errorf("Run() PrivateEndpoints = %v, want the private endpoints of the subscription", scanner.scanContext.PrivateEndpoints)
+	}
+}
+
+func TestRun_SharedScanContext(t *testing.T) {
+	calls := fakePrivateEndpoints(t, map[string]bool{})
+
+	scanContext := &ScanContext{}
+	for _, rg := range []string{"rg1", "rg2"} {
+		_, err := Run(context.Background(), &fakeEventHubScanner{}, Scope{SubscriptionID: "sub", ResourceGroup: rg}, RunOptions{ScanContext: scanContext})
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	}
+
+	if *calls != 1 {
+		t.Errorf("Run() listed private endpoints %d times, want once for the shared ScanContext", *calls)
+	}
+}
+
+// fakeLoaderScanner - Records the Resource Groups it loads its data for
+type fakeLoaderScanner struct {
+	fakeEventHubScanner
+	resourceGroups []string
+}
+
+func (f *fakeLoaderScanner) LoadScanContext(resourceGroups []string, scanContext *ScanContext) error {
+	f.resourceGroups = append(f.resourceGroups, resourceGroups...)
+	return nil
+}
+
+func TestRun_LoadScanContext(t *testing.T) {
+	fakePrivateEndpoints(t, map[string]bool{})
+
+	scanner := &fakeLoaderScanner{}
+	if _, err := Run(context.Background(), scanner, Scope{SubscriptionID: "sub", ResourceGroup: "rg"}, RunOptions{}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !reflect.DeepEqual(scanner.resourceGroups, []string{"rg"}) {
+		t.Errorf("Run() loaded the ScanContext for %v, want the resource group in scope", scanner.resourceGroups)
+	}
+}
+
+func TestRun_InitError(t *testing.T) {
+	initErr := errors.New("init failed")
+	scanner := &fakeEventHubScanner{initErr: initErr}
+
+	_, err := Run(context.Background(), scanner, Scope{SubscriptionID: "sub", ResourceGroup: "rg"}, RunOptions{})
+	if !errors.Is(err, initErr) {
+		t.Errorf("Run() error = %v, want %v", err, initErr)
+	}
+}
+
+func TestInit(t *testing.T) {
+	scanner := &fakeEventHubScanner{}
+	if err := Init(context.Background(), scanner, "sub", RunOptions{Limit: 3, WithMetrics: true}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if scanner.config.SubscriptionID != "sub" || scanner.config.Limit != 3 || !scanner.config.WithMetrics {
+		t.Errorf("Init() config = %+v, want the subscription and options", scanner.config)
+	}
+}
+
+func TestNewScanner(t *testing.T) {
+	scanner, err := NewScanner("EVH")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := scanner.(*evh.EventHubScanner); !ok {
+		t.Errorf("NewScanner(evh) = %T, want *evh.EventHubScanner", scanner)
+	}
+
+	if _, err := NewScanner("unknown"); err == nil {
+		t.Error("NewScanner(unknown) should return an error")
+	}
+	if len(Services()) == 0 || Services()[0] != "aks" {
+		t.Errorf("Services() = %v, want the services in scan order", Services())
+	}
+}
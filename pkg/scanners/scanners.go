@@ -0,0 +1,39 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Package scanners - Public API to run azqr scanners from other Go programs
+package scanners
+
+import (
+	"github.com/cmendible/azqr/internal/scanners"
+)
+
+type (
+	// IAzureScanner - Interface for all Azure Scanners
+	IAzureScanner = scanners.IAzureScanner
+	// IScanContextLoader - Implemented by scanners loading, before scanning, data their rules read from the ScanContext
+	IScanContextLoader = scanners.IScanContextLoader
+	// ScannerConfig - Configuration passed by Run to the scanner
+	ScannerConfig = scanners.ScannerConfig
+	// ScanContext - Data shared by the scanners of a subscription
+	ScanContext = scanners.ScanContext
+	// AzureServiceResult - Result of a scanned resource
+	AzureServiceResult = scanners.AzureServiceResult
+	// AzureRuleResult - Result of a rule evaluated for a resource
+	AzureRuleResult = scanners.AzureRuleResult
+	// AzureRule - Rule evaluated by a scanner
+	AzureRule = scanners.AzureRule
+	// DefenderResult - Status of a Microsoft Defender plan
+	DefenderResult = scanners.DefenderResult
+	// AdvisorResult - Azure Advisor recommendation
+	AdvisorResult = scanners.AdvisorResult
+	// ScanError - Error of a scanner that failed in a Resource Group
+	ScanError = scanners.ScanError
+	// RuleCache - Reuses the results of the rules that call Azure APIs for resources evaluated again, see RunOptions
+	RuleCache = scanners.RuleCache
+)
+
+// NewRuleCache - Creates an empty RuleCache, safe to share between concurrent scans
+func NewRuleCache() *RuleCache {
+	return scanners.NewRuleCache()
+}